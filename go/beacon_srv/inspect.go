@@ -0,0 +1,176 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/scionproto/scion/go/beacon_srv/internal/beacon"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/env"
+	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/util"
+)
+
+var (
+	inspectBeacons bool
+	explainPolicy  string
+	explainEgress  string
+)
+
+// runInspect lists the beacons in the beacon DB, or explains the selection of
+// the configured policy, and writes the result to stdout.
+func runInspect() int {
+	if err := inspect(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Err: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+func inspect(w io.Writer) error {
+	if _, err := toml.DecodeFile(env.ConfigFile(), &cfg); err != nil {
+		return err
+	}
+	cfg.InitDefaults()
+	if err := cfg.BeaconDB.Validate(); err != nil {
+		return common.NewBasicError("Unable to validate beacon DB config", err)
+	}
+	topo, err := topology.LoadFromFile(cfg.General.Topology)
+	if err != nil {
+		return common.NewBasicError("Unable to load topology", err)
+	}
+	db, err := cfg.BeaconDB.New(topo.ISD_AS)
+	if err != nil {
+		return common.NewBasicError("Unable to open beacon DB", err)
+	}
+	defer db.Close()
+	ctx, cancelF := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelF()
+	if explainPolicy == "" {
+		infos, err := beacon.ListBeacons(ctx, db)
+		if err != nil {
+			return err
+		}
+		writeBeacons(w, infos)
+		return nil
+	}
+	policy, err := loadExplainPolicy(topo.Core)
+	if err != nil {
+		return err
+	}
+	var egress *beacon.EgressPolicy
+	if explainEgress != "" {
+		for i := range policy.EgressPolicies {
+			if policy.EgressPolicies[i].Name == explainEgress {
+				egress = &policy.EgressPolicies[i]
+			}
+		}
+		if egress == nil {
+			return common.NewBasicError("Egress policy not found", nil,
+				"name", explainEgress, "type", policy.Type)
+		}
+	}
+	expls, err := beacon.Explain(ctx, db, policy, egress, topo.Core)
+	if err != nil {
+		return err
+	}
+	writeExplanations(w, expls)
+	return nil
+}
+
+// loadExplainPolicy loads the configured policy of the type that is
+// explained.
+func loadExplainPolicy(core bool) (beacon.Policy, error) {
+	t := beacon.PolicyType(explainPolicy)
+	if core {
+		policies, err := loadCorePolicies(cfg.BS.Policies)
+		if err != nil {
+			return beacon.Policy{}, err
+		}
+		policies.InitDefaults()
+		switch t {
+		case beacon.PropPolicy:
+			return policies.Prop, nil
+		case beacon.CoreRegPolicy:
+			return policies.CoreReg, nil
+		}
+		return beacon.Policy{}, common.NewBasicError("Unsupported policy type in core AS", nil,
+			"type", t)
+	}
+	policies, err := loadPolicies(cfg.BS.Policies)
+	if err != nil {
+		return beacon.Policy{}, err
+	}
+	policies.InitDefaults()
+	switch t {
+	case beacon.PropPolicy:
+		return policies.Prop, nil
+	case beacon.UpRegPolicy:
+		return policies.UpReg, nil
+	case beacon.DownRegPolicy:
+		return policies.DownReg, nil
+	}
+	return beacon.Policy{}, common.NewBasicError("Unsupported policy type in non-core AS", nil,
+		"type", t)
+}
+
+func writeBeacons(w io.Writer, infos []beacon.BeaconInfo) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "StartIA\tHops\tIngress\tUsage\tExpiry\tSegment")
+	for _, info := range infos {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\n", info.Segment.FirstIA(),
+			len(info.Segment.ASEntries), info.InIfId, usageFlags(info.Usage),
+			util.TimeToString(info.Segment.MaxExpiry()), info.Segment)
+	}
+	tw.Flush()
+}
+
+func writeExplanations(w io.Writer, expls []beacon.Explanation) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "StartIA\tHops\tIngress\tSelected\tReason\tSegment")
+	for _, e := range expls {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%t\t%s\t%s\n", e.Segment.FirstIA(),
+			len(e.Segment.ASEntries), e.InIfId, e.Selected, e.Reason, e.Segment)
+	}
+	tw.Flush()
+}
+
+// usageFlags returns a compact representation of the usage, e.g., "PUD-" for
+// a beacon that can be propagated and registered as up and down segment.
+func usageFlags(u beacon.Usage) string {
+	flags := []byte("----")
+	for i, f := range []struct {
+		usage beacon.Usage
+		flag  byte
+	}{
+		{beacon.UsageProp, 'P'},
+		{beacon.UsageUpReg, 'U'},
+		{beacon.UsageDownReg, 'D'},
+		{beacon.UsageCoreReg, 'C'},
+	} {
+		if u&f.usage != 0 {
+			flags[i] = f.flag
+		}
+	}
+	return string(flags)
+}
@@ -0,0 +1,196 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beacon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+)
+
+// allUsages contains all usages a beacon can be stored with.
+var allUsages = []Usage{UsageProp, UsageUpReg, UsageDownReg, UsageCoreReg}
+
+// BeaconInfo is a beacon stored in the beacon DB together with its usage.
+type BeaconInfo struct {
+	Beacon
+	// ID is the segment ID of the beacon.
+	ID common.RawBytes
+	// Usage indicates what the beacon is allowed to be used for.
+	Usage Usage
+}
+
+// ListBeacons returns all beacons in the database that are not revoked. The
+// beacons are ordered by source, segment length and segment ID.
+func ListBeacons(ctx context.Context, db DBRead) ([]BeaconInfo, error) {
+	var infos []BeaconInfo
+	idx := make(map[string]int)
+	for _, usage := range allUsages {
+		beacons, err := db.CandidateBeacons(ctx, math.MaxInt32, usage, addr.IA{})
+		if err != nil {
+			return nil, err
+		}
+		var errs []error
+		for res := range beacons {
+			if res.Err != nil {
+				errs = append(errs, res.Err)
+				continue
+			}
+			id, err := res.Beacon.Segment.ID()
+			if err != nil {
+				errs = append(errs, common.NewBasicError("Unable to compute segment ID", err))
+				continue
+			}
+			if i, ok := idx[string(id)]; ok {
+				infos[i].Usage |= usage
+				continue
+			}
+			idx[string(id)] = len(infos)
+			infos = append(infos, BeaconInfo{Beacon: res.Beacon, ID: id, Usage: usage})
+		}
+		if len(errs) > 0 {
+			return nil, common.NewBasicError("Unable to read beacons", nil,
+				"usage", usage, "errs", errs)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		a, b := infos[i], infos[j]
+		if srcA, srcB := a.Segment.FirstIA(), b.Segment.FirstIA(); !srcA.Equal(srcB) {
+			return srcA.IAInt() < srcB.IAInt()
+		}
+		if la, lb := len(a.Segment.ASEntries), len(b.Segment.ASEntries); la != lb {
+			return la < lb
+		}
+		return bytes.Compare(a.ID, b.ID) < 0
+	})
+	return infos, nil
+}
+
+// Explanation explains why a beacon was selected or rejected by a policy.
+type Explanation struct {
+	BeaconInfo
+	// Selected indicates whether the beacon was selected.
+	Selected bool
+	// Reason describes why the beacon was selected or rejected.
+	Reason string
+}
+
+// Explain runs the selection for the policy on all beacons in the database
+// that are not revoked and are usable for the policy type, and explains for
+// each beacon why it was selected or rejected. If egress is set, the
+// selection for the interfaces the egress policy applies to is explained. If
+// perSource is set, the beacons are selected per source AS, as is done in a
+// core AS.
+func Explain(ctx context.Context, db DBRead, policy Policy, egress *EgressPolicy,
+	perSource bool) ([]Explanation, error) {
+
+	all, err := ListBeacons(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	// Only the beacons with the usage of the policy type are candidates, the
+	// same as in the store.
+	usage := UsageFromPolicyType(policy.Type)
+	var infos []BeaconInfo
+	for _, info := range all {
+		if info.Usage&usage != 0 {
+			infos = append(infos, info)
+		}
+	}
+	if egress != nil {
+		policy.BestSetSize = egress.BestSetSize
+	}
+	// ListBeacons orders the beacons by source, which keeps the groups
+	// contiguous.
+	var groups [][]BeaconInfo
+	for i, info := range infos {
+		if i == 0 || (perSource && !info.Segment.FirstIA().Equal(infos[i-1].Segment.FirstIA())) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], info)
+	}
+	if !perSource {
+		for _, group := range groups {
+			sortByLength(group)
+		}
+	}
+	var explanations []Explanation
+	for _, group := range groups {
+		explanations = append(explanations, explainGroup(group, policy, egress)...)
+	}
+	return explanations, nil
+}
+
+// explainGroup explains the selection of the beacons in the group, which are
+// ordered by segment length.
+func explainGroup(infos []BeaconInfo, policy Policy, egress *EgressPolicy) []Explanation {
+	explanations := make([]Explanation, len(infos))
+	var candidates []int
+	for i, info := range infos {
+		explanations[i].BeaconInfo = info
+		if err := policy.Filter.Apply(info.Beacon); err != nil {
+			explanations[i].Reason = fmt.Sprintf("Rejected by policy filter: %s", err)
+			continue
+		}
+		if egress != nil {
+			if err := egress.Filter.Apply(info.Beacon); err != nil {
+				explanations[i].Reason = fmt.Sprintf("Rejected by egress policy %s filter: %s",
+					egress.Name, err)
+				continue
+			}
+		}
+		if len(candidates) >= policy.CandidateSetSize {
+			explanations[i].Reason = fmt.Sprintf("Not in candidate set: %d shorter candidates",
+				policy.CandidateSetSize)
+			continue
+		}
+		candidates = append(candidates, i)
+	}
+	beacons := make(chan BeaconOrErr, len(candidates))
+	for _, i := range candidates {
+		beacons <- BeaconOrErr{Beacon: infos[i].Beacon}
+	}
+	close(beacons)
+	results := make(chan BeaconOrErr, len(candidates)+1)
+	baseAlgo{}.SelectAndServe(beacons, results, policy.BestSetSize)
+	close(results)
+	selected := make(map[*seg.PathSegment]bool)
+	for res := range results {
+		selected[res.Beacon.Segment] = true
+	}
+	for _, i := range candidates {
+		if selected[infos[i].Segment] {
+			explanations[i].Selected = true
+			explanations[i].Reason = fmt.Sprintf("Selected: among the best %d beacons",
+				policy.BestSetSize)
+			continue
+		}
+		explanations[i].Reason = fmt.Sprintf("Not selected: selection algorithm preferred "+
+			"shorter or more diverse beacons (best set size %d)", policy.BestSetSize)
+	}
+	return explanations
+}
+
+func sortByLength(infos []BeaconInfo) {
+	sort.SliceStable(infos, func(i, j int) bool {
+		return len(infos[i].Segment.ASEntries) < len(infos[j].Segment.ASEntries)
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beacon_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/beacon_srv/internal/beacon"
+	"github.com/scionproto/scion/go/beacon_srv/internal/beacon/mock_beacon"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+)
+
+func TestListBeaconsAndExplain(t *testing.T) {
+	Convey("Given a beacon DB", t, func() {
+		mctrl := gomock.NewController(t)
+		defer mctrl.Finish()
+		g := graph.NewDefaultGraph(mctrl)
+
+		stub := graph.If_111_A_112_X
+		direct := testBeaconOrErr(g, graph.If_120_X_111_B, stub)
+		via130 := testBeaconOrErr(g, graph.If_130_B_120_A, graph.If_120_X_111_B, stub)
+		stored := map[beacon.Usage][]beacon.BeaconOrErr{
+			beacon.UsageProp:  {direct, via130},
+			beacon.UsageUpReg: {direct},
+		}
+		db := mock_beacon.NewMockDB(mctrl)
+		db.EXPECT().CandidateBeacons(gomock.Any(), gomock.Any(), gomock.Any(),
+			addr.IA{}).DoAndReturn(
+			func(_ context.Context, _ int, usage beacon.Usage,
+				_ addr.IA) (<-chan beacon.BeaconOrErr, error) {
+
+				results := make(chan beacon.BeaconOrErr, len(stored[usage]))
+				defer close(results)
+				for _, b := range stored[usage] {
+					results <- b
+				}
+				return results, nil
+			},
+		).AnyTimes()
+		ctx := context.Background()
+		Convey("ListBeacons lists all beacons with the combined usage", func() {
+			infos, err := beacon.ListBeacons(ctx, db)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("len", len(infos), ShouldEqual, 2)
+			SoMsg("direct", infos[0].Segment, ShouldEqual, direct.Beacon.Segment)
+			SoMsg("direct usage", infos[0].Usage, ShouldEqual,
+				beacon.UsageProp|beacon.UsageUpReg)
+			SoMsg("via130", infos[1].Segment, ShouldEqual, via130.Beacon.Segment)
+			SoMsg("via130 usage", infos[1].Usage, ShouldEqual, beacon.UsageProp)
+		})
+		Convey("Explain shows filtered beacons", func() {
+			policy := beacon.Policy{
+				Type:   beacon.PropPolicy,
+				Filter: beacon.Filter{AsBlackList: []addr.AS{ia130.A}},
+			}
+			policy.InitDefaults()
+			expls, err := beacon.Explain(ctx, db, policy, nil, false)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("len", len(expls), ShouldEqual, 2)
+			SoMsg("direct", expls[0].Selected, ShouldBeTrue)
+			SoMsg("via130", expls[1].Selected, ShouldBeFalse)
+			SoMsg("via130 reason", strings.HasPrefix(expls[1].Reason, "Rejected by policy"),
+				ShouldBeTrue)
+		})
+		Convey("Explain shows beacons outside the candidate set", func() {
+			policy := beacon.Policy{Type: beacon.PropPolicy, CandidateSetSize: 1}
+			policy.InitDefaults()
+			expls, err := beacon.Explain(ctx, db, policy, nil, false)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("direct", expls[0].Selected, ShouldBeTrue)
+			SoMsg("via130", expls[1].Selected, ShouldBeFalse)
+			SoMsg("via130 reason", strings.HasPrefix(expls[1].Reason, "Not in candidate set"),
+				ShouldBeTrue)
+			Convey("Selecting per source considers each source separately", func() {
+				expls, err := beacon.Explain(ctx, db, policy, nil, true)
+				SoMsg("err", err, ShouldBeNil)
+				SoMsg("direct", expls[0].Selected, ShouldBeTrue)
+				SoMsg("via130", expls[1].Selected, ShouldBeTrue)
+			})
+		})
+		Convey("Explain only considers beacons with the usage of the policy type", func() {
+			policy := beacon.Policy{Type: beacon.UpRegPolicy}
+			policy.InitDefaults()
+			expls, err := beacon.Explain(ctx, db, policy, nil, false)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("len", len(expls), ShouldEqual, 1)
+			SoMsg("direct", expls[0].Segment, ShouldEqual, direct.Beacon.Segment)
+			SoMsg("direct selected", expls[0].Selected, ShouldBeTrue)
+		})
+		Convey("Explain applies the egress policy filter", func() {
+			policy := beacon.Policy{Type: beacon.PropPolicy}
+			policy.InitDefaults()
+			egress := &beacon.EgressPolicy{
+				Name:        "no130",
+				BestSetSize: 5,
+				Filter:      beacon.Filter{AsBlackList: []addr.AS{ia130.A}},
+			}
+			expls, err := beacon.Explain(ctx, db, policy, egress, false)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("direct", expls[0].Selected, ShouldBeTrue)
+			SoMsg("via130", expls[1].Selected, ShouldBeFalse)
+			SoMsg("via130 reason", strings.HasPrefix(expls[1].Reason,
+				"Rejected by egress policy no130"), ShouldBeTrue)
+		})
+	})
+}
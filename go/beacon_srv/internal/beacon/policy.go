@@ -15,6 +15,7 @@
 package beacon
 
 import (
+	"fmt"
 	"io/ioutil"
 
 	yaml "gopkg.in/yaml.v2"
@@ -77,7 +78,7 @@ func (p *Policies) Validate() error {
 		return common.NewBasicError("Invalid policy type", nil,
 			"expected", DownRegPolicy, "actual", p.DownReg.Type)
 	}
-	return nil
+	return validatePolicies(&p.Prop, &p.UpReg, &p.DownReg)
 }
 
 // Filter applies all filters and returns an error if all of them filter the
@@ -139,7 +140,7 @@ func (p *CorePolicies) Validate() error {
 		return common.NewBasicError("Invalid policy type", nil,
 			"expected", CoreRegPolicy, "actual", p.CoreReg.Type)
 	}
-	return nil
+	return validatePolicies(&p.Prop, &p.CoreReg)
 }
 
 // Filter applies all filters and returns an error if all of them filter the
@@ -182,6 +183,10 @@ type Policy struct {
 	Filter Filter `yaml:"Filter"`
 	// Type is the policy type.
 	Type PolicyType `yaml:"Type"`
+	// EgressPolicies contains the policies that apply to a subset of the
+	// egress interfaces instead of this policy. Only allowed for the
+	// propagation policy.
+	EgressPolicies []EgressPolicy `yaml:"EgressPolicies"`
 }
 
 // InitDefaults initializes the default values for unset fields.
@@ -193,6 +198,37 @@ func (p *Policy) InitDefaults() {
 		p.CandidateSetSize = DefaultCandidateSetSize
 	}
 	p.Filter.InitDefaults()
+	for i := range p.EgressPolicies {
+		p.EgressPolicies[i].initDefaults(p.BestSetSize)
+	}
+}
+
+// Validate checks that egress policies are only set for the propagation
+// policy and that each egress policy matches at least one interface.
+func (p *Policy) Validate() error {
+	if len(p.EgressPolicies) > 0 && p.Type != PropPolicy {
+		return common.NewBasicError("Egress policies only allowed for propagation policy", nil,
+			"type", p.Type)
+	}
+	for i, egress := range p.EgressPolicies {
+		if len(egress.IfIDs) == 0 && len(egress.NeighborIAs) == 0 {
+			return common.NewBasicError("Egress policy matches no interface", nil,
+				"name", egress.Name, "idx", i)
+		}
+	}
+	return nil
+}
+
+// EgressPolicy returns the first egress policy that applies to the egress
+// interface ifid connecting to the neighbor ia. If none applies, nil is
+// returned and the policy itself applies to the interface.
+func (p *Policy) EgressPolicy(ifid common.IFIDType, ia addr.IA) *EgressPolicy {
+	for i := range p.EgressPolicies {
+		if p.EgressPolicies[i].Matches(ifid, ia) {
+			return &p.EgressPolicies[i]
+		}
+	}
+	return nil
 }
 
 func (p *Policy) initDefaults(t PolicyType) {
@@ -216,6 +252,9 @@ func ParseYaml(b common.RawBytes, t PolicyType) (*Policy, error) {
 		return nil, common.NewBasicError("Specified policy type does not match", nil,
 			"expected", t, "actual", p.Type)
 	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
 	return p, nil
 }
 
@@ -229,6 +268,65 @@ func LoadFromYaml(path string, t PolicyType) (*Policy, error) {
 	return ParseYaml(b, t)
 }
 
+// EgressPolicy is a propagation policy for a subset of the egress
+// interfaces, e.g. the interfaces to a specific customer. It applies to all
+// egress interfaces listed in IfIDs and all egress interfaces connecting to a
+// neighbor listed in NeighborIAs. The filter is applied in addition to the
+// filter of the AS-wide propagation policy, i.e., it can only further restrict
+// the beacons that are propagated.
+type EgressPolicy struct {
+	// Name is an optional name used to identify the policy in the logs.
+	Name string `yaml:"Name"`
+	// IfIDs contains the egress interfaces the policy applies to.
+	IfIDs []common.IFIDType `yaml:"IfIDs"`
+	// NeighborIAs contains the neighbors the policy applies to.
+	NeighborIAs []addr.IA `yaml:"NeighborIAs"`
+	// BestSetSize is the number of segments to propagate. If not set, the
+	// value of the AS-wide propagation policy is used.
+	BestSetSize int `yaml:"BestSetSize"`
+	// Filter is the filter applied to segments.
+	Filter Filter `yaml:"Filter"`
+}
+
+func (p *EgressPolicy) initDefaults(bestSetSize int) {
+	if p.BestSetSize == 0 {
+		p.BestSetSize = bestSetSize
+	}
+	p.Filter.InitDefaults()
+}
+
+// Matches indicates whether the policy applies to the egress interface ifid
+// connecting to the neighbor ia.
+func (p *EgressPolicy) Matches(ifid common.IFIDType, ia addr.IA) bool {
+	for _, id := range p.IfIDs {
+		if id == ifid {
+			return true
+		}
+	}
+	for _, neighbor := range p.NeighborIAs {
+		if neighbor.Equal(ia) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *EgressPolicy) String() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return fmt.Sprintf("IfIDs: %v NeighborIAs: %v", p.IfIDs, p.NeighborIAs)
+}
+
+func validatePolicies(policies ...*Policy) error {
+	for _, p := range policies {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Filter filters beacons.
 type Filter struct {
 	// MaxHopsLength is the maximum number of hops a segment can have.
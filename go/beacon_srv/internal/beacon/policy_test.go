@@ -21,6 +21,7 @@ import (
 
 	"github.com/scionproto/scion/go/beacon_srv/internal/beacon"
 	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
 	"github.com/scionproto/scion/go/lib/xtest"
 )
@@ -30,6 +31,7 @@ var (
 	ia111 = xtest.MustParseIA("1-ff00:0:111")
 	ia112 = xtest.MustParseIA("1-ff00:0:112")
 	ia113 = xtest.MustParseIA("1-ff00:0:113")
+	ia130 = xtest.MustParseIA("1-ff00:0:130")
 	ia210 = xtest.MustParseIA("2-ff00:0:210")
 	ia310 = xtest.MustParseIA("3-ff00:0:310")
 	ia311 = xtest.MustParseIA("3-ff00:0:311")
@@ -74,6 +76,42 @@ func TestLoadFromYaml(t *testing.T) {
 	})
 }
 
+func TestEgressPolicies(t *testing.T) {
+	Convey("Given a policy file with egress policies", t, func() {
+		p, err := beacon.LoadFromYaml("testdata/egressPolicy.yml", beacon.PropPolicy)
+		SoMsg("err", err, ShouldBeNil)
+		SoMsg("count", p.EgressPolicies, ShouldHaveLength, 2)
+		Convey("The egress policies are parsed correctly", func() {
+			e := p.EgressPolicies[0]
+			SoMsg("Name", e.Name, ShouldEqual, "customer")
+			SoMsg("IfIDs", e.IfIDs, ShouldResemble, []common.IFIDType{41, 42})
+			SoMsg("BestSetSize", e.BestSetSize, ShouldEqual, 2)
+			SoMsg("AsBlackList", e.Filter.AsBlackList, ShouldResemble, []addr.AS{ia110.A})
+			SoMsg("MaxHopsLength", e.Filter.MaxHopsLength, ShouldEqual,
+				beacon.DefaultMaxHopsLength)
+			e = p.EgressPolicies[1]
+			SoMsg("NeighborIAs", e.NeighborIAs, ShouldResemble, []addr.IA{ia111})
+			SoMsg("Default BestSetSize", e.BestSetSize, ShouldEqual, 6)
+		})
+		Convey("The matching egress policy is returned", func() {
+			SoMsg("ifid", p.EgressPolicy(42, ia112), ShouldEqual, &p.EgressPolicies[0])
+			SoMsg("ia", p.EgressPolicy(1, ia111), ShouldEqual, &p.EgressPolicies[1])
+			SoMsg("none", p.EgressPolicy(1, ia112), ShouldBeNil)
+		})
+		Convey("Loading as registration policy fails", func() {
+			_, err := beacon.LoadFromYaml("testdata/egressPolicy.yml", beacon.UpRegPolicy)
+			SoMsg("err", err, ShouldNotBeNil)
+		})
+	})
+	Convey("An egress policy without interfaces is invalid", t, func() {
+		p := beacon.Policy{
+			Type:           beacon.PropPolicy,
+			EgressPolicies: []beacon.EgressPolicy{{Name: "empty"}},
+		}
+		SoMsg("err", p.Validate(), ShouldNotBeNil)
+	})
+}
+
 func TestFilterApply(t *testing.T) {
 	Convey("Given a filter", t, func() {
 		f := beacon.Filter{
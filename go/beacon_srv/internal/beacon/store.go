@@ -63,7 +63,26 @@ func NewBeaconStore(policies Policies, db DB) (*Store, error) {
 // at the time of the call. The selection is based on the configured propagation
 // policy.
 func (s *Store) BeaconsToPropagate(ctx context.Context) (<-chan BeaconOrErr, error) {
-	return s.getBeacons(ctx, &s.policies.Prop)
+	return s.getBeacons(ctx, &s.policies.Prop, nil)
+}
+
+// EgressPolicy returns the propagation policy that applies to the egress
+// interface ifid connecting to the neighbor ia. If nil is returned, the
+// AS-wide propagation policy applies.
+func (s *Store) EgressPolicy(ifid common.IFIDType, ia addr.IA) *EgressPolicy {
+	return s.policies.Prop.EgressPolicy(ifid, ia)
+}
+
+// EgressBeaconsToPropagate returns a channel that provides all beacons to
+// propagate on the egress interfaces the egress policy applies to. The
+// selection is based on the configured propagation policy restricted by the
+// egress policy.
+func (s *Store) EgressBeaconsToPropagate(ctx context.Context,
+	egress *EgressPolicy) (<-chan BeaconOrErr, error) {
+
+	policy := s.policies.Prop
+	policy.BestSetSize = egress.BestSetSize
+	return s.getBeacons(ctx, &policy, &egress.Filter)
 }
 
 // SegmentsToRegister returns a channel that provides all beacons to register at
@@ -74,22 +93,26 @@ func (s *Store) SegmentsToRegister(ctx context.Context, segType proto.PathSegTyp
 
 	switch {
 	case segType == proto.PathSegType_down:
-		return s.getBeacons(ctx, &s.policies.DownReg)
+		return s.getBeacons(ctx, &s.policies.DownReg, nil)
 	case segType == proto.PathSegType_up:
-		return s.getBeacons(ctx, &s.policies.UpReg)
+		return s.getBeacons(ctx, &s.policies.UpReg, nil)
 	default:
 		return nil, common.NewBasicError("Unsupported segment type", nil, "type", segType)
 	}
 }
 
 // getBeacons fetches the candidate beacons from the database and serves the
-// best beacons according to the policy.
-func (s *Store) getBeacons(ctx context.Context, policy *Policy) (<-chan BeaconOrErr, error) {
+// best beacons according to the policy. If filter is set, candidate beacons
+// that are filtered by it are not considered.
+func (s *Store) getBeacons(ctx context.Context, policy *Policy,
+	filter *Filter) (<-chan BeaconOrErr, error) {
+
 	beacons, err := s.db.CandidateBeacons(ctx, policy.CandidateSetSize,
 		UsageFromPolicyType(policy.Type), addr.IA{})
 	if err != nil {
 		return nil, err
 	}
+	beacons = filterBeacons(beacons, filter)
 	results := make(chan BeaconOrErr, min(maxResultChanSize, policy.BestSetSize))
 	go func() {
 		defer log.LogPanicAndExit()
@@ -129,7 +152,26 @@ func NewCoreBeaconStore(policies CorePolicies, db DB) (*CoreStore, error) {
 // at the time of the call. The selection is based on the configured propagation
 // policy.
 func (s *CoreStore) BeaconsToPropagate(ctx context.Context) (<-chan BeaconOrErr, error) {
-	return s.getBeacons(ctx, &s.policies.Prop)
+	return s.getBeacons(ctx, &s.policies.Prop, nil)
+}
+
+// EgressPolicy returns the propagation policy that applies to the egress
+// interface ifid connecting to the neighbor ia. If nil is returned, the
+// AS-wide propagation policy applies.
+func (s *CoreStore) EgressPolicy(ifid common.IFIDType, ia addr.IA) *EgressPolicy {
+	return s.policies.Prop.EgressPolicy(ifid, ia)
+}
+
+// EgressBeaconsToPropagate returns a channel that provides all beacons to
+// propagate on the egress interfaces the egress policy applies to. The
+// selection is based on the configured propagation policy restricted by the
+// egress policy.
+func (s *CoreStore) EgressBeaconsToPropagate(ctx context.Context,
+	egress *EgressPolicy) (<-chan BeaconOrErr, error) {
+
+	policy := s.policies.Prop
+	policy.BestSetSize = egress.BestSetSize
+	return s.getBeacons(ctx, &policy, &egress.Filter)
 }
 
 // SegmentsToRegister returns a channel that provides all beacons to register at
@@ -141,12 +183,15 @@ func (s *CoreStore) SegmentsToRegister(ctx context.Context, segType proto.PathSe
 	if segType != proto.PathSegType_core {
 		return nil, common.NewBasicError("Unsupported segment type", nil, "type", segType)
 	}
-	return s.getBeacons(ctx, &s.policies.CoreReg)
+	return s.getBeacons(ctx, &s.policies.CoreReg, nil)
 }
 
 // getBeacons fetches the candidate beacons from the database and serves the
-// best beacons according to the policy.
-func (s *CoreStore) getBeacons(ctx context.Context, policy *Policy) (<-chan BeaconOrErr, error) {
+// best beacons according to the policy. If filter is set, candidate beacons
+// that are filtered by it are not considered.
+func (s *CoreStore) getBeacons(ctx context.Context, policy *Policy,
+	filter *Filter) (<-chan BeaconOrErr, error) {
+
 	srcs, err := s.db.BeaconSources(ctx)
 	if err != nil {
 		return nil, err
@@ -164,6 +209,7 @@ func (s *CoreStore) getBeacons(ctx context.Context, policy *Policy) (<-chan Beac
 			errs = append(errs, src)
 			continue
 		}
+		beacons = filterBeacons(beacons, filter)
 		wg.Add(1)
 		go func() {
 			defer log.LogPanicAndExit()
@@ -260,6 +306,31 @@ func (s *baseStore) UpdatePolicy(ctx context.Context, policy Policy) error {
 	return common.NewBasicError("policy update not supported", nil)
 }
 
+// filterBeacons returns a channel that provides the beacons that are not
+// filtered by filter. Errors are passed through. If filter is nil, beacons is
+// returned unchanged.
+func filterBeacons(beacons <-chan BeaconOrErr, filter *Filter) <-chan BeaconOrErr {
+	if filter == nil {
+		return beacons
+	}
+	filtered := make(chan BeaconOrErr)
+	go func() {
+		defer log.LogPanicAndExit()
+		defer close(filtered)
+		for res := range beacons {
+			if res.Err == nil {
+				if err := filter.Apply(res.Beacon); err != nil {
+					log.Trace("Beacon filtered by egress policy", "beacon", res.Beacon,
+						"err", err)
+					continue
+				}
+			}
+			filtered <- res
+		}
+	}()
+	return filtered
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -194,6 +194,55 @@ func testStoreSelection(t *testing.T,
 	}
 }
 
+func TestStoreEgressBeaconsToPropagate(t *testing.T) {
+	mctrl := gomock.NewController(t)
+	defer mctrl.Finish()
+	g := graph.NewDefaultGraph(mctrl)
+
+	stub := graph.If_111_A_112_X
+	direct := testBeaconOrErr(g, graph.If_120_X_111_B, stub)
+	via130 := testBeaconOrErr(g, graph.If_130_B_120_A, graph.If_120_X_111_B, stub)
+	db := mock_beacon.NewMockDB(mctrl)
+	policies := beacon.Policies{
+		Prop: beacon.Policy{
+			EgressPolicies: []beacon.EgressPolicy{
+				{
+					IfIDs:  []common.IFIDType{stub},
+					Filter: beacon.Filter{AsBlackList: []addr.AS{ia130.A}},
+				},
+			},
+		},
+	}
+	store, err := beacon.NewBeaconStore(policies, db)
+	xtest.FailOnErr(t, err)
+	if store.EgressPolicy(stub+1, ia130) != nil {
+		t.Fatalf("Unexpected egress policy")
+	}
+	egress := store.EgressPolicy(stub, ia130)
+	if egress == nil {
+		t.Fatalf("Expected egress policy")
+	}
+	db.EXPECT().CandidateBeacons(gomock.Any(), gomock.Any(), gomock.Any(),
+		addr.IA{}).DoAndReturn(
+		func(_ ...interface{}) (<-chan beacon.BeaconOrErr, error) {
+			results := make(chan beacon.BeaconOrErr, 2)
+			defer close(results)
+			results <- direct
+			results <- via130
+			return results, nil
+		},
+	)
+	res, err := store.EgressBeaconsToPropagate(context.Background(), egress)
+	xtest.FailOnErr(t, err)
+	var served []beacon.BeaconOrErr
+	for bOrErr := range res {
+		served = append(served, bOrErr)
+	}
+	if len(served) != 1 || served[0] != direct {
+		t.Errorf("Expected only unfiltered beacon, got %v", served)
+	}
+}
+
 func TestCoreStoreSegmentsToRegister(t *testing.T) {
 	testCoreStoreSelection(t, func(store *beacon.CoreStore) (<-chan beacon.BeaconOrErr, error) {
 		return store.SegmentsToRegister(context.Background(), proto.PathSegType_core)
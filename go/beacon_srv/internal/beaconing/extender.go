@@ -141,6 +141,9 @@ func (s *segExtender) remoteInfo(ifid common.IFIDType) (
 	if state != ifstate.Active {
 		return 0, 0, 0, common.NewBasicError("Interface is not active", nil)
 	}
+	if intf.Draining() {
+		return 0, 0, 0, common.NewBasicError("Interface is draining", nil)
+	}
 	topoInfo := intf.TopoInfo()
 	if topoInfo.RemoteIFID == 0 {
 		return 0, 0, 0, common.NewBasicError("Remote ifid is not set", nil)
@@ -8,6 +8,8 @@ import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
 	beacon "github.com/scionproto/scion/go/beacon_srv/internal/beacon"
+	addr "github.com/scionproto/scion/go/lib/addr"
+	common "github.com/scionproto/scion/go/lib/common"
 	proto "github.com/scionproto/scion/go/proto"
 	reflect "reflect"
 )
@@ -106,6 +108,35 @@ func (mr *MockBeaconProviderMockRecorder) BeaconsToPropagate(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeaconsToPropagate", reflect.TypeOf((*MockBeaconProvider)(nil).BeaconsToPropagate), arg0)
 }
 
+// EgressBeaconsToPropagate mocks base method
+func (m *MockBeaconProvider) EgressBeaconsToPropagate(arg0 context.Context, arg1 *beacon.EgressPolicy) (<-chan beacon.BeaconOrErr, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EgressBeaconsToPropagate", arg0, arg1)
+	ret0, _ := ret[0].(<-chan beacon.BeaconOrErr)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EgressBeaconsToPropagate indicates an expected call of EgressBeaconsToPropagate
+func (mr *MockBeaconProviderMockRecorder) EgressBeaconsToPropagate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EgressBeaconsToPropagate", reflect.TypeOf((*MockBeaconProvider)(nil).EgressBeaconsToPropagate), arg0, arg1)
+}
+
+// EgressPolicy mocks base method
+func (m *MockBeaconProvider) EgressPolicy(arg0 common.IFIDType, arg1 addr.IA) *beacon.EgressPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EgressPolicy", arg0, arg1)
+	ret0, _ := ret[0].(*beacon.EgressPolicy)
+	return ret0
+}
+
+// EgressPolicy indicates an expected call of EgressPolicy
+func (mr *MockBeaconProviderMockRecorder) EgressPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EgressPolicy", reflect.TypeOf((*MockBeaconProvider)(nil).EgressPolicy), arg0, arg1)
+}
+
 // MockSegmentProvider is a mock of SegmentProvider interface
 type MockSegmentProvider struct {
 	ctrl     *gomock.Controller
@@ -23,6 +23,7 @@ import (
 	"github.com/scionproto/scion/go/beacon_srv/internal/beaconing/metrics"
 	"github.com/scionproto/scion/go/beacon_srv/internal/ifstate"
 	"github.com/scionproto/scion/go/beacon_srv/internal/onehop"
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
 	"github.com/scionproto/scion/go/lib/log"
@@ -33,6 +34,9 @@ import (
 // BeaconProvider provides beacons to send to neighboring ASes.
 type BeaconProvider interface {
 	BeaconsToPropagate(ctx context.Context) (<-chan beacon.BeaconOrErr, error)
+	EgressPolicy(ifid common.IFIDType, ia addr.IA) *beacon.EgressPolicy
+	EgressBeaconsToPropagate(ctx context.Context,
+		egress *beacon.EgressPolicy) (<-chan beacon.BeaconOrErr, error)
 }
 
 var _ periodic.Task = (*Propagator)(nil)
@@ -51,7 +55,9 @@ type PropagatorConf struct {
 // Propagator forwards beacons to neighboring ASes. In a core AS, the beacons
 // are propagated to neighbors on core links. In a non-core AS, the beacons are
 // forwarded on child links. Selection of the beacons is handled by the beacon
-// provider, the propagator only filters AS loops.
+// provider, the propagator only filters AS loops. Interfaces that have a
+// dedicated egress policy are served with the beacons selected for that
+// policy.
 type Propagator struct {
 	*segExtender
 	beaconSender *onehop.BeaconSender
@@ -107,33 +113,84 @@ func (p *Propagator) run(ctx context.Context) error {
 	if len(nonActivePeers) > 0 && p.tick.passed() {
 		log.Debug("[Propagator] Ignore non-active peering interfaces", "ifids", nonActivePeers)
 	}
-	beacons, err := p.provider.BeaconsToPropagate(ctx)
-	if err != nil {
-		p.metrics.IncInternalErr()
-		return err
-	}
 	s := newSummary()
 	var wg sync.WaitGroup
-	for bOrErr := range beacons {
-		if bOrErr.Err != nil {
-			log.Error("[Propagator] Unable to get beacon", "err", bOrErr.Err)
+	var errs []error
+	for _, group := range p.groupByPolicy(intfs) {
+		var beacons <-chan beacon.BeaconOrErr
+		var err error
+		if group.egress == nil {
+			beacons, err = p.provider.BeaconsToPropagate(ctx)
+		} else {
+			beacons, err = p.provider.EgressBeaconsToPropagate(ctx, group.egress)
+		}
+		// Must not return, the other groups should still be served.
+		if err != nil {
 			p.metrics.IncInternalErr()
+			errs = append(errs, common.NewBasicError("Unable to get beacons", err,
+				"policy", group.egress, "egIfIds", group.intfs))
 			continue
 		}
-		b := beaconPropagator{
-			Propagator:  p,
-			beacon:      bOrErr.Beacon,
-			activeIntfs: intfs,
-			peers:       peers,
-			summary:     s,
+		for bOrErr := range beacons {
+			if bOrErr.Err != nil {
+				log.Error("[Propagator] Unable to get beacon", "err", bOrErr.Err)
+				p.metrics.IncInternalErr()
+				continue
+			}
+			b := beaconPropagator{
+				Propagator:  p,
+				beacon:      bOrErr.Beacon,
+				activeIntfs: group.intfs,
+				peers:       peers,
+				summary:     s,
+			}
+			b.start(ctx, &wg)
 		}
-		b.start(ctx, &wg)
 	}
 	wg.Wait()
 	p.logSummary(s)
+	if len(errs) > 0 {
+		return common.NewBasicError("Propagation incomplete", nil, "errs", errs)
+	}
 	return nil
 }
 
+// policyGroup is a set of egress interfaces that the same propagation policy
+// applies to. If egress is nil, the AS-wide propagation policy applies.
+type policyGroup struct {
+	egress *beacon.EgressPolicy
+	intfs  []common.IFIDType
+}
+
+// groupByPolicy groups the interfaces by the propagation policy that applies
+// to them. The group of the AS-wide policy, if any, is first.
+func (p *Propagator) groupByPolicy(intfs []common.IFIDType) []policyGroup {
+	groups := []policyGroup{{}}
+	idx := make(map[*beacon.EgressPolicy]int)
+	for _, ifid := range intfs {
+		intf := p.cfg.Intfs.Get(ifid)
+		if intf == nil {
+			continue
+		}
+		egress := p.provider.EgressPolicy(ifid, intf.TopoInfo().ISD_AS)
+		if egress == nil {
+			groups[0].intfs = append(groups[0].intfs, ifid)
+			continue
+		}
+		i, ok := idx[egress]
+		if !ok {
+			i = len(groups)
+			idx[egress] = i
+			groups = append(groups, policyGroup{egress: egress})
+		}
+		groups[i].intfs = append(groups[i].intfs, ifid)
+	}
+	if len(groups[0].intfs) == 0 {
+		return groups[1:]
+	}
+	return groups
+}
+
 // needsBeacons returns a list of active interface ids that beacons should be
 // propagated to. In a core AS, these are all active core links. In a non-core
 // AS, these are all active child links.
@@ -175,6 +175,7 @@ func TestPropagatorRun(t *testing.T) {
 				cfg.Config.Intfs.Get(ifid).Activate(remote)
 			}
 			g := graph.NewDefaultGraph(mctrl)
+			provider.EXPECT().EgressPolicy(gomock.Any(), gomock.Any()).AnyTimes()
 			provider.EXPECT().BeaconsToPropagate(gomock.Any()).MaxTimes(2).DoAndReturn(
 				func(_ interface{}) (<-chan beacon.BeaconOrErr, error) {
 					res := make(chan beacon.BeaconOrErr, len(beacons[test.core]))
@@ -244,6 +245,7 @@ func TestPropagatorRun(t *testing.T) {
 		g := graph.NewDefaultGraph(mctrl)
 		// We call run 4 times in this test, since the interface to 1-ff00:0:120
 		// will never be beaconed on, because the beacons are filtered for loops.
+		provider.EXPECT().EgressPolicy(gomock.Any(), gomock.Any()).AnyTimes()
 		provider.EXPECT().BeaconsToPropagate(gomock.Any()).Times(4).DoAndReturn(
 			func(_ interface{}) (<-chan beacon.BeaconOrErr, error) {
 				res := make(chan beacon.BeaconOrErr, 1)
@@ -270,4 +272,85 @@ func TestPropagatorRun(t *testing.T) {
 		// Fourth run. Since period has passed, two writes are expected.
 		p.Run(nil)
 	})
+	Convey("Egress policy", t, func() {
+		mctrl := gomock.NewController(t)
+		defer mctrl.Finish()
+		topoProvider := xtest.TopoProviderFromFile(t, topoCore)
+		provider := mock_beaconing.NewMockBeaconProvider(mctrl)
+		conn := mock_snet.NewMockPacketConn(mctrl)
+		cfg := PropagatorConf{
+			Config: ExtenderConf{
+				Signer: testSigner(t, priv, topoProvider.Get().ISD_AS),
+				Mac:    macProp,
+				Intfs:  ifstate.NewInterfaces(topoProvider.Get().IFInfoMap, ifstate.Config{}),
+				MTU:    uint16(topoProvider.Get().MTU),
+			},
+			Period:         time.Hour,
+			BeaconProvider: provider,
+			Core:           true,
+			BeaconSender: &onehop.BeaconSender{
+				Sender: onehop.Sender{
+					IA:   topoProvider.Get().ISD_AS,
+					Conn: conn,
+					Addr: &addr.AppAddr{
+						L3: addr.HostFromIPStr("127.0.0.1"),
+						L4: addr.NewL4UDPInfo(4242),
+					},
+					MAC: macSender,
+				},
+			},
+		}
+		p, err := cfg.New()
+		SoMsg("err", err, ShouldBeNil)
+		for ifid, remote := range allIntfs[true] {
+			cfg.Config.Intfs.Get(ifid).Activate(remote)
+		}
+		g := graph.NewDefaultGraph(mctrl)
+		egress := &beacon.EgressPolicy{IfIDs: []common.IFIDType{graph.If_110_X_210_X}}
+		provider.EXPECT().EgressPolicy(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+			func(ifid common.IFIDType, _ addr.IA) *beacon.EgressPolicy {
+				if egress.Matches(ifid, addr.IA{}) {
+					return egress
+				}
+				return nil
+			},
+		)
+		// The AS-wide policy serves both beacons to 1-ff00:0:120 and
+		// 1-ff00:0:130, the egress policy only serves the first beacon to
+		// 2-ff00:0:210.
+		provider.EXPECT().BeaconsToPropagate(gomock.Any()).DoAndReturn(
+			func(_ interface{}) (<-chan beacon.BeaconOrErr, error) {
+				res := make(chan beacon.BeaconOrErr, len(beacons[true]))
+				for _, desc := range beacons[true] {
+					res <- testBeaconOrErr(g, desc)
+				}
+				close(res)
+				return res, nil
+			},
+		)
+		provider.EXPECT().EgressBeaconsToPropagate(gomock.Any(), egress).DoAndReturn(
+			func(_, _ interface{}) (<-chan beacon.BeaconOrErr, error) {
+				res := make(chan beacon.BeaconOrErr, 1)
+				res <- testBeaconOrErr(g, beacons[true][0])
+				close(res)
+				return res, nil
+			},
+		)
+		var ifidsMtx sync.Mutex
+		var ifids []common.IFIDType
+		conn.EXPECT().WriteTo(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+			func(ipkt, _ interface{}) error {
+				ifidsMtx.Lock()
+				defer ifidsMtx.Unlock()
+				pkt := ipkt.(*snet.SCIONPacket)
+				hop, err := pkt.Path.GetHopField(pkt.Path.HopOff)
+				xtest.FailOnErr(t, err)
+				ifids = append(ifids, hop.ConsEgress)
+				return nil
+			},
+		)
+		p.Run(nil)
+		SoMsg("egress ifids", ifids, ShouldContain, graph.If_110_X_130_A)
+		SoMsg("egress ifids", ifids, ShouldContain, graph.If_110_X_210_X)
+	})
 }
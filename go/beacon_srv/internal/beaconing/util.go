@@ -60,7 +60,8 @@ func packBeaconMsg(bseg *seg.Beacon, ia addr.IA, egIfid common.IFIDType,
 
 // sortedIntfs returns two sorted lists. The first list contains all active
 // interfaces of the given type. The second list contains all non-active
-// interfaces of the given type.
+// interfaces of the given type. Draining interfaces are considered
+// non-active.
 func sortedIntfs(intfs *ifstate.Interfaces, linkType proto.LinkType) ([]common.IFIDType,
 	[]common.IFIDType) {
 
@@ -70,7 +71,7 @@ func sortedIntfs(intfs *ifstate.Interfaces, linkType proto.LinkType) ([]common.I
 		if topoInfo.LinkType != linkType {
 			continue
 		}
-		if intf.State() != ifstate.Active {
+		if intf.State() != ifstate.Active || intf.Draining() {
 			nonActive = append(nonActive, ifid)
 			continue
 		}
@@ -23,6 +23,7 @@ import (
 	"strconv"
 
 	"github.com/scionproto/scion/go/beacon_srv/internal/beacon"
+	"github.com/scionproto/scion/go/beacon_srv/internal/beacon/beacondbpostgres"
 	"github.com/scionproto/scion/go/beacon_srv/internal/beacon/beacondbsqlite"
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
@@ -39,6 +40,8 @@ const (
 	backendNone Backend = ""
 	// BackendSqlite indicates an sqlite backend.
 	BackendSqlite Backend = "sqlite"
+	// BackendPostgres indicates a PostgreSQL backend.
+	BackendPostgres Backend = "postgres"
 )
 
 const (
@@ -107,7 +110,7 @@ func (cfg *BeaconDBConf) Validate() error {
 		return err
 	}
 	switch cfg.Backend() {
-	case BackendSqlite:
+	case BackendSqlite, BackendPostgres:
 		return nil
 	case backendNone:
 		return common.NewBasicError("No backend set", nil)
@@ -144,6 +147,8 @@ func (cfg *BeaconDBConf) New(ia addr.IA) (beacon.DB, error) {
 	switch cfg.Backend() {
 	case BackendSqlite:
 		db, err = beacondbsqlite.New(cfg.Connection(), ia)
+	case BackendPostgres:
+		db, err = beacondbpostgres.New(cfg.Connection(), ia)
 	default:
 		return nil, common.NewBasicError("Unsupported backend", nil, "backend", cfg.Backend())
 	}
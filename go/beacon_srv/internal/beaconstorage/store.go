@@ -35,6 +35,14 @@ type Store interface {
 	// propagate at the time of the call. The selection is based on the
 	// configured propagation policy.
 	BeaconsToPropagate(ctx context.Context) (<-chan beacon.BeaconOrErr, error)
+	// EgressPolicy returns the propagation policy that applies to the egress
+	// interface ifid connecting to the neighbor ia. If nil is returned, the
+	// AS-wide propagation policy applies.
+	EgressPolicy(ifid common.IFIDType, ia addr.IA) *beacon.EgressPolicy
+	// EgressBeaconsToPropagate returns a channel that provides all beacons to
+	// propagate on the egress interfaces the egress policy applies to.
+	EgressBeaconsToPropagate(ctx context.Context,
+		egress *beacon.EgressPolicy) (<-chan beacon.BeaconOrErr, error)
 	// SegmentsToRegister returns a channel that provides all beacons to
 	// register at the time of the call. The selections is based on the
 	// configured propagation policy for the requested segment type.
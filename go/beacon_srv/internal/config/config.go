@@ -20,10 +20,13 @@ import (
 	"time"
 
 	"github.com/scionproto/scion/go/beacon_srv/internal/beaconstorage"
+	"github.com/scionproto/scion/go/beacon_srv/internal/ifstate"
+	"github.com/scionproto/scion/go/beacon_srv/internal/leader"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/env"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery"
+	"github.com/scionproto/scion/go/lib/keysigner"
 	"github.com/scionproto/scion/go/lib/truststorage"
 	"github.com/scionproto/scion/go/lib/util"
 )
@@ -55,9 +58,12 @@ type Config struct {
 	Logging        env.Logging
 	Metrics        env.Metrics
 	QUIC           env.QUIC `toml:"quic"`
+	Admission      env.Admission
+	Tracing        env.Tracing
 	TrustDB        truststorage.TrustDBConf
 	BeaconDB       beaconstorage.BeaconDBConf
 	Discovery      idiscovery.Config
+	Keys           keysigner.Config
 	BS             BSConfig
 	EnableQUICTest bool
 }
@@ -68,24 +74,47 @@ func (cfg *Config) InitDefaults() {
 		&cfg.General,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.Tracing,
 		&cfg.TrustDB,
 		&cfg.BeaconDB,
 		&cfg.Discovery,
+		&cfg.Keys,
 		&cfg.BS,
 	)
 }
 
 // Validate validates all parts of the config.
 func (cfg *Config) Validate() error {
-	return config.ValidateAll(
+	err := config.ValidateAll(
 		&cfg.General,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.Admission,
+		&cfg.Tracing,
 		&cfg.TrustDB,
 		&cfg.BeaconDB,
 		&cfg.Discovery,
+		&cfg.Keys,
 		&cfg.BS,
 	)
+	if err != nil {
+		return err
+	}
+	return cfg.validateLeader()
+}
+
+// validateLeader validates that the lease database for the leader election
+// can be derived from the beacon DB if no connection is configured.
+func (cfg *Config) validateLeader() error {
+	l := cfg.BS.Leader
+	if !l.Enabled || l.Connection != "" {
+		return nil
+	}
+	if cfg.BeaconDB.Backend() != beaconstorage.BackendPostgres {
+		return common.NewBasicError("Leader connection must be set if the beacon DB is not "+
+			"shared", nil, "backend", cfg.BeaconDB.Backend())
+	}
+	return nil
 }
 
 // Sample generates a sample config file for the beacon server.
@@ -94,9 +123,12 @@ func (cfg *Config) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 		&cfg.General,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.Admission,
+		&cfg.Tracing,
 		&cfg.TrustDB,
 		&cfg.BeaconDB,
 		&cfg.Discovery,
+		&cfg.Keys,
 		&cfg.BS,
 	)
 }
@@ -126,6 +158,13 @@ type BSConfig struct {
 	ExpiredCheckInterval util.DurWrap
 	// Policies contains the policy files.
 	Policies Policies
+	// Leader contains the leader election configuration.
+	Leader LeaderConfig
+	// AdminAddress is the address of the interface admin endpoint. It is
+	// either the absolute path of a unix socket, or host:port with a loopback
+	// IP address. The requests are not authenticated. If it is empty, the
+	// endpoint is disabled.
+	AdminAddress string
 }
 
 // InitDefaults the default values for the durations that are equal to zero.
@@ -136,6 +175,7 @@ func (cfg *BSConfig) InitDefaults() {
 	initDurWrap(&cfg.PropagationInterval, DefaultPropagationInterval)
 	initDurWrap(&cfg.RegistrationInterval, DefaultRegistrationInterval)
 	initDurWrap(&cfg.ExpiredCheckInterval, DefaultExpiredCheckInterval)
+	cfg.Leader.InitDefaults()
 }
 
 // Validate validates that all durations are set.
@@ -158,13 +198,18 @@ func (cfg *BSConfig) Validate() error {
 	if cfg.ExpiredCheckInterval.Duration == 0 {
 		return common.NewBasicError("ExpiredCheckInterval not set", nil)
 	}
-	return nil
+	if cfg.AdminAddress != "" {
+		if _, err := ifstate.AdminNetwork(cfg.AdminAddress); err != nil {
+			return err
+		}
+	}
+	return cfg.Leader.Validate()
 }
 
 // Sample generates a sample for the beacon server specific configuration.
 func (cfg *BSConfig) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
 	config.WriteString(dst, bsconfigSample)
-	config.WriteSample(dst, path, ctx, &cfg.Policies)
+	config.WriteSample(dst, path, ctx, &cfg.Policies, &cfg.Leader)
 }
 
 // ConfigName is the toml key for the beacon server specific configuration.
@@ -210,3 +255,63 @@ func (cfg *Policies) Sample(dst io.Writer, _ config.Path, _ config.CtxMap) {
 func (cfg *Policies) ConfigName() string {
 	return "policies"
 }
+
+var _ config.Config = (*LeaderConfig)(nil)
+
+// LeaderConfig is the configuration of the leader election between multiple
+// beacon server instances in the same AS.
+type LeaderConfig struct {
+	// Enabled enables the leader election. Only the leader originates and
+	// propagates beacons, and registers segments. If it is disabled, the
+	// beacon server always acts as leader.
+	Enabled bool
+	// Backend is the database backend that stores the lease. It is either
+	// sqlite or postgres.
+	Backend beaconstorage.Backend
+	// Connection is the connection to the lease database. For the postgres
+	// backend, the connection of the beacon DB is used if it is not set.
+	Connection string
+	// LeaseTime is the duration a lease is valid after it has been renewed.
+	LeaseTime util.DurWrap
+	// RenewInterval is the interval between attempts to acquire or renew the
+	// lease.
+	RenewInterval util.DurWrap
+}
+
+// InitDefaults initializes the default values for the durations that are
+// equal to zero.
+func (cfg *LeaderConfig) InitDefaults() {
+	initDurWrap(&cfg.LeaseTime, leader.DefaultLeaseTime)
+	initDurWrap(&cfg.RenewInterval, leader.DefaultRenewInterval)
+}
+
+// Validate validates the backend and that the lease is renewed before it
+// expires.
+func (cfg *LeaderConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	switch cfg.Backend {
+	case beaconstorage.BackendSqlite, beaconstorage.BackendPostgres:
+	default:
+		return common.NewBasicError("Unsupported leader backend", nil, "backend", cfg.Backend)
+	}
+	if cfg.Backend == beaconstorage.BackendSqlite && cfg.Connection == "" {
+		return common.NewBasicError("Leader connection must be set for sqlite", nil)
+	}
+	if cfg.RenewInterval.Duration >= cfg.LeaseTime.Duration {
+		return common.NewBasicError("RenewInterval must be smaller than LeaseTime", nil,
+			"renew", cfg.RenewInterval, "lease", cfg.LeaseTime)
+	}
+	return nil
+}
+
+// Sample generates a sample for the leader election configuration.
+func (cfg *LeaderConfig) Sample(dst io.Writer, _ config.Path, _ config.CtxMap) {
+	config.WriteString(dst, leaderSample)
+}
+
+// ConfigName is the toml key for the leader election configuration.
+func (cfg *LeaderConfig) ConfigName() string {
+	return "leader"
+}
@@ -21,9 +21,12 @@ import (
 	"github.com/BurntSushi/toml"
 	. "github.com/smartystreets/goconvey/convey"
 
+	"github.com/scionproto/scion/go/beacon_srv/internal/beaconstorage"
 	"github.com/scionproto/scion/go/beacon_srv/internal/beaconstorage/beaconstoragetest"
+	"github.com/scionproto/scion/go/beacon_srv/internal/leader"
 	"github.com/scionproto/scion/go/lib/env/envtest"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery/idiscoverytest"
+	"github.com/scionproto/scion/go/lib/keysigner/keysignertest"
 	"github.com/scionproto/scion/go/lib/truststorage/truststoragetest"
 )
 
@@ -46,10 +49,12 @@ func InitTestConfig(cfg *Config) {
 	truststoragetest.InitTestConfig(&cfg.TrustDB)
 	beaconstoragetest.InitTestBeaconDBConf(&cfg.BeaconDB)
 	idiscoverytest.InitTestConfig(&cfg.Discovery)
+	keysignertest.InitTestConfig(&cfg.Keys)
 	InitTestBSConfig(&cfg.BS)
 }
 
 func InitTestBSConfig(cfg *BSConfig) {
+	cfg.AdminAddress = "test"
 	InitTestPolicies(&cfg.Policies)
 }
 
@@ -65,6 +70,7 @@ func CheckTestConfig(cfg *Config, id string) {
 	truststoragetest.CheckTestConfig(&cfg.TrustDB, id)
 	beaconstoragetest.CheckTestBeaconDBConf(&cfg.BeaconDB, id)
 	idiscoverytest.CheckTestConfig(&cfg.Discovery)
+	keysignertest.CheckTestConfig(&cfg.Keys)
 	CheckTestBSConfig(&cfg.BS)
 }
 
@@ -80,7 +86,18 @@ func CheckTestBSConfig(cfg *BSConfig) {
 		DefaultRegistrationInterval)
 	SoMsg("ExpiredCheckInterval", cfg.ExpiredCheckInterval.Duration, ShouldEqual,
 		DefaultExpiredCheckInterval)
+	SoMsg("AdminAddress", cfg.AdminAddress, ShouldEqual, "")
 	CheckTestPolicies(&cfg.Policies)
+	CheckTestLeader(&cfg.Leader)
+}
+
+func CheckTestLeader(cfg *LeaderConfig) {
+	SoMsg("Enabled", cfg.Enabled, ShouldBeFalse)
+	SoMsg("Backend", cfg.Backend, ShouldEqual, beaconstorage.BackendPostgres)
+	SoMsg("Connection", cfg.Connection, ShouldEqual, "")
+	SoMsg("LeaseTime", cfg.LeaseTime.Duration, ShouldEqual, leader.DefaultLeaseTime)
+	SoMsg("RenewInterval", cfg.RenewInterval.Duration, ShouldEqual,
+		leader.DefaultRenewInterval)
 }
 
 func CheckTestPolicies(cfg *Policies) {
@@ -89,3 +106,49 @@ func CheckTestPolicies(cfg *Policies) {
 	SoMsg("UpRegistration", cfg.UpRegistration, ShouldEqual, "")
 	SoMsg("DownRegistration", cfg.DownRegistration, ShouldEqual, "")
 }
+
+func TestLeaderConfigValidate(t *testing.T) {
+	Convey("Given a leader election config", t, func() {
+		cfg := LeaderConfig{Enabled: true, Backend: beaconstorage.BackendSqlite,
+			Connection: "lease.db"}
+		cfg.InitDefaults()
+		SoMsg("valid", cfg.Validate(), ShouldBeNil)
+		Convey("The sqlite backend requires a connection", func() {
+			cfg.Connection = ""
+			SoMsg("err", cfg.Validate(), ShouldNotBeNil)
+		})
+		Convey("The backend must be supported", func() {
+			cfg.Backend = "mysql"
+			SoMsg("err", cfg.Validate(), ShouldNotBeNil)
+		})
+		Convey("The lease must be renewed before it expires", func() {
+			cfg.RenewInterval = cfg.LeaseTime
+			SoMsg("err", cfg.Validate(), ShouldNotBeNil)
+		})
+		Convey("A disabled config is not validated", func() {
+			cfg.Enabled = false
+			cfg.Backend = ""
+			SoMsg("err", cfg.Validate(), ShouldBeNil)
+		})
+	})
+}
+
+func TestBSConfigValidateAdminAddress(t *testing.T) {
+	Convey("Given a beacon server config", t, func() {
+		var cfg BSConfig
+		cfg.InitDefaults()
+		SoMsg("disabled", cfg.Validate(), ShouldBeNil)
+		Convey("A loopback address is valid", func() {
+			cfg.AdminAddress = "127.0.0.1:30455"
+			SoMsg("err", cfg.Validate(), ShouldBeNil)
+		})
+		Convey("A unix socket is valid", func() {
+			cfg.AdminAddress = "/run/shm/bs-admin.sock"
+			SoMsg("err", cfg.Validate(), ShouldBeNil)
+		})
+		Convey("A non-loopback address is invalid", func() {
+			cfg.AdminAddress = "0.0.0.0:30455"
+			SoMsg("err", cfg.Validate(), ShouldNotBeNil)
+		})
+	})
+}
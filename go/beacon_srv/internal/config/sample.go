@@ -35,6 +35,11 @@ RegistrationInterval = "5s"
 
 # The interval between checking for expired interfaces to revoke. (default 200ms)
 ExpiredCheckInterval = "200ms"
+
+# The address of the interface admin endpoint. It is either the absolute path
+# of a unix socket, or host:port with a loopback IP address. The requests are
+# not authenticated. If it is empty, the endpoint is disabled. (default "")
+AdminAddress = ""
 `
 
 const policiesSample = `
@@ -57,3 +62,25 @@ UpRegistration = ""
 # (default "")
 DownRegistration = ""
 `
+
+const leaderSample = `
+# Enable the leader election between beacon server instances of the same AS.
+# Only the leader originates and propagates beacons, and registers segments.
+# Standby instances handle incoming beacons and keepalives, and take over
+# when the leader fails to renew its lease. (default false)
+Enabled = false
+
+# The database backend storing the lease (sqlite|postgres). Instances that
+# compete for the leadership must share the database. (default "")
+Backend = "postgres"
+
+# The connection to the lease database. For postgres, the beacon DB
+# connection is used if it is not set. (default "")
+Connection = ""
+
+# The duration a lease is valid after it has been renewed. (default 5s)
+LeaseTime = "5s"
+
+# The interval between attempts to acquire or renew the lease. (default 1s)
+RenewInterval = "1s"
+`
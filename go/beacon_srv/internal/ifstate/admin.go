@@ -0,0 +1,181 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+const (
+	// AdminPath is the path of the interface state admin endpoint.
+	AdminPath = "/ifstate"
+)
+
+// AdminHandler is an HTTP handler that allows operators to take interfaces
+// out of service ahead of a maintenance, and to put them back into service.
+// It serves the following requests:
+//  GET  /ifstate                           lists the state of all interfaces.
+//  POST /ifstate/drain?ifid=1[&revoke=1]   drains the interface.
+//  POST /ifstate/undrain?ifid=1            puts the interface back into service.
+//
+// The requests are not authenticated, the handler must only be served on a
+// listener returned by ListenAdmin. The drain state is kept in the memory of
+// the beacon server instance. It is neither persisted across restarts nor
+// shared with standby instances, so an interface must be drained on every
+// instance that can become leader.
+type AdminHandler struct {
+	// Intfs are the interfaces that are managed.
+	Intfs *Interfaces
+	// OnRevoke, if set, is called after an interface has been drained with
+	// revocation, e.g., to run the revoker immediately.
+	OnRevoke func()
+}
+
+// IntfStatus is the status of an interface as reported by the admin handler.
+type IntfStatus struct {
+	IfID        common.IFIDType
+	State       State
+	Draining    bool
+	DrainRevoke bool
+}
+
+// AdminNetwork returns the network of the admin endpoint address. The address
+// is either the absolute path of a unix socket, or host:port with a loopback
+// IP address.
+func AdminNetwork(address string) (string, error) {
+	if filepath.IsAbs(address) {
+		return "unix", nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", common.NewBasicError("Invalid admin address", err, "address", address)
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return "", common.NewBasicError("Admin address must be a loopback IP or a unix socket",
+			nil, "address", address)
+	}
+	return "tcp", nil
+}
+
+// ListenAdmin listens on the admin endpoint address, see AdminNetwork. A
+// stale unix socket at the address is removed.
+func ListenAdmin(address string) (net.Listener, error) {
+	network, err := AdminNetwork(address)
+	if err != nil {
+		return nil, err
+	}
+	if network == "unix" {
+		if fi, err := os.Stat(address); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			if err := os.Remove(address); err != nil {
+				return nil, common.NewBasicError("Unable to remove stale admin socket", err,
+					"address", address)
+			}
+		}
+	}
+	return net.Listen(network, address)
+}
+
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case AdminPath:
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.serveStatus(w)
+	case AdminPath + "/drain":
+		h.serveDrain(w, r, true)
+	case AdminPath + "/undrain":
+		h.serveDrain(w, r, false)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *AdminHandler) serveStatus(w http.ResponseWriter) {
+	all := h.Intfs.All()
+	status := make([]IntfStatus, 0, len(all))
+	for ifid, intf := range all {
+		status = append(status, intfStatus(ifid, intf))
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].IfID < status[j].IfID })
+	writeJSON(w, status)
+}
+
+func (h *AdminHandler) serveDrain(w http.ResponseWriter, r *http.Request, drain bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	rawIfid := r.URL.Query().Get("ifid")
+	ifid, err := strconv.ParseUint(rawIfid, 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid ifid: %q", rawIfid), http.StatusBadRequest)
+		return
+	}
+	intf := h.Intfs.Get(common.IFIDType(ifid))
+	if intf == nil {
+		http.Error(w, fmt.Sprintf("unknown ifid: %d", ifid), http.StatusNotFound)
+		return
+	}
+	if !drain {
+		intf.Undrain()
+		log.Info("[AdminHandler] Undrained interface", "ifid", ifid)
+		writeJSON(w, intfStatus(common.IFIDType(ifid), intf))
+		return
+	}
+	var revoke bool
+	if rawRevoke := r.URL.Query().Get("revoke"); rawRevoke != "" {
+		if revoke, err = strconv.ParseBool(rawRevoke); err != nil {
+			http.Error(w, fmt.Sprintf("invalid revoke: %q", rawRevoke), http.StatusBadRequest)
+			return
+		}
+	}
+	intf.Drain(revoke)
+	log.Info("[AdminHandler] Draining interface", "ifid", ifid, "revoke", revoke)
+	if revoke && h.OnRevoke != nil {
+		h.OnRevoke()
+	}
+	writeJSON(w, intfStatus(common.IFIDType(ifid), intf))
+}
+
+func intfStatus(ifid common.IFIDType, intf *Interface) IntfStatus {
+	return IntfStatus{
+		IfID:        ifid,
+		State:       intf.State(),
+		Draining:    intf.Draining(),
+		DrainRevoke: intf.DrainRevoke(),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(v); err != nil {
+		log.Error("[AdminHandler] Unable to write response", "err", err)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifstate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAdminHandler(t *testing.T) {
+	Convey("Given an admin handler", t, func() {
+		intfs := testInterfaces()
+		var revokeCalls int
+		h := &AdminHandler{Intfs: intfs, OnRevoke: func() { revokeCalls++ }}
+		serve := func(method, target string) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+			return rec
+		}
+		Convey("GET lists all interfaces", func() {
+			rec := serve(http.MethodGet, "/ifstate")
+			SoMsg("code", rec.Code, ShouldEqual, http.StatusOK)
+			var status []IntfStatus
+			SoMsg("err", json.Unmarshal(rec.Body.Bytes(), &status), ShouldBeNil)
+			SoMsg("len", len(status), ShouldEqual, 2)
+			SoMsg("ifid 1", status[0].IfID, ShouldEqual, 1)
+			SoMsg("state 1", status[0].State, ShouldEqual, Active)
+			SoMsg("ifid 2", status[1].IfID, ShouldEqual, 2)
+			SoMsg("state 2", status[1].State, ShouldEqual, Revoked)
+		})
+		Convey("Drain without revocation", func() {
+			rec := serve(http.MethodPost, "/ifstate/drain?ifid=1")
+			SoMsg("code", rec.Code, ShouldEqual, http.StatusOK)
+			SoMsg("draining", intfs.Get(1).Draining(), ShouldBeTrue)
+			SoMsg("revoke", intfs.Get(1).DrainRevoke(), ShouldBeFalse)
+			SoMsg("revoke calls", revokeCalls, ShouldEqual, 0)
+			rec = serve(http.MethodPost, "/ifstate/undrain?ifid=1")
+			SoMsg("code undrain", rec.Code, ShouldEqual, http.StatusOK)
+			SoMsg("undrained", intfs.Get(1).Draining(), ShouldBeFalse)
+		})
+		Convey("Drain with revocation triggers the revoker", func() {
+			rec := serve(http.MethodPost, "/ifstate/drain?ifid=1&revoke=true")
+			SoMsg("code", rec.Code, ShouldEqual, http.StatusOK)
+			SoMsg("draining", intfs.Get(1).Draining(), ShouldBeTrue)
+			SoMsg("revoke", intfs.Get(1).DrainRevoke(), ShouldBeTrue)
+			SoMsg("revoke calls", revokeCalls, ShouldEqual, 1)
+		})
+		Convey("Invalid requests are rejected", func() {
+			SoMsg("method", serve(http.MethodGet, "/ifstate/drain?ifid=1").Code,
+				ShouldEqual, http.StatusMethodNotAllowed)
+			SoMsg("no ifid", serve(http.MethodPost, "/ifstate/drain").Code,
+				ShouldEqual, http.StatusBadRequest)
+			SoMsg("unknown ifid", serve(http.MethodPost, "/ifstate/drain?ifid=42").Code,
+				ShouldEqual, http.StatusNotFound)
+			SoMsg("bad revoke", serve(http.MethodPost, "/ifstate/drain?ifid=1&revoke=x").Code,
+				ShouldEqual, http.StatusBadRequest)
+			SoMsg("unknown path", serve(http.MethodGet, "/ifstate/foo").Code,
+				ShouldEqual, http.StatusNotFound)
+			SoMsg("draining", intfs.Get(1).Draining(), ShouldBeFalse)
+		})
+	})
+}
+
+func TestAdminNetwork(t *testing.T) {
+	Convey("AdminNetwork", t, func() {
+		tests := []struct {
+			Address string
+			Network string
+			Err     bool
+		}{
+			{Address: "/run/bs1-admin.sock", Network: "unix"},
+			{Address: "127.0.0.1:30455", Network: "tcp"},
+			{Address: "[::1]:30455", Network: "tcp"},
+			{Address: "192.0.2.1:30455", Err: true},
+			{Address: ":30455", Err: true},
+			{Address: "localhost:30455", Err: true},
+			{Address: "bs1-admin.sock", Err: true},
+		}
+		for _, test := range tests {
+			network, err := AdminNetwork(test.Address)
+			if test.Err {
+				SoMsg(test.Address, err, ShouldNotBeNil)
+				continue
+			}
+			SoMsg(test.Address+" err", err, ShouldBeNil)
+			SoMsg(test.Address, network, ShouldEqual, test.Network)
+		}
+	})
+}
@@ -140,18 +140,29 @@ type Interface struct {
 	lastPropagate time.Time
 	lastActivate  time.Time
 	cfg           Config
+	// draining indicates that the interface is taken out of service by the
+	// operator. Draining interfaces are not used for beaconing.
+	draining bool
+	// drainRevoke indicates that the draining interface is revoked.
+	drainRevoke bool
 }
 
 // Activate activates the interface the keep alive is received from when
 // necessary, and sets the remote interface id. The return value indicates
 // the previous state of the interface.
+//
+// An interface that is draining with revocation is not activated, i.e., it
+// stays revoked until it is undrained.
 func (intf *Interface) Activate(remote common.IFIDType) State {
 	intf.mu.Lock()
 	defer intf.mu.Unlock()
 	prev := intf.state
-	intf.state = Active
 	intf.lastActivate = time.Now()
 	intf.topoInfo.RemoteIFID = remote
+	if intf.drainRevoke {
+		return prev
+	}
+	intf.state = Active
 	intf.revocation = nil
 	return prev
 }
@@ -179,11 +190,12 @@ func (intf *Interface) Expire() bool {
 // Revoke changes the state of the interface to revoked and updates the
 // revocation, unless the current state is active. In that case, the
 // interface has been activated in the meantime and should not be revoked.
-// This is indicated through an error.
+// This is indicated through an error. Interfaces that are draining with
+// revocation are revoked regardless of their state.
 func (intf *Interface) Revoke(rev *path_mgmt.SignedRevInfo) error {
 	intf.mu.Lock()
 	defer intf.mu.Unlock()
-	if intf.state == Active {
+	if intf.state == Active && !intf.drainRevoke {
 		return common.NewBasicError("Interface activated in the meantime", nil)
 	}
 	intf.state = Revoked
@@ -191,6 +203,47 @@ func (intf *Interface) Revoke(rev *path_mgmt.SignedRevInfo) error {
 	return nil
 }
 
+// Drain marks the interface as draining, e.g., ahead of a maintenance of the
+// link. Draining interfaces are neither used to originate nor to propagate
+// beacons, and segments that contain them are not registered. If revoke is
+// set, the revoker additionally issues a revocation for the interface, such
+// that paths containing it are avoided before the link goes down.
+func (intf *Interface) Drain(revoke bool) {
+	intf.mu.Lock()
+	defer intf.mu.Unlock()
+	intf.draining = true
+	intf.drainRevoke = revoke
+}
+
+// Undrain puts a draining interface back into service. If the interface has
+// been revoked because of the drain, the revocation is dropped and the
+// interface is inactive until the next keepalive is received.
+func (intf *Interface) Undrain() {
+	intf.mu.Lock()
+	defer intf.mu.Unlock()
+	if intf.drainRevoke && intf.state == Revoked {
+		intf.state = Inactive
+		intf.revocation = nil
+	}
+	intf.draining = false
+	intf.drainRevoke = false
+}
+
+// Draining indicates whether the interface is draining.
+func (intf *Interface) Draining() bool {
+	intf.mu.RLock()
+	defer intf.mu.RUnlock()
+	return intf.draining
+}
+
+// DrainRevoke indicates whether the interface is draining and should be
+// revoked.
+func (intf *Interface) DrainRevoke() bool {
+	intf.mu.RLock()
+	defer intf.mu.RUnlock()
+	return intf.drainRevoke
+}
+
 // Revocation returns the revocation.
 func (intf *Interface) Revocation() *path_mgmt.SignedRevInfo {
 	intf.mu.RLock()
@@ -192,3 +192,45 @@ func testInterfaces() *Interfaces {
 	intfs.Get(2).Revoke(&path_mgmt.SignedRevInfo{})
 	return intfs
 }
+
+func TestInfoDrain(t *testing.T) {
+	Convey("Given an active interface", t, func() {
+		intf := &Interface{state: Active}
+		intf.cfg.InitDefaults()
+		Convey("Draining without revocation keeps the interface active", func() {
+			intf.Drain(false)
+			SoMsg("Draining", intf.Draining(), ShouldBeTrue)
+			SoMsg("DrainRevoke", intf.DrainRevoke(), ShouldBeFalse)
+			SoMsg("Revoke err", intf.Revoke(&path_mgmt.SignedRevInfo{}), ShouldNotBeNil)
+			SoMsg("Prev", intf.Activate(11), ShouldEqual, Active)
+			SoMsg("State", intf.State(), ShouldEqual, Active)
+			intf.Undrain()
+			SoMsg("Undrained", intf.Draining(), ShouldBeFalse)
+			SoMsg("State undrained", intf.State(), ShouldEqual, Active)
+		})
+		Convey("Draining with revocation allows revoking the interface", func() {
+			intf.Drain(true)
+			SoMsg("Draining", intf.Draining(), ShouldBeTrue)
+			SoMsg("DrainRevoke", intf.DrainRevoke(), ShouldBeTrue)
+			SoMsg("Revoke err", intf.Revoke(&path_mgmt.SignedRevInfo{}), ShouldBeNil)
+			SoMsg("State", intf.State(), ShouldEqual, Revoked)
+			// Keepalives must not reactivate the interface.
+			SoMsg("Prev", intf.Activate(11), ShouldEqual, Revoked)
+			SoMsg("State after activate", intf.State(), ShouldEqual, Revoked)
+			SoMsg("Revocation", intf.Revocation(), ShouldNotBeNil)
+			intf.Undrain()
+			SoMsg("Undrained", intf.Draining(), ShouldBeFalse)
+			SoMsg("DrainRevoke undrained", intf.DrainRevoke(), ShouldBeFalse)
+			SoMsg("State undrained", intf.State(), ShouldEqual, Inactive)
+			SoMsg("Prev undrained", intf.Activate(11), ShouldEqual, Inactive)
+			SoMsg("State reactivated", intf.State(), ShouldEqual, Active)
+		})
+		Convey("Resetting the state on election keeps the drain state", func() {
+			intf.Drain(true)
+			intf.reset()
+			SoMsg("Draining", intf.Draining(), ShouldBeTrue)
+			SoMsg("DrainRevoke", intf.DrainRevoke(), ShouldBeTrue)
+			SoMsg("State", intf.State(), ShouldEqual, Inactive)
+		})
+	})
+}
@@ -65,8 +65,9 @@ type RevokerConf struct {
 
 var _ periodic.Task = (*Revoker)(nil)
 
-// Revoker issues revocations for interfaces that have timed out.
-// Revocations for already revoked interfaces are renewed periodically.
+// Revoker issues revocations for interfaces that have timed out or that are
+// draining with revocation. Revocations for already revoked interfaces are
+// renewed periodically.
 type Revoker struct {
 	cfg    RevokerConf
 	pusher brPusher
@@ -84,13 +85,18 @@ func (cfg RevokerConf) New() *Revoker {
 	}
 }
 
-// Run issues revocations for interfaces that have timed out
-// and renews revocations for revoked interfaces.
+// Run issues revocations for interfaces that have timed out or that are
+// draining with revocation, and renews revocations for revoked interfaces.
 func (r *Revoker) Run(ctx context.Context) {
 	revs := make(map[common.IFIDType]*path_mgmt.SignedRevInfo)
 	for ifid, intf := range r.cfg.Intfs.All() {
-		if intf.Expire() && !r.hasValidRevocation(intf) {
-			if intf.Revocation() == nil {
+		drainRevoke := intf.DrainRevoke()
+		if (intf.Expire() || drainRevoke) && !r.hasValidRevocation(intf) {
+			switch {
+			case intf.Revocation() != nil:
+			case drainRevoke:
+				log.Info("[Revoker] revoking draining interface", "ifid", ifid)
+			default:
 				log.Info("[Revoker] interface went down", "ifid", ifid)
 			}
 			srev, err := r.createSignedRev(ifid)
@@ -100,7 +100,9 @@ func (h *handler) handle(logger log.Logger) (*infra.HandlerResult, error) {
 	if err != nil {
 		return infra.MetricsErrInvalid, err
 	}
-	if lastState := info.Activate(keepalive.OrigIfID); lastState != ifstate.Active {
+	lastState := info.Activate(keepalive.OrigIfID)
+	// Interfaces that are draining with revocation are not activated.
+	if lastState != ifstate.Active && info.State() == ifstate.Active {
 		logger.Info("[KeepaliveHandler] Activated interface", "ifid", ifid)
 		h.startPush(ifid)
 		if err := h.dropRevs(ifid, keepalive.OrigIfID, info.TopoInfo().ISD_AS); err != nil {
@@ -0,0 +1,139 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package leader implements the leader election between multiple beacon
+// server instances of the same AS.
+//
+// The instances compete for a lease that is stored in a shared database. The
+// instance holding the lease is the leader and runs the tasks that must only
+// run once per AS (e.g., beacon origination, propagation and segment
+// registration). The leader periodically renews the lease. If it fails to do
+// so, it steps down at the latest when the lease expires, and one of the
+// standby instances acquires the lease. Thus, the leader is replaced within
+// at most the lease time plus the renew interval.
+package leader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/periodic"
+)
+
+const (
+	// DefaultLeaseTime is the default duration a lease is valid after it has
+	// been acquired or renewed.
+	DefaultLeaseTime = 5 * time.Second
+	// DefaultRenewInterval is the default interval between attempts to
+	// acquire or renew the lease.
+	DefaultRenewInterval = time.Second
+)
+
+// Lease is a lease that is held by at most one owner at a time.
+type Lease interface {
+	// Acquire acquires the lease for the owner, or renews it if the owner
+	// already holds it. The lease is valid until expiration. The return value
+	// indicates whether the owner holds the lease when the call returns.
+	Acquire(ctx context.Context, owner string, expiration time.Time) (bool, error)
+	// Release releases the lease, if it is held by the owner.
+	Release(ctx context.Context, owner string) error
+}
+
+var _ periodic.Task = (*Elector)(nil)
+
+// Elector is a periodic task that tries to acquire or renew the lease. The
+// callbacks are invoked when the instance becomes leader or loses the
+// leadership.
+type Elector struct {
+	// Lease is the lease the instances compete for.
+	Lease Lease
+	// ID identifies this instance. It must be unique among all instances
+	// competing for the lease.
+	ID string
+	// LeaseTime is the duration the lease is valid after it has been
+	// acquired or renewed.
+	LeaseTime time.Duration
+	// OnElected is invoked when the instance becomes leader.
+	OnElected func()
+	// OnDemoted is invoked when the instance loses the leadership.
+	OnDemoted func()
+
+	mu     sync.Mutex
+	leader bool
+	expiry time.Time
+}
+
+// Run tries to acquire or renew the lease.
+func (e *Elector) Run(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := time.Now()
+	expiration := now.Add(e.LeaseTime)
+	acquired, err := e.Lease.Acquire(ctx, e.ID, expiration)
+	switch {
+	case err != nil:
+		log.Error("[leader.Elector] Unable to acquire lease", "id", e.ID, "err", err)
+		// The lease is still valid, the leadership is only given up after it
+		// has expired.
+		if e.leader && time.Now().After(e.expiry) {
+			e.demote("lease expired")
+		}
+	case acquired:
+		e.expiry = expiration
+		if !e.leader {
+			e.elect()
+		}
+	case e.leader:
+		e.demote("lease taken over")
+	}
+}
+
+// IsLeader indicates whether this instance is currently the leader.
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader
+}
+
+// Resign gives up the leadership and releases the lease, such that a standby
+// instance can take over immediately. This should be called on shutdown.
+func (e *Elector) Resign(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.leader {
+		e.demote("resigned")
+	}
+	return e.Lease.Release(ctx, e.ID)
+}
+
+func (e *Elector) elect() {
+	log.Info("[leader.Elector] Elected leader", "id", e.ID)
+	e.leader = true
+	metrics.leader.Set(1)
+	if e.OnElected != nil {
+		e.OnElected()
+	}
+}
+
+func (e *Elector) demote(reason string) {
+	log.Info("[leader.Elector] Lost leadership", "id", e.ID, "reason", reason)
+	e.leader = false
+	e.expiry = time.Time{}
+	metrics.leader.Set(0)
+	if e.OnDemoted != nil {
+		e.OnDemoted()
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testLease is an in-memory lease.
+type testLease struct {
+	owner      string
+	expiration time.Time
+	err        error
+}
+
+func (l *testLease) Acquire(_ context.Context, owner string,
+	expiration time.Time) (bool, error) {
+
+	if l.err != nil {
+		return false, l.err
+	}
+	if l.owner != owner && time.Now().Before(l.expiration) {
+		return false, nil
+	}
+	l.owner, l.expiration = owner, expiration
+	return true, nil
+}
+
+func (l *testLease) Release(_ context.Context, owner string) error {
+	if l.owner == owner {
+		l.owner, l.expiration = "", time.Time{}
+	}
+	return l.err
+}
+
+func TestElector(t *testing.T) {
+	Convey("Given two electors competing for the same lease", t, func() {
+		ctx := context.Background()
+		lease := &testLease{}
+		var elected, demoted []string
+		newElector := func(id string) *Elector {
+			return &Elector{
+				Lease:     lease,
+				ID:        id,
+				LeaseTime: time.Hour,
+				OnElected: func() { elected = append(elected, id) },
+				OnDemoted: func() { demoted = append(demoted, id) },
+			}
+		}
+		e1, e2 := newElector("bs-1"), newElector("bs-2")
+		e1.Run(ctx)
+		e2.Run(ctx)
+		SoMsg("e1 leader", e1.IsLeader(), ShouldBeTrue)
+		SoMsg("e2 leader", e2.IsLeader(), ShouldBeFalse)
+		SoMsg("elected", elected, ShouldResemble, []string{"bs-1"})
+		Convey("Renewing keeps the leadership", func() {
+			e1.Run(ctx)
+			e2.Run(ctx)
+			SoMsg("e1 leader", e1.IsLeader(), ShouldBeTrue)
+			SoMsg("elected", elected, ShouldResemble, []string{"bs-1"})
+			SoMsg("demoted", demoted, ShouldBeEmpty)
+		})
+		Convey("The standby takes over after the leader resigns", func() {
+			SoMsg("err", e1.Resign(ctx), ShouldBeNil)
+			e2.Run(ctx)
+			SoMsg("e1 leader", e1.IsLeader(), ShouldBeFalse)
+			SoMsg("e2 leader", e2.IsLeader(), ShouldBeTrue)
+			SoMsg("elected", elected, ShouldResemble, []string{"bs-1", "bs-2"})
+			SoMsg("demoted", demoted, ShouldResemble, []string{"bs-1"})
+		})
+		Convey("The leader is demoted when the lease is taken over", func() {
+			lease.expiration = time.Now().Add(-time.Second)
+			e2.Run(ctx)
+			e1.Run(ctx)
+			SoMsg("e1 leader", e1.IsLeader(), ShouldBeFalse)
+			SoMsg("e2 leader", e2.IsLeader(), ShouldBeTrue)
+			SoMsg("demoted", demoted, ShouldResemble, []string{"bs-1"})
+		})
+		Convey("The leader keeps the leadership on errors until the lease expires", func() {
+			lease.err = errors.New("test error")
+			e1.Run(ctx)
+			SoMsg("e1 leader", e1.IsLeader(), ShouldBeTrue)
+			e1.expiry = time.Now().Add(-time.Second)
+			e1.Run(ctx)
+			SoMsg("e1 leader expired", e1.IsLeader(), ShouldBeFalse)
+			SoMsg("demoted", demoted, ShouldResemble, []string{"bs-1"})
+		})
+	})
+}
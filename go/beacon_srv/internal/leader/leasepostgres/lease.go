@@ -0,0 +1,98 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package leasepostgres implements the leader election lease with a
+// PostgreSQL database, which can be shared by beacon server instances on
+// different hosts.
+package leasepostgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/scionproto/scion/go/beacon_srv/internal/leader"
+	"github.com/scionproto/scion/go/lib/infra/modules/db"
+)
+
+const (
+	// SchemaName identifies the schema in the database.
+	SchemaName = "bsleader"
+	// SchemaVersion is the version of the PostgreSQL schema understood by this backend.
+	// Whenever changes to the schema are made, this version number should be increased
+	// to prevent data corruption between incompatible database schemas.
+	SchemaVersion = 1
+	// Schema is the PostgreSQL database layout.
+	Schema = `CREATE TABLE Leases(
+		Name TEXT PRIMARY KEY,
+		Owner TEXT NOT NULL,
+		Expiration BIGINT NOT NULL
+	);`
+)
+
+var _ leader.Lease = (*Lease)(nil)
+
+// Lease is a lease stored in a PostgreSQL database.
+type Lease struct {
+	db   *sql.DB
+	name string
+}
+
+// New returns a new lease with the given name, which is stored in the
+// PostgreSQL database described by connection. The lease table can share the
+// database with the beacon DB.
+func New(connection, name string) (*Lease, error) {
+	db, err := db.NewPostgres(connection, SchemaName, Schema, SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &Lease{db: db, name: name}, nil
+}
+
+// Acquire acquires the lease for the owner, or renews it if the owner already
+// holds it. The lease is only taken over from a different owner, if it has
+// expired.
+func (l *Lease) Acquire(ctx context.Context, owner string,
+	expiration time.Time) (bool, error) {
+
+	query := `INSERT INTO Leases (Name, Owner, Expiration) VALUES ($1, $2, $3)
+		ON CONFLICT (Name) DO UPDATE SET Owner=EXCLUDED.Owner, Expiration=EXCLUDED.Expiration
+		WHERE Leases.Owner=EXCLUDED.Owner OR Leases.Expiration<$4`
+	res, err := l.db.ExecContext(ctx, query, l.name, owner, expiration.UnixNano(),
+		time.Now().UnixNano())
+	if err != nil {
+		return false, db.NewWriteError("Failed to write lease", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, db.NewWriteError("Failed to determine affected rows", err)
+	}
+	return n > 0, nil
+}
+
+// Release releases the lease, if it is held by the owner.
+func (l *Lease) Release(ctx context.Context, owner string) error {
+	query := "DELETE FROM Leases WHERE Name=$1 AND Owner=$2"
+	if _, err := l.db.ExecContext(ctx, query, l.name, owner); err != nil {
+		return db.NewWriteError("Failed to release lease", err)
+	}
+	return nil
+}
+
+// Close closes the database.
+func (l *Lease) Close() error {
+	return l.db.Close()
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package leasesqlite implements the leader election lease with an SQLite
+// database. The database file can be shared by beacon server instances that
+// run on the same host.
+package leasesqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/scionproto/scion/go/beacon_srv/internal/leader"
+	"github.com/scionproto/scion/go/lib/infra/modules/db"
+)
+
+const (
+	// SchemaVersion is the version of the SQLite schema understood by this backend.
+	// Whenever changes to the schema are made, this version number should be increased
+	// to prevent data corruption between incompatible database schemas.
+	SchemaVersion = 1
+	// Schema is the SQLite database layout.
+	Schema = `CREATE TABLE Leases(
+		Name TEXT PRIMARY KEY,
+		Owner TEXT NOT NULL,
+		Expiration INTEGER NOT NULL
+	);`
+)
+
+var _ leader.Lease = (*Lease)(nil)
+
+// Lease is a lease stored in an SQLite database.
+type Lease struct {
+	db   *sql.DB
+	name string
+}
+
+// New returns a new lease with the given name, which is stored in the SQLite
+// database at path. If the database does not exist, it is created.
+func New(path, name string) (*Lease, error) {
+	db, err := db.NewSqlite(path, Schema, SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &Lease{db: db, name: name}, nil
+}
+
+// Acquire acquires the lease for the owner, or renews it if the owner already
+// holds it.
+func (l *Lease) Acquire(ctx context.Context, owner string,
+	expiration time.Time) (bool, error) {
+
+	var acquired bool
+	err := db.DoInTx(ctx, l.db, func(ctx context.Context, tx *sql.Tx) error {
+		var current string
+		var currentExp int64
+		query := "SELECT Owner, Expiration FROM Leases WHERE Name=?"
+		err := tx.QueryRowContext(ctx, query, l.name).Scan(&current, &currentExp)
+		switch {
+		case err == sql.ErrNoRows:
+		case err != nil:
+			return db.NewReadError("Failed to lookup lease", err)
+		case current != owner && time.Now().UnixNano() < currentExp:
+			return nil
+		}
+		insert := "INSERT OR REPLACE INTO Leases (Name, Owner, Expiration) VALUES (?, ?, ?)"
+		if _, err := tx.ExecContext(ctx, insert, l.name, owner,
+			expiration.UnixNano()); err != nil {
+			return db.NewWriteError("Failed to write lease", err)
+		}
+		acquired = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return acquired, nil
+}
+
+// Release releases the lease, if it is held by the owner.
+func (l *Lease) Release(ctx context.Context, owner string) error {
+	query := "DELETE FROM Leases WHERE Name=? AND Owner=?"
+	if _, err := l.db.ExecContext(ctx, query, l.name, owner); err != nil {
+		return db.NewWriteError("Failed to release lease", err)
+	}
+	return nil
+}
+
+// Close closes the database.
+func (l *Lease) Close() error {
+	return l.db.Close()
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leasesqlite
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/beacon_srv/internal/leader"
+	"github.com/scionproto/scion/go/beacon_srv/internal/leader/leasetest"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+var _ leasetest.Testable = (*testStorage)(nil)
+
+type testStorage struct {
+	dir  string
+	path string
+}
+
+func (s *testStorage) Prepare(t *testing.T, _ context.Context) {
+	f, err := ioutil.TempFile(s.dir, "lease")
+	xtest.FailOnErr(t, err)
+	f.Close()
+	s.path = f.Name()
+}
+
+func (s *testStorage) Lease(t *testing.T, name string) leader.Lease {
+	l, err := New(s.path, name)
+	xtest.FailOnErr(t, err)
+	return l
+}
+
+func TestLeaseSuite(t *testing.T) {
+	dir, cleanF := xtest.MustTempDir("", "leasesqlite")
+	defer cleanF()
+	Convey("LeaseSuite", t, func() {
+		leasetest.Test(t, &testStorage{dir: dir})
+	})
+}
+
+func TestLeaseShared(t *testing.T) {
+	Convey("Two instances sharing a database file compete for the lease", t, func() {
+		dir, cleanF := xtest.MustTempDir("", "leasesqlite")
+		defer cleanF()
+		path := filepath.Join(dir, "lease.db")
+		storage := &testStorage{path: path}
+		l1, l2 := storage.Lease(t, "bs"), storage.Lease(t, "bs")
+		ctx := context.Background()
+		valid := time.Now().Add(time.Hour)
+		acquired, err := l1.Acquire(ctx, "bs-1", valid)
+		xtest.FailOnErr(t, err)
+		SoMsg("l1", acquired, ShouldBeTrue)
+		acquired, err = l2.Acquire(ctx, "bs-2", valid)
+		xtest.FailOnErr(t, err)
+		SoMsg("l2", acquired, ShouldBeFalse)
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package leasetest contains a test suite for the leader election lease
+// implementations.
+package leasetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/beacon_srv/internal/leader"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+var timeout = time.Second
+
+// Testable extends the lease storage with the functionality required to run
+// the test suite.
+type Testable interface {
+	// Prepare sets up an empty lease storage.
+	Prepare(t *testing.T, ctx context.Context)
+	// Lease returns the lease with the given name from the storage.
+	Lease(t *testing.T, name string) leader.Lease
+}
+
+// Test tests the lease implementation.
+func Test(t *testing.T, storage Testable) {
+	Convey("Given an unheld lease", func() {
+		ctx, cancelF := context.WithTimeout(context.Background(), timeout)
+		defer cancelF()
+		storage.Prepare(t, ctx)
+		lease := storage.Lease(t, "bs-1-ff00:0:110")
+		valid := time.Now().Add(time.Hour)
+		Convey("The first owner acquires the lease", func() {
+			acquired, err := lease.Acquire(ctx, "bs-1", valid)
+			xtest.FailOnErr(t, err)
+			SoMsg("bs-1", acquired, ShouldBeTrue)
+			acquired, err = lease.Acquire(ctx, "bs-2", valid)
+			xtest.FailOnErr(t, err)
+			SoMsg("bs-2", acquired, ShouldBeFalse)
+			Convey("The owner can renew the lease", func() {
+				acquired, err := lease.Acquire(ctx, "bs-1", valid.Add(time.Hour))
+				xtest.FailOnErr(t, err)
+				SoMsg("renewed", acquired, ShouldBeTrue)
+			})
+			Convey("Only the owner can release the lease", func() {
+				xtest.FailOnErr(t, lease.Release(ctx, "bs-2"))
+				acquired, err := lease.Acquire(ctx, "bs-2", valid)
+				xtest.FailOnErr(t, err)
+				SoMsg("not released", acquired, ShouldBeFalse)
+				xtest.FailOnErr(t, lease.Release(ctx, "bs-1"))
+				acquired, err = lease.Acquire(ctx, "bs-2", valid)
+				xtest.FailOnErr(t, err)
+				SoMsg("released", acquired, ShouldBeTrue)
+			})
+		})
+		Convey("An expired lease is taken over", func() {
+			acquired, err := lease.Acquire(ctx, "bs-1", time.Now().Add(-time.Second))
+			xtest.FailOnErr(t, err)
+			SoMsg("bs-1", acquired, ShouldBeTrue)
+			acquired, err = lease.Acquire(ctx, "bs-2", valid)
+			xtest.FailOnErr(t, err)
+			SoMsg("bs-2", acquired, ShouldBeTrue)
+			acquired, err = lease.Acquire(ctx, "bs-1", valid)
+			xtest.FailOnErr(t, err)
+			SoMsg("bs-1 again", acquired, ShouldBeFalse)
+		})
+		Convey("Leases with different names are independent", func() {
+			other := storage.Lease(t, "bs-1-ff00:0:111")
+			acquired, err := lease.Acquire(ctx, "bs-1", valid)
+			xtest.FailOnErr(t, err)
+			SoMsg("bs-1", acquired, ShouldBeTrue)
+			acquired, err = other.Acquire(ctx, "bs-2", valid)
+			xtest.FailOnErr(t, err)
+			SoMsg("bs-2", acquired, ShouldBeTrue)
+		})
+	})
+}
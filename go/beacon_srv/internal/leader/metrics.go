@@ -0,0 +1,34 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leader
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
+var metrics = newMetrics()
+
+type electorMetrics struct {
+	leader prometheus.Gauge
+}
+
+func newMetrics() electorMetrics {
+	return electorMetrics{
+		leader: prom.NewGauge("beacon_srv", "leader", "is_leader",
+			"Whether this instance is the leader, 0 means standby, 1 leader."),
+	}
+}
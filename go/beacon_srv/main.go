@@ -21,6 +21,7 @@ import (
 	"flag"
 	"fmt"
 	"hash"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sync"
@@ -37,6 +38,9 @@ import (
 	"github.com/scionproto/scion/go/beacon_srv/internal/config"
 	"github.com/scionproto/scion/go/beacon_srv/internal/ifstate"
 	"github.com/scionproto/scion/go/beacon_srv/internal/keepalive"
+	"github.com/scionproto/scion/go/beacon_srv/internal/leader"
+	"github.com/scionproto/scion/go/beacon_srv/internal/leader/leasepostgres"
+	"github.com/scionproto/scion/go/beacon_srv/internal/leader/leasesqlite"
 	"github.com/scionproto/scion/go/beacon_srv/internal/metrics"
 	"github.com/scionproto/scion/go/beacon_srv/internal/onehop"
 	"github.com/scionproto/scion/go/beacon_srv/internal/revocation"
@@ -53,12 +57,14 @@ import (
 	"github.com/scionproto/scion/go/lib/infra/modules/trust"
 	"github.com/scionproto/scion/go/lib/infra/modules/trust/trustdb"
 	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/keysigner"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/periodic"
 	"github.com/scionproto/scion/go/lib/scrypto"
 	"github.com/scionproto/scion/go/lib/snet"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
 	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/tracing"
 	"github.com/scionproto/scion/go/proto"
 )
 
@@ -84,10 +90,19 @@ func realMain() int {
 	fatal.Init()
 	env.AddFlags()
 	flag.BoolVar(&helpPoliciy, "help-policy", false, "Output sample policy file.")
+	flag.BoolVar(&inspectBeacons, "inspect-beacons", false,
+		"List the beacons in the beacon DB and exit.")
+	flag.StringVar(&explainPolicy, "explain", "", "Explain the beacon selection of the "+
+		"configured policy of the given type (e.g., Propagation) and exit.")
+	flag.StringVar(&explainEgress, "explain-egress", "",
+		"Explain the selection for the named egress policy. Requires -explain Propagation.")
 	flag.Parse()
 	if v, ok := checkFlags(&cfg); !ok {
 		return v
 	}
+	if inspectBeacons || explainPolicy != "" {
+		return runInspect()
+	}
 	if err := setupBasic(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 1
@@ -99,6 +114,11 @@ func realMain() int {
 		log.Crit("Setup failed", "err", err)
 		return 1
 	}
+	if err := cfg.Tracing.InitGlobalTracer(cfg.General.ID); err != nil {
+		log.Crit("Unable to initialize tracing", "err", err)
+		return 1
+	}
+	defer tracing.Close()
 	trustDB, err := cfg.TrustDB.New()
 	if err != nil {
 		log.Crit("Unable to initialize trustDB", "err", err)
@@ -131,6 +151,11 @@ func realMain() int {
 		log.Crit("Unable to find topo address")
 		return 1
 	}
+	admission, err := infraenv.NewAdmissionConfig(&cfg.Admission)
+	if err != nil {
+		log.Crit("Unable to create admission config", "err", err)
+		return 1
+	}
 	nc := infraenv.NetworkConfig{
 		IA:                    topo.ISD_AS,
 		Public:                env.GetPublicSnetAddress(topo.ISD_AS, topoAddress),
@@ -145,6 +170,7 @@ func realMain() int {
 		SVCResolutionFraction: cfg.QUIC.ResolutionFraction,
 		TrustStore:            trustStore,
 		SVCRouter:             messenger.NewSVCRouter(itopo.Provider()),
+		Admission:             admission,
 	}
 	msgr, err := nc.Messenger()
 	if err != nil {
@@ -190,8 +216,15 @@ func realMain() int {
 		log.Crit("Unable to create SCION packet conn", "err", err)
 		return 1
 	}
+	keys, err := loadKeys(topo.ISD_AS, trustDB)
+	if err != nil {
+		log.Crit("Unable to load keys", "err", err)
+		return 1
+	}
+	defer keys.Close()
 	tasks = &periodicTasks{
 		intfs:        intfs,
+		signKey:      keys.Sign,
 		conn:         conn.(*snet.SCIONPacketConn),
 		trustDB:      trustDB,
 		store:        store,
@@ -221,6 +254,32 @@ func realMain() int {
 		return 1
 	}
 	defer tasks.Kill()
+	adminSrv, err := startAdmin(intfs, tasks)
+	if err != nil {
+		log.Crit("Unable to start interface admin endpoint", "err", err)
+		return 1
+	}
+	if adminSrv != nil {
+		defer adminSrv.Close()
+	}
+	if cfg.BS.Leader.Enabled {
+		elector, err := newElector(topo.ISD_AS)
+		if err != nil {
+			log.Crit("Unable to initialize leader election", "err", err)
+			return 1
+		}
+		electorRunner := periodic.StartPeriodicTask(elector,
+			periodic.NewTicker(cfg.BS.Leader.RenewInterval.Duration),
+			cfg.BS.Leader.RenewInterval.Duration)
+		defer func() {
+			electorRunner.Kill()
+			ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+			defer cancelF()
+			if err := elector.Resign(ctx); err != nil {
+				log.Error("Unable to release lease", "err", err)
+			}
+		}()
+	}
 	select {
 	case <-environment.AppShutdownSignal:
 		// Whenever we receive a SIGINT or SIGTERM we exit without an error.
@@ -237,13 +296,18 @@ type segRegRunners struct {
 	core          bool
 }
 
+// Kill kills all registrars that have been started.
 func (s segRegRunners) Kill() {
-	if s.core {
-		s.coreRegistrar.Kill()
-		return
+	killRunners(s.coreRegistrar, s.upRegistrar, s.downRegistrar)
+}
+
+// killRunners kills all runners that are not nil.
+func killRunners(runners ...*periodic.Runner) {
+	for _, r := range runners {
+		if r != nil {
+			r.Kill()
+		}
 	}
-	s.upRegistrar.Kill()
-	s.downRegistrar.Kill()
 }
 
 type periodicTasks struct {
@@ -251,6 +315,7 @@ type periodicTasks struct {
 	conn            *snet.SCIONPacketConn
 	genMac          func() hash.Hash
 	trustDB         trustdb.TrustDB
+	signKey         keysigner.KeySigner
 	store           beaconstorage.Store
 	msgr            infra.Messenger
 	topoProvider    topology.Provider
@@ -269,6 +334,7 @@ type periodicTasks struct {
 
 	mtx     sync.Mutex
 	running bool
+	leader  bool
 }
 
 func (t *periodicTasks) Start() error {
@@ -279,15 +345,64 @@ func (t *periodicTasks) Start() error {
 		log.Warn("Trying to start task, but they are running! Ignored.")
 		return nil
 	}
+	var err error
+	if t.discovery, err = t.startDiscovery(); err != nil {
+		return err
+	}
+	t.beaconCleaner = periodic.StartPeriodicTask(
+		beaconstorage.NewBeaconCleaner(t.store),
+		periodic.NewTicker(30*time.Second), 30*time.Second,
+	)
+	t.revCleaner = periodic.StartPeriodicTask(
+		beaconstorage.NewRevocationCleaner(t.store),
+		periodic.NewTicker(5*time.Second), 5*time.Second,
+	)
+	t.running = true
+	if !cfg.BS.Leader.Enabled {
+		return t.startLeaderTasks()
+	}
+	return nil
+}
+
+// StartLeaderTasks starts the tasks that only run on the leader.
+func (t *periodicTasks) StartLeaderTasks() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if !t.running {
+		return common.NewBasicError("Periodic tasks not running", nil)
+	}
+	return t.startLeaderTasks()
+}
+
+// StopLeaderTasks stops the tasks that only run on the leader.
+func (t *periodicTasks) StopLeaderTasks() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.stopLeaderTasks()
+}
+
+// startLeaderTasks starts the keepalive sender, the revoker, the originator,
+// the propagator and the segment registrars. The caller must hold the lock.
+func (t *periodicTasks) startLeaderTasks() error {
+	if t.leader {
+		return nil
+	}
 	topo := t.topoProvider.Get()
 	topoAddress := topo.BS.GetById(cfg.General.ID)
 	if topoAddress == nil {
 		return common.NewBasicError("Unable to find topo address", nil)
 	}
-	var err error
-	if t.discovery, err = t.startDiscovery(); err != nil {
+	if err := t.startLeaderRunners(topoAddress); err != nil {
+		// Do not leave the runners that were already started behind.
+		t.killLeaderRunners()
 		return err
 	}
+	t.leader = true
+	return nil
+}
+
+func (t *periodicTasks) startLeaderRunners(topoAddress *topology.TopoAddr) error {
+	var err error
 	if t.registrars, err = t.startSegRegRunners(); err != nil {
 		return err
 	}
@@ -303,18 +418,28 @@ func (t *periodicTasks) Start() error {
 	if t.propagator, err = t.startPropagator(topoAddress); err != nil {
 		return err
 	}
-	t.beaconCleaner = periodic.StartPeriodicTask(
-		beaconstorage.NewBeaconCleaner(t.store),
-		periodic.NewTicker(30*time.Second), 30*time.Second,
-	)
-	t.revCleaner = periodic.StartPeriodicTask(
-		beaconstorage.NewRevocationCleaner(t.store),
-		periodic.NewTicker(5*time.Second), 5*time.Second,
-	)
-	t.running = true
 	return nil
 }
 
+// stopLeaderTasks stops the tasks started by startLeaderTasks. The caller must
+// hold the lock.
+func (t *periodicTasks) stopLeaderTasks() {
+	if !t.leader {
+		return
+	}
+	t.killLeaderRunners()
+	t.leader = false
+}
+
+// killLeaderRunners kills the leader runners that have been started and
+// resets them. The caller must hold the lock.
+func (t *periodicTasks) killLeaderRunners() {
+	t.registrars.Kill()
+	killRunners(t.revoker, t.keepalive, t.originator, t.propagator)
+	t.originator, t.propagator, t.revoker, t.keepalive = nil, nil, nil, nil
+	t.registrars = segRegRunners{}
+}
+
 func (t *periodicTasks) startDiscovery() (idiscovery.Runners, error) {
 	d, err := idiscovery.StartRunners(cfg.Discovery, discovery.Full, idiscovery.TopoHandlers{}, nil)
 	if err != nil {
@@ -475,25 +600,44 @@ func (t *periodicTasks) startRegistrar(topo *topology.Topo,
 		cfg.BS.RegistrationInterval.Duration), nil
 }
 
-func (t *periodicTasks) createSigner(topo *topology.Topo) (infra.Signer, error) {
-	dir := filepath.Join(cfg.General.ConfigDir, "keys")
-	cfg, err := keyconf.Load(dir, false, false, false, false)
+// loadKeys loads the AS signing key with the algorithm that is specified by
+// the local certificate chain.
+func loadKeys(ia addr.IA, trustDB trustdb.TrustDB) (*keysigner.Keys, error) {
+	ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+	defer cancelF()
+	algos, err := trust.KeyAlgos(ctx, ia, trustDB)
 	if err != nil {
-		return nil, common.NewBasicError("Unable to load key config", err)
+		return nil, err
 	}
+	return cfg.Keys.Load(filepath.Join(cfg.General.ConfigDir, "keys"), algos, false)
+}
+
+func (t *periodicTasks) createSigner(topo *topology.Topo) (infra.Signer, error) {
 	ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
 	defer cancelF()
 	meta, err := trust.CreateSignMeta(ctx, topo.ISD_AS, t.trustDB)
 	if err != nil {
 		return nil, common.NewBasicError("Unable to create sign meta", err)
 	}
-	signer, err := trust.NewBasicSigner(cfg.SignKey, meta)
+	signer, err := trust.NewKeySigner(t.signKey, meta)
 	if err != nil {
 		return nil, common.NewBasicError("Unable to create signer", err)
 	}
 	return signer, nil
 }
 
+// triggerRevoker runs the revoker immediately, if the tasks are running.
+func (t *periodicTasks) triggerRevoker() {
+	if t == nil {
+		return
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.running && t.leader {
+		t.revoker.TriggerRun()
+	}
+}
+
 func (t *periodicTasks) Kill() {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
@@ -501,16 +645,80 @@ func (t *periodicTasks) Kill() {
 		log.Warn("Trying to stop tasks, but they are not running! Ignored.")
 		return
 	}
+	t.stopLeaderTasks()
 	t.discovery.Kill()
-	t.keepalive.Kill()
-	if t.originator != nil {
-		t.originator.Kill()
-	}
 	t.beaconCleaner.Kill()
 	t.revCleaner.Kill()
 	t.running = false
 }
 
+// startAdmin serves the interface admin endpoint on the configured address.
+// It returns nil if no address is configured.
+func startAdmin(intfs *ifstate.Interfaces, t *periodicTasks) (*http.Server, error) {
+	if cfg.BS.AdminAddress == "" {
+		return nil, nil
+	}
+	ln, err := ifstate.ListenAdmin(cfg.BS.AdminAddress)
+	if err != nil {
+		return nil, err
+	}
+	handler := &ifstate.AdminHandler{
+		Intfs:    intfs,
+		OnRevoke: t.triggerRevoker,
+	}
+	mux := http.NewServeMux()
+	mux.Handle(ifstate.AdminPath, handler)
+	mux.Handle(ifstate.AdminPath+"/", handler)
+	srv := &http.Server{Handler: mux}
+	go func() {
+		defer log.LogPanicAndExit()
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			fatal.Fatal(common.NewBasicError("Interface admin endpoint failed", err))
+		}
+	}()
+	log.Info("Serving interface admin endpoint", "addr", ln.Addr())
+	return srv, nil
+}
+
+// newElector creates the leader elector. On election, the interface states are
+// reset and the leader tasks are started. On demotion, they are stopped.
+func newElector(ia addr.IA) (*leader.Elector, error) {
+	lcfg := cfg.BS.Leader
+	connection := lcfg.Connection
+	if connection == "" {
+		connection = cfg.BeaconDB.Connection()
+	}
+	name := fmt.Sprintf("beacon_srv-%s", ia)
+	var lease leader.Lease
+	var err error
+	switch lcfg.Backend {
+	case beaconstorage.BackendSqlite:
+		lease, err = leasesqlite.New(connection, name)
+	case beaconstorage.BackendPostgres:
+		lease, err = leasepostgres.New(connection, name)
+	default:
+		return nil, common.NewBasicError("Unsupported leader backend", nil,
+			"backend", lcfg.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &leader.Elector{
+		Lease:     lease,
+		ID:        cfg.General.ID,
+		LeaseTime: lcfg.LeaseTime.Duration,
+		OnElected: func() {
+			intfs.Reset()
+			if err := tasks.StartLeaderTasks(); err != nil {
+				fatal.Fatal(common.NewBasicError("Unable to start leader tasks", err))
+			}
+		},
+		OnDemoted: func() {
+			tasks.StopLeaderTasks()
+		},
+	}, nil
+}
+
 func macGenFactory() (func() hash.Hash, error) {
 	mk, err := keyconf.LoadMaster(filepath.Join(cfg.General.ConfigDir, "keys"))
 	if err != nil {
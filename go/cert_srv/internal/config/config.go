@@ -22,6 +22,7 @@ import (
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/env"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery"
+	"github.com/scionproto/scion/go/lib/keysigner"
 	"github.com/scionproto/scion/go/lib/scrypto/cert"
 	"github.com/scionproto/scion/go/lib/truststorage"
 	"github.com/scionproto/scion/go/lib/util"
@@ -51,8 +52,11 @@ type Config struct {
 	Metrics   env.Metrics
 	QUIC      env.QUIC         `toml:"quic"`
 	Sciond    env.SciondClient `toml:"sd_client"`
+	Admission env.Admission
+	Tracing   env.Tracing
 	TrustDB   truststorage.TrustDBConf
 	Discovery idiscovery.Config
+	Keys      keysigner.Config
 	CS        CSConfig
 }
 
@@ -61,9 +65,11 @@ func (cfg *Config) InitDefaults() {
 		&cfg.General,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.Tracing,
 		&cfg.Sciond,
 		&cfg.TrustDB,
 		&cfg.Discovery,
+		&cfg.Keys,
 		&cfg.CS,
 	)
 }
@@ -73,9 +79,12 @@ func (cfg *Config) Validate() error {
 		&cfg.General,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.Admission,
+		&cfg.Tracing,
 		&cfg.Sciond,
 		&cfg.TrustDB,
 		&cfg.Discovery,
+		&cfg.Keys,
 		&cfg.CS,
 	)
 }
@@ -86,8 +95,11 @@ func (cfg *Config) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 		&cfg.Sciond,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.Admission,
+		&cfg.Tracing,
 		&cfg.TrustDB,
 		&cfg.Discovery,
+		&cfg.Keys,
 		&cfg.CS,
 	)
 }
@@ -25,6 +25,7 @@ import (
 
 	"github.com/scionproto/scion/go/lib/env/envtest"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery/idiscoverytest"
+	"github.com/scionproto/scion/go/lib/keysigner/keysignertest"
 	"github.com/scionproto/scion/go/lib/truststorage/truststoragetest"
 )
 
@@ -78,6 +79,7 @@ func InitTestConfig(cfg *Config) {
 	envtest.InitTest(&cfg.General, &cfg.Logging, &cfg.Metrics, &cfg.Sciond)
 	truststoragetest.InitTestConfig(&cfg.TrustDB)
 	idiscoverytest.InitTestConfig(&cfg.Discovery)
+	keysignertest.InitTestConfig(&cfg.Keys)
 	InitTestCSConfig(&cfg.CS)
 }
 
@@ -89,6 +91,7 @@ func CheckTestConfig(cfg *Config, id string) {
 	envtest.CheckTest(&cfg.General, &cfg.Logging, &cfg.Metrics, &cfg.Sciond, id)
 	truststoragetest.CheckTestConfig(&cfg.TrustDB, id)
 	idiscoverytest.CheckTestConfig(&cfg.Discovery)
+	keysignertest.CheckTestConfig(&cfg.Keys)
 	CheckTestCSConfig(&cfg.CS)
 }
 
@@ -23,6 +23,8 @@ import (
 	"github.com/scionproto/scion/go/lib/infra/modules/trust"
 	"github.com/scionproto/scion/go/lib/infra/modules/trust/trustdb"
 	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/keysigner"
+	"github.com/scionproto/scion/go/lib/scrypto"
 )
 
 type State struct {
@@ -30,9 +32,11 @@ type State struct {
 	Store *trust.Store
 	// TrustDB is the trust DB.
 	TrustDB trustdb.TrustDB
-	// keyConf contains the AS level keys.
+	// keyConf contains the AS level keys, except for the signing keys.
 	keyConf *keyconf.Conf
-	// keyConfLock guards KeyConf.
+	// keys contains the AS signing key and the issuer signing key.
+	keys *keysigner.Keys
+	// keyConfLock guards KeyConf and keys.
 	keyConfLock sync.RWMutex
 	// signer is used to sign ctrl payloads.
 	signer infra.Signer
@@ -44,41 +48,69 @@ type State struct {
 	verifierLock sync.RWMutex
 }
 
+// LoadState loads the state. The signing keys are loaded with the algorithms
+// in algos.
 func LoadState(confDir string, isCore bool, trustDB trustdb.TrustDB,
-	trustStore *trust.Store) (*State, error) {
+	trustStore *trust.Store, keysCfg *keysigner.Config, algos keyconf.Algos) (*State, error) {
 
 	s := &State{
 		Store:   trustStore,
 		TrustDB: trustDB,
 	}
-	if err := s.loadKeyConf(confDir, isCore); err != nil {
+	if err := s.loadKeyConf(confDir, isCore, keysCfg, algos); err != nil {
 		return nil, err
 	}
 	return s, nil
 }
 
-// loadKeyConf loads the key configuration.
-func (s *State) loadKeyConf(confDir string, isCore bool) error {
+// loadKeyConf loads the key configuration. The signing keys are loaded from
+// the configured key backend.
+func (s *State) loadKeyConf(confDir string, isCore bool, keysCfg *keysigner.Config,
+	algos keyconf.Algos) error {
+
 	var err error
-	s.keyConf, err = keyconf.Load(filepath.Join(confDir, "keys"), isCore, isCore, false, true)
+	dir := filepath.Join(confDir, "keys")
+	s.keyConf = &keyconf.Conf{}
+	s.keyConf.DecryptKey, err = keyconf.LoadKey(filepath.Join(dir, keyconf.DecKeyFile),
+		scrypto.Curve25519xSalsa20Poly1305)
 	if err != nil {
 		return common.NewBasicError(ErrorKeyConf, err)
 	}
+	if isCore {
+		s.keyConf.OnRootKey, err = keyconf.LoadKey(filepath.Join(dir, keyconf.OnKeyFile),
+			algos.OnRoot)
+		if err != nil {
+			return common.NewBasicError(ErrorKeyConf, err)
+		}
+	}
+	if s.keyConf.Master, err = keyconf.LoadMaster(dir); err != nil {
+		return common.NewBasicError(ErrorKeyConf, err)
+	}
+	if s.keys, err = keysCfg.Load(dir, algos, isCore); err != nil {
+		return common.NewBasicError(ErrorKeyConf, err)
+	}
 	return nil
 }
 
 // GetSigningKey returns the signing key of the current key configuration.
-func (s *State) GetSigningKey() common.RawBytes {
+func (s *State) GetSigningKey() keysigner.KeySigner {
 	s.keyConfLock.RLock()
 	defer s.keyConfLock.RUnlock()
-	return s.keyConf.SignKey
+	return s.keys.Sign
+}
+
+// Close releases the resources held by the key backend.
+func (s *State) Close() error {
+	s.keyConfLock.Lock()
+	defer s.keyConfLock.Unlock()
+	return s.keys.Close()
 }
 
 // GetIssSigningKey returns the issuer signing key of the current key configuration.
-func (s *State) GetIssSigningKey() common.RawBytes {
+func (s *State) GetIssSigningKey() keysigner.KeySigner {
 	s.keyConfLock.RLock()
 	defer s.keyConfLock.RUnlock()
-	return s.keyConf.IssSigKey
+	return s.keys.IssSig
 }
 
 // GetDecryptKey returns the decryption key of the current key configuration.
@@ -20,7 +20,9 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/keysigner"
 	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/xtest"
 )
 
 func TestLoadState(t *testing.T) {
@@ -31,26 +33,32 @@ func TestLoadState(t *testing.T) {
 	asSig, _ := keyconf.LoadKey("testdata/keys/as-sig.seed", scrypto.Ed25519)
 	issSig, _ := keyconf.LoadKey("testdata/keys/core-sig.seed", scrypto.Ed25519)
 	online, _ := keyconf.LoadKey("testdata/keys/online-root.seed", scrypto.Ed25519)
+	asSigner, err := keysigner.NewRaw(asSig, scrypto.Ed25519)
+	xtest.FailOnErr(t, err)
+	issSigner, err := keysigner.NewRaw(issSig, scrypto.Ed25519)
+	xtest.FailOnErr(t, err)
+	keysCfg := &keysigner.Config{}
+	keysCfg.InitDefaults()
 	Convey("Load core state", t, func() {
-		state, err := LoadState("testdata", true, nil, nil)
+		state, err := LoadState("testdata", true, nil, nil, keysCfg, keyconf.DefaultAlgos)
 		SoMsg("err", err, ShouldBeNil)
 		SoMsg("Master0", state.keyConf.Master.Key0, ShouldResemble, mstr0)
 		SoMsg("Master1", state.keyConf.Master.Key1, ShouldResemble, mstr1)
 		SoMsg("Decrypt", state.keyConf.DecryptKey, ShouldResemble, dcrpt)
-		SoMsg("AS-Sign", state.keyConf.SignKey, ShouldResemble, asSig)
-		SoMsg("Issuer-Sign", state.keyConf.IssSigKey, ShouldResemble, issSig)
+		SoMsg("AS-Sign", state.keys.Sign, ShouldResemble, asSigner)
+		SoMsg("Issuer-Sign", state.keys.IssSig, ShouldResemble, issSigner)
 		SoMsg("Online", state.keyConf.OnRootKey, ShouldResemble, online)
 		SoMsg("Offline", state.keyConf.OffRootKey, ShouldBeZeroValue)
 	})
 
 	Convey("Load non-core state", t, func() {
-		state, err := LoadState("testdata", false, nil, nil)
+		state, err := LoadState("testdata", false, nil, nil, keysCfg, keyconf.DefaultAlgos)
 		SoMsg("err", err, ShouldBeNil)
 		SoMsg("Master0", state.keyConf.Master.Key0, ShouldResemble, mstr0)
 		SoMsg("Master1", state.keyConf.Master.Key1, ShouldResemble, mstr1)
 		SoMsg("Decrypt", state.keyConf.DecryptKey, ShouldResemble, dcrpt)
-		SoMsg("AS-Sign", state.keyConf.SignKey, ShouldResemble, asSig)
-		SoMsg("Issuer-Sign", state.keyConf.IssSigKey, ShouldBeZeroValue)
+		SoMsg("AS-Sign", state.keys.Sign, ShouldResemble, asSigner)
+		SoMsg("Issuer-Sign", state.keys.IssSig, ShouldBeNil)
 		SoMsg("Online", state.keyConf.OnRootKey, ShouldBeZeroValue)
 		SoMsg("Offline", state.keyConf.OffRootKey, ShouldBeZeroValue)
 	})
@@ -184,7 +184,8 @@ func (h *Handler) issueChain(ctx context.Context, c *cert.Certificate,
 	if chain.Issuer.ExpirationTime < chain.Leaf.ExpirationTime {
 		chain.Leaf.ExpirationTime = chain.Issuer.ExpirationTime
 	}
-	if err = chain.Leaf.Sign(h.State.GetIssSigningKey(), chain.Issuer.SignAlgorithm); err != nil {
+	err = chain.Leaf.SignWith(h.State.GetIssSigningKey(), chain.Issuer.SignAlgorithm)
+	if err != nil {
 		return nil, err
 	}
 	err = chain.Leaf.Verify(c.Subject, issCert.SubjectSignKey, issCert.SignAlgorithm)
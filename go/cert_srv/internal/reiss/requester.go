@@ -19,8 +19,6 @@ import (
 	"context"
 	"time"
 
-	"golang.org/x/crypto/ed25519"
-
 	"github.com/scionproto/scion/go/cert_srv/internal/config"
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
@@ -83,7 +81,7 @@ func (r *Requester) sendReq(ctx context.Context, chain *cert.Chain) (bool, error
 	c.IssuingTime = util.TimeToSecs(time.Now())
 	c.ExpirationTime = c.IssuingTime + (chain.Leaf.ExpirationTime - chain.Leaf.IssuingTime)
 	c.Version++
-	if err := c.Sign(r.State.GetSigningKey(), chain.Leaf.SignAlgorithm); err != nil {
+	if err := c.SignWith(r.State.GetSigningKey(), chain.Leaf.SignAlgorithm); err != nil {
 		return true, common.NewBasicError("Unable to sign certificate", err)
 	}
 	raw, err := c.JSON(false)
@@ -119,7 +117,7 @@ func (r *Requester) handleRep(ctx context.Context, rep *cert_mgmt.ChainIssRep) (
 	if err != nil {
 		return true, common.NewBasicError("Unable create sign meta", err)
 	}
-	signer, err := trust.NewBasicSigner(r.State.GetSigningKey(), meta)
+	signer, err := trust.NewKeySigner(r.State.GetSigningKey(), meta)
 	if err != nil {
 		return true, common.NewBasicError("Unable to create new signer", err)
 	}
@@ -132,8 +130,7 @@ func (r *Requester) handleRep(ctx context.Context, rep *cert_mgmt.ChainIssRep) (
 
 // validateRep validates that the received certificate chain can be added to the trust store.
 func (r *Requester) validateRep(ctx context.Context, chain *cert.Chain) error {
-	verKey := common.RawBytes(ed25519.PrivateKey(
-		r.State.GetSigningKey()).Public().(ed25519.PublicKey))
+	verKey := r.State.GetSigningKey().PublicKey()
 	if !bytes.Equal(chain.Leaf.SubjectSignKey, verKey) {
 		return common.NewBasicError("Invalid SubjectSignKey", nil, "expected",
 			verKey, "actual", chain.Leaf.SubjectSignKey)
@@ -96,7 +96,7 @@ func (s *Self) createLeafCert(ctx context.Context, leaf *cert.Certificate) error
 	if chain.Issuer.ExpirationTime < chain.Leaf.ExpirationTime {
 		chain.Leaf.ExpirationTime = chain.Issuer.ExpirationTime
 	}
-	if err := chain.Leaf.Sign(s.State.GetIssSigningKey(), issCrt.SignAlgorithm); err != nil {
+	if err := chain.Leaf.SignWith(s.State.GetIssSigningKey(), issCrt.SignAlgorithm); err != nil {
 		return common.NewBasicError("Unable to sign leaf certificate", err, "chain", chain)
 	}
 	if err := trust.VerifyChain(ctx, s.IA, chain, s.State.Store); err != nil {
@@ -110,7 +110,7 @@ func (s *Self) createLeafCert(ctx context.Context, leaf *cert.Certificate) error
 	if err != nil {
 		return common.NewBasicError("Unable to create sign meta", err)
 	}
-	signer, err := trust.NewBasicSigner(s.State.GetSigningKey(), meta)
+	signer, err := trust.NewKeySigner(s.State.GetSigningKey(), meta)
 	if err != nil {
 		return common.NewBasicError("Unable to create new signer", err)
 	}
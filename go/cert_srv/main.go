@@ -37,6 +37,7 @@ import (
 	"github.com/scionproto/scion/go/lib/infra/modules/trust/trustdb"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/periodic"
+	"github.com/scionproto/scion/go/lib/tracing"
 )
 
 var (
@@ -78,6 +79,11 @@ func realMain() int {
 		log.Crit("Setup failed", "err", err)
 		return 1
 	}
+	if err := cfg.Tracing.InitGlobalTracer(cfg.General.ID); err != nil {
+		log.Crit("Unable to initialize tracing", "err", err)
+		return 1
+	}
+	defer tracing.Close()
 	// Start the periodic reissuance task.
 	startReissRunner()
 	// Start the periodic fetching from discovery service.
@@ -195,4 +201,7 @@ func stop() {
 	discRunners.Kill()
 	msgr.CloseServer()
 	trustDB.Close()
+	if err := state.Close(); err != nil {
+		log.Error("Unable to close key backend", "err", err)
+	}
 }
@@ -106,20 +106,27 @@ func initState(cfg *config.Config, router snet.Router) error {
 	if err != nil {
 		return common.NewBasicError("Unable to initialize trust store", err)
 	}
-	state, err = config.LoadState(cfg.General.ConfigDir, topo.Core,
-		trustDB, trustStore)
-	if err != nil {
-		return common.NewBasicError("Unable to load CS state", err)
-	}
-	err = state.Store.LoadAuthoritativeTRC(filepath.Join(cfg.General.ConfigDir, "certs"))
+	err = trustStore.LoadAuthoritativeTRC(filepath.Join(cfg.General.ConfigDir, "certs"))
 	if err != nil {
 		return common.NewBasicError("Unable to load local TRC", err)
 	}
-	err = state.Store.LoadAuthoritativeChain(
+	err = trustStore.LoadAuthoritativeChain(
 		filepath.Join(cfg.General.ConfigDir, "certs"))
 	if err != nil {
 		return common.NewBasicError("Unable to load local Chain", err)
 	}
+	// The algorithms of the signing keys are specified by the local chain and TRC.
+	ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+	defer cancelF()
+	algos, err := trust.KeyAlgos(ctx, topo.ISD_AS, trustDB)
+	if err != nil {
+		return common.NewBasicError("Unable to determine key algorithms", err)
+	}
+	state, err = config.LoadState(cfg.General.ConfigDir, topo.Core,
+		trustDB, trustStore, &cfg.Keys, algos)
+	if err != nil {
+		return common.NewBasicError("Unable to load CS state", err)
+	}
 	if err = setDefaultSignerVerifier(state, topo.ISD_AS); err != nil {
 		return common.NewBasicError("Unable to set default signer and verifier", err)
 	}
@@ -135,7 +142,7 @@ func setDefaultSignerVerifier(c *config.State, pubIA addr.IA) error {
 	if err != nil {
 		return err
 	}
-	signer, err := trust.NewBasicSigner(c.GetSigningKey(), meta)
+	signer, err := trust.NewKeySigner(c.GetSigningKey(), meta)
 	if err != nil {
 		return err
 	}
@@ -152,6 +159,10 @@ func setMessenger(cfg *config.Config, router snet.Router) error {
 	if topoAddress == nil {
 		return common.NewBasicError("Unable to find topo address", nil)
 	}
+	admission, err := infraenv.NewAdmissionConfig(&cfg.Admission)
+	if err != nil {
+		return common.NewBasicError("Unable to create admission config", err)
+	}
 	nc := infraenv.NetworkConfig{
 		IA:                    topo.ISD_AS,
 		Public:                env.GetPublicSnetAddress(topo.ISD_AS, topoAddress),
@@ -167,8 +178,8 @@ func setMessenger(cfg *config.Config, router snet.Router) error {
 		TrustStore:            state.Store,
 		Router:                router,
 		SVCRouter:             messenger.NewSVCRouter(itopo.Provider()),
+		Admission:             admission,
 	}
-	var err error
 	msgr, err = nc.Messenger()
 	if err != nil {
 		return common.NewBasicError("Unable to initialize SCION Messenger", err)
@@ -78,7 +78,8 @@ var (
 
 func init() {
 	flag.Var((*snet.Addr)(&local), "local", "(Mandatory) address to listen on")
-	flag.Var((*snet.Addr)(&remote), "remote", "(Mandatory for clients) address to connect to")
+	flag.Var((*snet.Addr)(&remote), "remote",
+		"(Mandatory for clients) address or host name to connect to")
 }
 
 func main() {
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/scionproto/scion/go/godispatcher/internal/registration"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/env"
@@ -26,6 +27,12 @@ import (
 	"github.com/scionproto/scion/go/lib/sock/reliable"
 )
 
+// Default rate limit for SCMP errors sent by the dispatcher.
+const (
+	DefaultSCMPErrorRate  = 100
+	DefaultSCMPErrorBurst = 20
+)
+
 var _ config.Config = (*Config)(nil)
 
 type Config struct {
@@ -44,6 +51,17 @@ type Config struct {
 		// DeleteSocket specifies whether the dispatcher should delete the
 		// socket file prior to attempting to create a new one.
 		DeleteSocket bool
+		// SCMPErrorRate is the average number of SCMP errors per second the
+		// dispatcher sends for packets that cannot be delivered to an
+		// application. A negative value disables SCMP errors. (default 100)
+		SCMPErrorRate float64
+		// SCMPErrorBurst is the maximum number of SCMP errors sent at once.
+		// (default 20)
+		SCMPErrorBurst int
+		// SVCAnycast is the policy used to select the application an anycast
+		// SVC packet is delivered to, one of round_robin, least_loaded or
+		// bind_ip. (default round_robin)
+		SVCAnycast string
 	}
 }
 
@@ -54,6 +72,15 @@ func (cfg *Config) InitDefaults() {
 	if cfg.Dispatcher.OverlayPort == 0 {
 		cfg.Dispatcher.OverlayPort = overlay.EndhostPort
 	}
+	if cfg.Dispatcher.SCMPErrorRate == 0 {
+		cfg.Dispatcher.SCMPErrorRate = DefaultSCMPErrorRate
+	}
+	if cfg.Dispatcher.SCMPErrorBurst == 0 {
+		cfg.Dispatcher.SCMPErrorBurst = DefaultSCMPErrorBurst
+	}
+	if cfg.Dispatcher.SVCAnycast == "" {
+		cfg.Dispatcher.SVCAnycast = registration.AnycastRoundRobin.String()
+	}
 }
 
 func (cfg *Config) Validate() error {
@@ -66,9 +93,21 @@ func (cfg *Config) Validate() error {
 	if cfg.Dispatcher.ID == "" {
 		return common.NewBasicError("ID must be set", nil)
 	}
+	if cfg.Dispatcher.SCMPErrorBurst < 0 {
+		return common.NewBasicError("SCMPErrorBurst must not be negative", nil,
+			"burst", cfg.Dispatcher.SCMPErrorBurst)
+	}
+	if _, err := cfg.AnycastPolicy(); err != nil {
+		return err
+	}
 	return config.ValidateAll(&cfg.Logging, &cfg.Metrics)
 }
 
+// AnycastPolicy returns the policy configured in SVCAnycast.
+func (cfg *Config) AnycastPolicy() (registration.AnycastPolicy, error) {
+	return registration.AnycastPolicyFromString(cfg.Dispatcher.SVCAnycast)
+}
+
 func (cfg *Config) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 	dispSampler := config.StringSampler{
 		Text: fmt.Sprintf(dispSample, idSample),
@@ -21,6 +21,7 @@ import (
 	"github.com/BurntSushi/toml"
 	. "github.com/smartystreets/goconvey/convey"
 
+	"github.com/scionproto/scion/go/godispatcher/internal/registration"
 	"github.com/scionproto/scion/go/lib/env/envtest"
 	"github.com/scionproto/scion/go/lib/overlay"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
@@ -44,6 +45,9 @@ func InitTestConfig(cfg *Config) {
 	envtest.InitTest(nil, &cfg.Logging, &cfg.Metrics, nil)
 	cfg.Dispatcher.DeleteSocket = true
 	cfg.Dispatcher.PerfData = "Invalid"
+	cfg.Dispatcher.SCMPErrorRate = -1
+	cfg.Dispatcher.SCMPErrorBurst = 1
+	cfg.Dispatcher.SVCAnycast = "bind_ip"
 }
 
 func CheckTestConfig(cfg *Config, id string) {
@@ -54,4 +58,10 @@ func CheckTestConfig(cfg *Config, id string) {
 	SoMsg("OverlayPort", cfg.Dispatcher.OverlayPort, ShouldEqual, overlay.EndhostPort)
 	SoMsg("PerfData", cfg.Dispatcher.PerfData, ShouldBeEmpty)
 	SoMsg("DeleteSocket", cfg.Dispatcher.DeleteSocket, ShouldBeFalse)
+	SoMsg("SCMPErrorRate", cfg.Dispatcher.SCMPErrorRate, ShouldEqual, DefaultSCMPErrorRate)
+	SoMsg("SCMPErrorBurst", cfg.Dispatcher.SCMPErrorBurst, ShouldEqual, DefaultSCMPErrorBurst)
+	SoMsg("SVCAnycast", cfg.Dispatcher.SVCAnycast, ShouldEqual, "round_robin")
+	policy, err := cfg.AnycastPolicy()
+	SoMsg("AnycastPolicy err", err, ShouldBeNil)
+	SoMsg("AnycastPolicy", policy, ShouldEqual, registration.AnycastRoundRobin)
 }
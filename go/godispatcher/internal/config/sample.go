@@ -33,4 +33,19 @@ PerfData = ""
 # Set DeleteSock to true to have the Dispatcher remove the socket file (if it
 # exists) on start. (default false)
 DeleteSocket = false
+
+# SCMPErrorRate is the average number of SCMP errors per second sent for
+# packets that cannot be delivered to an application. A negative value
+# disables SCMP errors. (default 100)
+SCMPErrorRate = 100.0
+
+# SCMPErrorBurst is the maximum number of SCMP errors sent at once. (default 20)
+SCMPErrorBurst = 20
+
+# SVCAnycast is the policy used to select the application an anycast SVC
+# packet is delivered to. round_robin rotates between all applications
+# registered for the service, least_loaded selects the application with the
+# fewest queued packets, and bind_ip selects between the applications bound to
+# the destination IP address of the overlay packet. (default round_robin)
+SVCAnycast = "round_robin"
 `
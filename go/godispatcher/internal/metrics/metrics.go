@@ -32,6 +32,7 @@ const (
 const (
 	IncomingPacketOutcome = "incoming_packet_outcome"
 	OpenConnectionType    = "open_connection_type"
+	SCMPErrorOutcome      = "scmp_error_outcome"
 )
 
 // Packet outcome labels
@@ -41,12 +42,20 @@ const (
 	PacketOutcomeOk            = "ok"
 )
 
+// SCMP error outcome labels
+const (
+	SCMPErrorOutcomeSent        = "sent"
+	SCMPErrorOutcomeRateLimited = "rate_limited"
+	SCMPErrorOutcomeError       = "error"
+)
+
 var (
 	OutgoingPacketsTotal prometheus.Counter
 	IncomingBytesTotal   prometheus.Counter
 	OutgoingBytesTotal   prometheus.Counter
 	IncomingPackets      *prometheus.CounterVec
 	OpenSockets          *prometheus.GaugeVec
+	SCMPErrors           *prometheus.CounterVec
 )
 
 // GetOpenConnectionLabel returns an SVC address string representation for sockets
@@ -80,4 +89,7 @@ func initMetrics(elem string) {
 		"Total packets received from the network.", []string{IncomingPacketOutcome})
 	OpenSockets = prom.NewGaugeVec(namespace, "", "open_application_connections",
 		"Number of sockets currently opened by applications.", []string{OpenConnectionType})
+	SCMPErrors = prom.NewCounterVec(namespace, "", "scmp_errors_total",
+		"Total SCMP errors generated for packets that could not be delivered.",
+		[]string{SCMPErrorOutcome})
 }
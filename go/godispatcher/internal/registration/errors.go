@@ -24,4 +24,5 @@ const (
 	ErrNilAddress         = "nil address"
 	ErrSvcNone            = "svc none"
 	ErrNoPorts            = "no free ports"
+	ErrUnknownPolicy      = "unknown anycast policy"
 )
@@ -77,9 +77,9 @@ type IATable interface {
 	LookupPublic(ia addr.IA, public *net.UDPAddr) (interface{}, bool)
 	// LookupService returns the entries associated with svc and bind.
 	//
-	// If SVC is an anycast address, at most one entry is returned. The bind
-	// address is used to narrow down the set of possible entries. If multiple
-	// entries exist, one is selected arbitrarily.
+	// If SVC is an anycast address, at most one entry is returned. The entry
+	// is selected according to the anycast policy of the table, see
+	// AnycastPolicy.
 	//
 	// Note that nil bind addresses are supported for anycasts (the address is
 	// in this case ignored), but support for this might be dropped in the
@@ -102,7 +102,13 @@ type IATable interface {
 //
 // If minPort is <= 0 or maxPort is > 65535, the function panics.
 func NewIATable(minPort, maxPort int) IATable {
-	return newIATable(minPort, maxPort)
+	return newIATable(minPort, maxPort, AnycastBindIP)
+}
+
+// NewIATableWithPolicy creates a new UDP/IP port registration table that uses
+// policy to select the entry anycasts are delivered to.
+func NewIATableWithPolicy(minPort, maxPort int, policy AnycastPolicy) IATable {
+	return newIATable(minPort, maxPort, policy)
 }
 
 var _ IATable = (*iaTable)(nil)
@@ -112,13 +118,15 @@ type iaTable struct {
 	ia      map[addr.IA]*Table
 	minPort int
 	maxPort int
+	policy  AnycastPolicy
 }
 
-func newIATable(minPort, maxPort int) *iaTable {
+func newIATable(minPort, maxPort int, policy AnycastPolicy) *iaTable {
 	return &iaTable{
 		ia:      make(map[addr.IA]*Table),
 		minPort: minPort,
 		maxPort: maxPort,
+		policy:  policy,
 	}
 }
 
@@ -135,7 +143,7 @@ func (t *iaTable) Register(ia addr.IA, public *net.UDPAddr, bind net.IP, svc add
 	}
 	table, ok := t.ia[ia]
 	if !ok {
-		table = NewTable(t.minPort, t.maxPort)
+		table = newTable(t.minPort, t.maxPort, t.policy)
 		t.ia[ia] = table
 	}
 	reference, err := table.Register(public, bind, svc, value)
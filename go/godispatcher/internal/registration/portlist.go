@@ -52,6 +52,22 @@ func (l *portList) Get() interface{} {
 	return v.(*listItem).value
 }
 
+// GetLeastLoaded returns the object with the lowest load. Objects that do not
+// implement Loader have a load of 0. Ties are broken in round-robin fashion.
+func (l *portList) GetLeastLoaded() interface{} {
+	if l.list == nil {
+		return nil
+	}
+	best, bestLoad := l.list, load(l.list)
+	for e := l.list.Next(); e != l.list; e = e.Next() {
+		if eLoad := load(e); eLoad < bestLoad {
+			best, bestLoad = e, eLoad
+		}
+	}
+	l.list = best.Next()
+	return best.Value.(*listItem).value
+}
+
 func (l *portList) Find(port int) bool {
 	var found bool
 	l.list.Do(
@@ -85,3 +101,10 @@ type listItem struct {
 	port  int
 	value interface{}
 }
+
+func load(element *ring.Ring) int {
+	if loader, ok := element.Value.(*listItem).value.(Loader); ok {
+		return loader.Load()
+	}
+	return 0
+}
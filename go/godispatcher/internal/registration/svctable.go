@@ -44,14 +44,22 @@ import (
 // from the UDP address. IP must not be zero (so binding to multiple interfaces
 // is not supported), and port must not be zero.
 //
-// Anycasting to a local application requires the service type (e.g., CS) and
-// the IP. This is because for SCION, the IP is selected remotely by the border
-// router. The local dispatcher then anycasts between all local ports listening on that IP.
+// Anycasts are delivered to a single entry, selected according to the
+// AnycastPolicy of the table. Multicasts are delivered to all entries of the
+// service.
 //
-// For example, in the table above, anycasting to CS-10.2.3.4 can either go to
-// entry 10.2.3.4:10080 or 10.2.3.4:10081. Anycasts are chosen in round-robin
-// fashion; the round-robin distribution is not strict, and can get skewed due
-// to registrations and frees.
+// With AnycastBindIP, anycasting to a local application requires the service
+// type (e.g., CS) and the IP. This is because for SCION, the IP is selected
+// remotely by the border router. The local dispatcher then anycasts between
+// all local ports listening on that IP. For example, in the table above,
+// anycasting to CS-10.2.3.4 can either go to entry 10.2.3.4:10080 or
+// 10.2.3.4:10081.
+//
+// With AnycastRoundRobin and AnycastLeastLoaded, the IP is ignored and all
+// entries of the service are considered.
+//
+// Round-robin distribution is not strict, and can get skewed due to
+// registrations and frees.
 type SVCTable interface {
 	// Register adds a new entry for the select svc, IP address and port. Both
 	// IPv4 and IPv6 are supported. IP addresses 0.0.0.0 and :: are not
@@ -65,12 +73,13 @@ type SVCTable interface {
 	Register(svc addr.HostSVC, address *net.UDPAddr, value interface{}) (Reference, error)
 	// Lookup returns the entries associated with svc and ip.
 	//
-	// If SVC is an anycast address, at most one entry is returned. The ip
-	// address is used in case to narrow down the set of possible entries. If
-	// multiple entries exist, one is selected arbitrarily.
+	// If SVC is an anycast address, at most one entry is returned. With
+	// AnycastBindIP, the ip address is used to narrow down the set of
+	// possible entries; other policies ignore it.
 	//
-	// Note that nil addresses are supported for anycasts (the address is then
-	// ignored), but support for this might be dropped in the future.
+	// Note that nil addresses are supported for anycasts with AnycastBindIP
+	// (the entries of an arbitrary IP are then used), but support for this
+	// might be dropped in the future.
 	//
 	// If SVC is a multicast address, more than one entry can be returned. The
 	// ip address is ignored in this case.
@@ -78,19 +87,76 @@ type SVCTable interface {
 	String() string
 }
 
+// AnycastPolicy determines which entry an anycast is delivered to.
+type AnycastPolicy int
+
+const (
+	// AnycastBindIP selects among the entries bound to the IP address the
+	// packet was sent to, in round-robin fashion.
+	AnycastBindIP AnycastPolicy = iota
+	// AnycastRoundRobin selects among all entries of the service in
+	// round-robin fashion.
+	AnycastRoundRobin
+	// AnycastLeastLoaded selects the entry of the service with the lowest
+	// load, see Loader.
+	AnycastLeastLoaded
+)
+
+var anycastPolicyNames = map[AnycastPolicy]string{
+	AnycastBindIP:      "bind_ip",
+	AnycastRoundRobin:  "round_robin",
+	AnycastLeastLoaded: "least_loaded",
+}
+
+// AnycastPolicyFromString parses the policy name s, e.g., round_robin.
+func AnycastPolicyFromString(s string) (AnycastPolicy, error) {
+	for p, name := range anycastPolicyNames {
+		if s == name {
+			return p, nil
+		}
+	}
+	return 0, common.NewBasicError(ErrUnknownPolicy, nil, "policy", s)
+}
+
+func (p AnycastPolicy) String() string {
+	if name, ok := anycastPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("AnycastPolicy(%d)", int(p))
+}
+
+// Loader is implemented by values whose load is taken into account by
+// AnycastLeastLoaded.
+type Loader interface {
+	// Load returns the current load, e.g., the number of queued packets.
+	Load() int
+}
+
+// NewSVCTable creates a table that uses AnycastBindIP.
 func NewSVCTable() SVCTable {
-	return newSvcTable()
+	return newSvcTable(AnycastBindIP)
+}
+
+// NewSVCTableWithPolicy creates a table that uses policy to select anycast
+// entries.
+func NewSVCTableWithPolicy(policy AnycastPolicy) SVCTable {
+	return newSvcTable(policy)
 }
 
 var _ SVCTable = (*svcTable)(nil)
 
 type svcTable struct {
 	m map[addr.HostSVC]unicastIpTable
+	// all contains the entries of each service, regardless of IP.
+	all    map[addr.HostSVC]*portList
+	policy AnycastPolicy
 }
 
-func newSvcTable() *svcTable {
+func newSvcTable(policy AnycastPolicy) *svcTable {
 	return &svcTable{
-		m: make(map[addr.HostSVC]unicastIpTable),
+		m:      make(map[addr.HostSVC]unicastIpTable),
+		all:    make(map[addr.HostSVC]*portList),
+		policy: policy,
 	}
 }
 
@@ -115,8 +181,12 @@ func (t *svcTable) Register(svc addr.HostSVC, address *net.UDPAddr,
 	if err != nil {
 		return nil, err
 	}
+	if _, ok := t.all[svc]; !ok {
+		t.all[svc] = newPortList()
+	}
+	allElement := t.all[svc].Insert(address.Port, value)
 	return &svcTableReference{
-		cleanF: t.buildCleanupCallback(svc, address.IP, element),
+		cleanF: t.buildCleanupCallback(svc, address.IP, element, allElement),
 	}, nil
 }
 
@@ -147,6 +217,17 @@ func (t *svcTable) multicast(svc addr.HostSVC) []interface{} {
 }
 
 func (t *svcTable) anycast(svc addr.HostSVC, ip net.IP) (interface{}, bool) {
+	switch t.policy {
+	case AnycastRoundRobin, AnycastLeastLoaded:
+		ports, ok := t.all[svc]
+		if !ok {
+			return nil, false
+		}
+		if t.policy == AnycastLeastLoaded {
+			return ports.GetLeastLoaded(), true
+		}
+		return ports.Get(), true
+	}
 	ipTable, ok := t.m[svc]
 	if !ok {
 		return nil, false
@@ -171,13 +252,20 @@ func (t *svcTable) String() string {
 	return fmt.Sprintf("%v", t.m)
 }
 
-func (t *svcTable) buildCleanupCallback(svc addr.HostSVC, ip net.IP, port *ring.Ring) func() {
+func (t *svcTable) buildCleanupCallback(svc addr.HostSVC, ip net.IP,
+	port, allPort *ring.Ring) func() {
+
 	return func() {
-		t.doCleanup(svc, ip, port)
+		t.doCleanup(svc, ip, port, allPort)
 	}
 }
 
-func (t *svcTable) doCleanup(svc addr.HostSVC, ip net.IP, port *ring.Ring) {
+func (t *svcTable) doCleanup(svc addr.HostSVC, ip net.IP, port, allPort *ring.Ring) {
+	allList := t.all[svc]
+	allList.Remove(allPort)
+	if allList.Len() == 0 {
+		delete(t.all, svc)
+	}
 	ipTable := t.m[svc]
 	portList := ipTable[ip.String()]
 	portList.Remove(port)
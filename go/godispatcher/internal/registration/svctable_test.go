@@ -294,6 +294,84 @@ func TestSVCTableFree(t *testing.T) {
 	})
 }
 
+func TestSVCTableAnycastPolicy(t *testing.T) {
+	ipOne, ipTwo := net.IP{10, 2, 3, 4}, net.IP{10, 5, 6, 7}
+	Convey("Given a round-robin table with entries on two IPs", t, func() {
+		table := NewSVCTableWithPolicy(AnycastRoundRobin)
+		refOne, err := table.Register(addr.SvcCS, &net.UDPAddr{IP: ipOne, Port: 10080}, "1")
+		xtest.FailOnErr(t, err)
+		_, err = table.Register(addr.SvcCS, &net.UDPAddr{IP: ipTwo, Port: 10080}, "2")
+		xtest.FailOnErr(t, err)
+		Convey("anycasting cycles between both IPs", func() {
+			checkAnyCastCycles(t,
+				func() []interface{} { return table.Lookup(addr.SvcCS, nil) },
+				[]string{"1", "2"})
+		})
+		Convey("anycasting ignores the IP", func() {
+			checkAnyCastCycles(t,
+				func() []interface{} { return table.Lookup(addr.SvcCS, ipOne) },
+				[]string{"1", "2"})
+		})
+		Convey("anycasting to a different SVC does not find an entry", func() {
+			So(table.Lookup(addr.SvcPS, nil), ShouldBeEmpty)
+		})
+		Convey("a multicast returns both values", func() {
+			So(table.Lookup(addr.SvcCS.Multicast(), nil), ShouldHaveLength, 2)
+		})
+		Convey("freed entries are no longer selected", func() {
+			refOne.Free()
+			checkAnyCastCycles(t,
+				func() []interface{} { return table.Lookup(addr.SvcCS, nil) },
+				[]string{"2"})
+		})
+	})
+	Convey("Given a least-loaded table", t, func() {
+		table := NewSVCTableWithPolicy(AnycastLeastLoaded)
+		values := []*testLoader{{name: "1", load: 3}, {name: "2", load: 1}, {name: "3", load: 1}}
+		for i, v := range values {
+			_, err := table.Register(addr.SvcCS,
+				&net.UDPAddr{IP: ipOne, Port: 10080 + i}, v)
+			xtest.FailOnErr(t, err)
+		}
+		lookup := func() string {
+			retValues := table.Lookup(addr.SvcCS, nil)
+			So(retValues, ShouldHaveLength, 1)
+			return retValues[0].(*testLoader).name
+		}
+		Convey("anycasting cycles between the least loaded entries", func() {
+			first, second := lookup(), lookup()
+			So([]string{first, second}, ShouldContain, "2")
+			So([]string{first, second}, ShouldContain, "3")
+		})
+		Convey("anycasting follows load changes", func() {
+			values[0].load = 0
+			So(lookup(), ShouldEqual, "1")
+			So(lookup(), ShouldEqual, "1")
+		})
+	})
+}
+
+func TestAnycastPolicyFromString(t *testing.T) {
+	Convey("AnycastPolicyFromString", t, func() {
+		for _, p := range []AnycastPolicy{AnycastBindIP, AnycastRoundRobin, AnycastLeastLoaded} {
+			parsed, err := AnycastPolicyFromString(p.String())
+			SoMsg("err "+p.String(), err, ShouldBeNil)
+			SoMsg("policy "+p.String(), parsed, ShouldEqual, p)
+		}
+		_, err := AnycastPolicyFromString("random")
+		SoMsg("unknown err", err, ShouldNotBeNil)
+	})
+}
+
+type testLoader struct {
+	name string
+	load int
+}
+
+func (l *testLoader) Load() int {
+	return l.load
+}
+
 func checkAnyCastCycles(t *testing.T, lookup func() []interface{}, expected []string) {
 	t.Helper()
 	firstRes := lookup()[0].(string)
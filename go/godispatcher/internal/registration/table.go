@@ -39,9 +39,13 @@ type Table struct {
 }
 
 func NewTable(minPort, maxPort int) *Table {
+	return newTable(minPort, maxPort, AnycastBindIP)
+}
+
+func newTable(minPort, maxPort int, policy AnycastPolicy) *Table {
 	return &Table{
 		udpPortTable: NewUDPPortTable(minPort, maxPort),
-		svcTable:     NewSVCTable(),
+		svcTable:     NewSVCTableWithPolicy(policy),
 		scmpTable:    NewSCMPTable(),
 	}
 }
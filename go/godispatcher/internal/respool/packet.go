@@ -22,6 +22,7 @@ import (
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/hpkt"
 	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/scmp"
 	"github.com/scionproto/scion/go/lib/spkt"
 )
 
@@ -43,6 +44,9 @@ func GetPacket() *Packet {
 type Packet struct {
 	Info          spkt.ScnPkt
 	OverlayRemote *net.UDPAddr
+	// OverlayLocal is the destination IP address of the overlay packet. It is
+	// nil if the overlay connection does not report it.
+	OverlayLocal net.IP
 
 	// buffer contains the raw slice that other fields reference
 	buffer common.RawBytes
@@ -92,8 +96,24 @@ func (pkt *Packet) Free() {
 	}
 }
 
+// DstReader is implemented by overlay connections that report the destination
+// IP address of received packets.
+type DstReader interface {
+	// ReadFromDst reads a packet into b, and returns its length, source
+	// address and destination IP address. The destination IP address is nil
+	// if it is not known.
+	ReadFromDst(b []byte) (int, net.Addr, net.IP, error)
+}
+
 func (pkt *Packet) DecodeFromConn(conn net.PacketConn) error {
-	n, readExtra, err := conn.ReadFrom(pkt.buffer)
+	var n int
+	var readExtra net.Addr
+	var err error
+	if dstConn, ok := conn.(DstReader); ok {
+		n, readExtra, pkt.OverlayLocal, err = dstConn.ReadFromDst(pkt.buffer)
+	} else {
+		n, readExtra, err = conn.ReadFrom(pkt.buffer)
+	}
 	if err != nil {
 		return err
 	}
@@ -133,8 +153,42 @@ func (pkt *Packet) SendOnConn(conn net.PacketConn, address net.Addr) (int, error
 	return conn.WriteTo(pkt.buffer, address)
 }
 
+// Quote returns the raw bytes of the block of the packet, for quoting in SCMP
+// errors. It must only be called on packets that were parsed successfully.
+// The returned slice references the packet buffer.
+func (pkt *Packet) Quote(blk scmp.RawBlock) common.RawBytes {
+	addrEnd := spkt.CmnHdrLen + pkt.Info.AddrLen()
+	hdrEnd := pkt.Info.HdrLen()
+	// The L4 header directly precedes the payload at the end of the packet.
+	l4End := len(pkt.buffer)
+	if pkt.Info.Pld != nil {
+		l4End -= pkt.Info.Pld.Len()
+	}
+	l4Start := l4End
+	if pkt.Info.L4 != nil {
+		l4Start -= pkt.Info.L4.L4Len()
+	}
+	if l4Start < hdrEnd || hdrEnd > len(pkt.buffer) {
+		return nil
+	}
+	switch blk {
+	case scmp.RawCmnHdr:
+		return pkt.buffer[:spkt.CmnHdrLen]
+	case scmp.RawAddrHdr:
+		return pkt.buffer[spkt.CmnHdrLen:addrEnd]
+	case scmp.RawPathHdr:
+		return pkt.buffer[addrEnd:hdrEnd]
+	case scmp.RawExtHdrs:
+		return pkt.buffer[hdrEnd:l4Start]
+	case scmp.RawL4Hdr:
+		return pkt.buffer[l4Start:l4End]
+	}
+	return nil
+}
+
 func (pkt *Packet) reset() {
 	pkt.buffer = pkt.buffer[:cap(pkt.buffer)]
 	pkt.Info = spkt.ScnPkt{}
 	pkt.OverlayRemote = nil
+	pkt.OverlayLocal = nil
 }
@@ -127,10 +127,16 @@ func RunDispatcher(deleteSocketFlag bool, applicationSocket string, overlayPort
 			return err
 		}
 	}
+	policy, err := cfg.AnycastPolicy()
+	if err != nil {
+		return err
+	}
 	dispatcher := &network.Dispatcher{
-		RoutingTable:      network.NewIATable(1024, 65535),
+		RoutingTable:      network.NewIATableWithPolicy(1024, 65535, policy),
 		OverlaySocket:     fmt.Sprintf(":%d", overlayPort),
 		ApplicationSocket: applicationSocket,
+		SCMPErrorRate:     cfg.Dispatcher.SCMPErrorRate,
+		SCMPErrorBurst:    cfg.Dispatcher.SCMPErrorBurst,
 	}
 	log.Debug("Dispatcher starting", "appSocket", applicationSocket, "overlayPort", overlayPort)
 	return dispatcher.ListenAndServe()
@@ -15,8 +15,6 @@
 package network
 
 import (
-	"net"
-
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
 )
@@ -25,10 +23,15 @@ type Dispatcher struct {
 	RoutingTable      *IATable
 	OverlaySocket     string
 	ApplicationSocket string
+	// SCMPErrorRate is the average number of SCMP errors per second sent for
+	// packets that cannot be delivered, SCMPErrorBurst the maximum number
+	// sent at once. If SCMPErrorRate is not positive, no SCMP errors are sent.
+	SCMPErrorRate  float64
+	SCMPErrorBurst int
 }
 
 func (d *Dispatcher) ListenAndServe() error {
-	overlayConn, err := net.ListenPacket("udp", d.OverlaySocket)
+	overlayConn, err := listenOverlay(d.OverlaySocket)
 	if err != nil {
 		return err
 	}
@@ -47,6 +50,10 @@ func (d *Dispatcher) ListenAndServe() error {
 			OverlayConn:  overlayConn,
 			RoutingTable: d.RoutingTable,
 		}
+		if d.SCMPErrorRate > 0 {
+			netToRingDataplane.SCMPErrors = NewSCMPErrorSender(overlayConn,
+				d.SCMPErrorRate, d.SCMPErrorBurst)
+		}
 		errChan <- netToRingDataplane.Run()
 	}()
 
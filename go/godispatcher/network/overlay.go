@@ -45,6 +45,9 @@ const (
 type NetToRingDataplane struct {
 	OverlayConn  net.PacketConn
 	RoutingTable *IATable
+	// SCMPErrors sends SCMP errors for packets that cannot be delivered. If
+	// nil, such packets are dropped silently.
+	SCMPErrors *SCMPErrorSender
 }
 
 func (dp *NetToRingDataplane) Run() error {
@@ -158,6 +161,7 @@ func (d *UDPDestination) Send(dp *NetToRingDataplane, pkt *respool.Packet) {
 	if !ok {
 		log.Warn("destination address not found", "ia", pkt.Info.DstIA,
 			"udpAddr", (*net.UDPAddr)(d))
+		dp.SCMPErrors.Send(pkt, scmp.ClassType{Class: scmp.C_Routing, Type: scmp.T_R_UnreachPort})
 		return
 	}
 	sendPacket(routingEntry, pkt)
@@ -167,12 +171,17 @@ var _ Destination = SVCDestination(addr.SvcNone)
 
 type SVCDestination addr.HostSVC
 
+// Send delivers anycast SVC packets to a single application, selected by the
+// anycast policy of the routing table. The destination IP address of the
+// overlay packet is used to select applications bound to that address.
+// Multicast SVC packets are delivered to all applications registered for the
+// service.
 func (d SVCDestination) Send(dp *NetToRingDataplane, pkt *respool.Packet) {
-	// FIXME(scrye): This should deliver to the correct IP address, based on
-	// information found in the overlay IP header.
-	routingEntries := dp.RoutingTable.LookupService(pkt.Info.DstIA, addr.HostSVC(d), nil)
+	routingEntries := dp.RoutingTable.LookupService(pkt.Info.DstIA, addr.HostSVC(d),
+		pkt.OverlayLocal)
 	if len(routingEntries) == 0 {
 		log.Warn("destination address not found", "ia", pkt.Info.DstIA, "svc", addr.HostSVC(d))
+		dp.SCMPErrors.Send(pkt, scmp.ClassType{Class: scmp.C_Routing, Type: scmp.T_R_UnreachHost})
 		return
 	}
 	// Increase reference count for all extra copies
@@ -194,6 +203,7 @@ func (d *SCMPAppDestination) Send(dp *NetToRingDataplane, pkt *respool.Packet) {
 	routingEntry, ok := dp.RoutingTable.LookupID(pkt.Info.DstIA, d.ID)
 	if !ok {
 		log.Warn("destination address not found", "SCMP", d.ID)
+		dp.SCMPErrors.Send(pkt, scmp.ClassType{Class: scmp.C_Routing, Type: scmp.T_R_UnreachPort})
 		return
 	}
 	sendPacket(routingEntry, pkt)
@@ -0,0 +1,84 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"net"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+
+	"github.com/scionproto/scion/go/godispatcher/internal/respool"
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+var _ respool.DstReader = (*overlayConn)(nil)
+
+// overlayConn is the UDP overlay socket of the dispatcher. It reports the
+// destination IP address of received packets, if the platform supports it.
+// The address is used to deliver anycast SVC packets to applications bound to
+// that address.
+type overlayConn struct {
+	*net.UDPConn
+	pconn4 *ipv4.PacketConn
+	pconn6 *ipv6.PacketConn
+}
+
+func listenOverlay(address string) (*overlayConn, error) {
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	c := &overlayConn{UDPConn: conn.(*net.UDPConn)}
+	if c.LocalAddr().(*net.UDPAddr).IP.To4() != nil {
+		pconn := ipv4.NewPacketConn(c.UDPConn)
+		if err = pconn.SetControlMessage(ipv4.FlagDst, true); err == nil {
+			c.pconn4 = pconn
+		}
+	} else {
+		pconn := ipv6.NewPacketConn(c.UDPConn)
+		if err = pconn.SetControlMessage(ipv6.FlagDst, true); err == nil {
+			c.pconn6 = pconn
+		}
+	}
+	if err != nil {
+		log.Info("Overlay destination addresses not available", "err", err)
+	}
+	return c, nil
+}
+
+func (c *overlayConn) ReadFromDst(b []byte) (int, net.Addr, net.IP, error) {
+	switch {
+	case c.pconn4 != nil:
+		n, cm, src, err := c.pconn4.ReadFrom(b)
+		if cm == nil {
+			return n, src, nil, err
+		}
+		return n, src, cm.Dst, err
+	case c.pconn6 != nil:
+		n, cm, src, err := c.pconn6.ReadFrom(b)
+		if cm == nil {
+			return n, src, nil, err
+		}
+		// IPv4 packets received on dual-stack sockets have IPv4-mapped
+		// destination addresses.
+		if ip := cm.Dst.To4(); ip != nil {
+			return n, src, ip, err
+		}
+		return n, src, cm.Dst, err
+	}
+	n, src, err := c.ReadFrom(b)
+	return n, src, nil, err
+}
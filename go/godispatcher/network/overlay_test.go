@@ -21,10 +21,16 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/smartystreets/goconvey/convey"
 
+	"github.com/scionproto/scion/go/godispatcher/internal/metrics"
+	"github.com/scionproto/scion/go/godispatcher/internal/registration"
+	"github.com/scionproto/scion/go/godispatcher/internal/respool"
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/hpkt"
 	"github.com/scionproto/scion/go/lib/l4"
 	"github.com/scionproto/scion/go/lib/l4/mock_l4"
+	"github.com/scionproto/scion/go/lib/mocks/net/mock_net"
+	"github.com/scionproto/scion/go/lib/ringbuf"
 	"github.com/scionproto/scion/go/lib/scmp"
 	"github.com/scionproto/scion/go/lib/spkt"
 	"github.com/scionproto/scion/go/lib/xtest"
@@ -219,6 +225,98 @@ func TestComputeDestination(t *testing.T) {
 	})
 }
 
+func TestSVCDestinationSend(t *testing.T) {
+	ringbuf.InitMetrics("svc_destination_test", nil)
+	ia := xtest.MustParseIA("1-ff00:0:1")
+	Convey("Given a round robin table with two PS applications", t, func() {
+		table := NewIATableWithPolicy(1024, 65535, registration.AnycastRoundRobin)
+		entries := []*TableEntry{newTableEntry(nil), newTableEntry(nil)}
+		for i, entry := range entries {
+			public := &net.UDPAddr{IP: net.IP{127, 0, 0, byte(i + 1)}, Port: 20001 + i}
+			_, err := table.Register(ia, public, nil, addr.SvcPS, entry)
+			xtest.FailOnErr(t, err)
+		}
+		dp := &NetToRingDataplane{RoutingTable: table}
+		Convey("Anycast packets are delivered to a single application each", func() {
+			for i := 0; i < 4; i++ {
+				SVCDestination(addr.SvcPS).Send(dp, newSVCPacket(ia))
+			}
+			SoMsg("first", entries[0].Load(), ShouldEqual, 2)
+			SoMsg("second", entries[1].Load(), ShouldEqual, 2)
+		})
+		Convey("Multicast packets are delivered to all applications", func() {
+			SVCDestination(addr.SvcPS.Multicast()).Send(dp, newSVCPacket(ia))
+			SoMsg("first", entries[0].Load(), ShouldEqual, 1)
+			SoMsg("second", entries[1].Load(), ShouldEqual, 1)
+		})
+	})
+}
+
+func TestSVCDestinationSendBindIP(t *testing.T) {
+	ringbuf.InitMetrics("svc_destination_test", nil)
+	metrics.Init("svc_destination_test")
+	ia := xtest.MustParseIA("1-ff00:0:1")
+	Convey("Given a bind IP table with PS applications on two IPs", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		table := NewIATableWithPolicy(1024, 65535, registration.AnycastBindIP)
+		entries := []*TableEntry{newTableEntry(nil), newTableEntry(nil)}
+		for i, entry := range entries {
+			public := &net.UDPAddr{IP: net.IP{127, 0, 0, byte(i + 1)}, Port: 20001}
+			_, err := table.Register(ia, public, nil, addr.SvcPS, entry)
+			xtest.FailOnErr(t, err)
+		}
+		conn := mock_net.NewMockPacketConn(ctrl)
+		dp := &NetToRingDataplane{
+			RoutingTable: table,
+			SCMPErrors:   NewSCMPErrorSender(conn, 100, 10),
+		}
+		Convey("Anycast packets are delivered to the application bound to the overlay IP",
+			func() {
+				for i := 0; i < 2; i++ {
+					pkt := newSVCPacket(ia)
+					pkt.OverlayLocal = net.IP{127, 0, 0, 2}
+					SVCDestination(addr.SvcPS).Send(dp, pkt)
+				}
+				SoMsg("first", entries[0].Load(), ShouldEqual, 0)
+				SoMsg("second", entries[1].Load(), ShouldEqual, 2)
+			},
+		)
+		Convey("Anycast packets to an IP without applications are answered with an SCMP error",
+			func() {
+				pkt := receivePacket(t, &spkt.ScnPkt{
+					SrcIA:   xtest.MustParseIA("1-ff00:0:2"),
+					DstIA:   ia,
+					SrcHost: addr.HostFromIP(net.IP{192, 168, 0, 1}),
+					DstHost: addr.SvcPS,
+					L4:      &l4.UDP{SrcPort: 40000, DstPort: 0},
+					Pld:     common.RawBytes{1, 2, 3, 4},
+				})
+				pkt.OverlayLocal = net.IP{127, 0, 0, 3}
+				var reply spkt.ScnPkt
+				conn.EXPECT().WriteTo(gomock.Any(), pkt.OverlayRemote).DoAndReturn(
+					func(b []byte, _ net.Addr) (int, error) {
+						return len(b), hpkt.ParseScnPkt(&reply, b)
+					},
+				)
+				SVCDestination(addr.SvcPS).Send(dp, pkt)
+				SoMsg("first", entries[0].Load(), ShouldEqual, 0)
+				SoMsg("second", entries[1].Load(), ShouldEqual, 0)
+				hdr, ok := reply.L4.(*scmp.Hdr)
+				SoMsg("scmp", ok, ShouldBeTrue)
+				SoMsg("class", hdr.Class, ShouldEqual, scmp.C_Routing)
+				SoMsg("type", hdr.Type, ShouldEqual, scmp.T_R_UnreachHost)
+			},
+		)
+	})
+}
+
+func newSVCPacket(ia addr.IA) *respool.Packet {
+	pkt := respool.GetPacket()
+	pkt.Info.DstIA = ia
+	return pkt
+}
+
 func MustPackL4Header(t *testing.T, header l4.L4Header) common.RawBytes {
 	b, err := header.Pack(false)
 	xtest.FailOnErr(t, err)
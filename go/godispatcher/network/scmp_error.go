@@ -0,0 +1,184 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"net"
+	"time"
+
+	"github.com/scionproto/scion/go/godispatcher/internal/metrics"
+	"github.com/scionproto/scion/go/godispatcher/internal/respool"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/hpkt"
+	"github.com/scionproto/scion/go/lib/layers"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/spkt"
+)
+
+// SCMPErrorSender replies with SCMP errors to packets that cannot be
+// delivered to an application, the same way the border router replies to
+// packets it cannot forward. Errors are sent back to the source of the packet
+// on the reversed path, and their number is limited by a token bucket.
+//
+// No errors are sent in response to SCMP errors, to packets with non-IP source
+// addresses or to packets addressed to multicast SVC addresses.
+//
+// SCMPErrorSender is not safe for concurrent use.
+type SCMPErrorSender struct {
+	conn   net.PacketConn
+	bucket *tokenBucket
+}
+
+// NewSCMPErrorSender returns a sender that writes errors to the overlay
+// connection conn. On average, rate errors per second are sent, and at most
+// burst errors at once.
+func NewSCMPErrorSender(conn net.PacketConn, rate float64, burst int) *SCMPErrorSender {
+	return &SCMPErrorSender{
+		conn:   conn,
+		bucket: newTokenBucket(rate, burst, time.Now()),
+	}
+}
+
+// Send replies to pkt with an SCMP error of type ct. It does not take
+// ownership of pkt. Send is a no-op on a nil sender.
+func (s *SCMPErrorSender) Send(pkt *respool.Packet, ct scmp.ClassType) {
+	if s == nil || !needsSCMPError(&pkt.Info) {
+		return
+	}
+	if !s.bucket.take(time.Now()) {
+		metrics.SCMPErrors.WithLabelValues(metrics.SCMPErrorOutcomeRateLimited).Inc()
+		return
+	}
+	if err := s.send(pkt, ct); err != nil {
+		log.Warn("Unable to send SCMP error", "ct", ct, "err", err)
+		metrics.SCMPErrors.WithLabelValues(metrics.SCMPErrorOutcomeError).Inc()
+		return
+	}
+	metrics.SCMPErrors.WithLabelValues(metrics.SCMPErrorOutcomeSent).Inc()
+}
+
+func (s *SCMPErrorSender) send(pkt *respool.Packet, ct scmp.ClassType) error {
+	srcHost := pkt.Info.DstHost
+	if srcHost.Type() == addr.HostTypeSVC {
+		// SVC addresses cannot be used as source, use the address of the
+		// host instead.
+		ip, err := localIP(pkt.OverlayRemote)
+		if err != nil {
+			return err
+		}
+		srcHost = addr.HostFromIP(ip)
+	}
+	reply, err := createSCMPErrorReply(pkt, ct, srcHost)
+	if err != nil {
+		return err
+	}
+	b := respool.GetBuffer()
+	defer respool.PutBuffer(b)
+	n, err := hpkt.WriteScnPkt(reply, b)
+	if err != nil {
+		return common.NewBasicError("Unable to serialize SCMP error", err)
+	}
+	if _, err := s.conn.WriteTo(b[:n], pkt.OverlayRemote); err != nil {
+		return common.NewBasicError("Unable to write to overlay socket", err)
+	}
+	return nil
+}
+
+// createSCMPErrorReply creates an SCMP error of type ct in response to pkt,
+// quoting the offending packet.
+func createSCMPErrorReply(pkt *respool.Packet, ct scmp.ClassType,
+	srcHost addr.HostAddr) (*spkt.ScnPkt, error) {
+
+	reply := &spkt.ScnPkt{
+		DstIA:   pkt.Info.SrcIA,
+		SrcIA:   pkt.Info.DstIA,
+		DstHost: pkt.Info.SrcHost,
+		SrcHost: srcHost,
+		HBHExt:  []common.Extension{&layers.ExtnSCMP{Error: true}},
+	}
+	if pkt.Info.Path != nil {
+		reply.Path = pkt.Info.Path.Copy()
+		if err := reply.Path.Reverse(); err != nil {
+			return nil, common.NewBasicError("Unable to reverse path", err)
+		}
+	}
+	reply.Pld = scmp.PldFromQuotes(ct, nil, pkt.Info.L4.L4Type(), pkt.Quote)
+	reply.L4 = scmp.NewHdr(ct, reply.Pld.Len())
+	return reply, nil
+}
+
+// needsSCMPError returns whether an SCMP error should be sent if pkt cannot be
+// delivered.
+func needsSCMPError(pkt *spkt.ScnPkt) bool {
+	if pkt.L4 == nil || pkt.SrcHost == nil || pkt.DstHost == nil {
+		return false
+	}
+	if hdr, ok := pkt.L4.(*scmp.Hdr); ok && hdr.Class != scmp.C_General {
+		return false
+	}
+	if t := pkt.SrcHost.Type(); t != addr.HostTypeIPv4 && t != addr.HostTypeIPv6 {
+		return false
+	}
+	if svc, ok := pkt.DstHost.(addr.HostSVC); ok && svc.IsMulticast() {
+		return false
+	}
+	return true
+}
+
+// localIP returns the IP address the host uses to send packets to remote.
+func localIP(remote *net.UDPAddr) (net.IP, error) {
+	conn, err := net.DialUDP("udp", nil, remote)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to determine local IP", err,
+			"remote", remote)
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// tokenBucket limits the rate of events.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	b := float64(burst)
+	if b < 1 {
+		b = 1
+	}
+	return &tokenBucket{rate: rate, burst: b, tokens: b, last: now}
+}
+
+// take removes a token from the bucket. It returns false if the bucket is
+// empty.
+func (b *tokenBucket) take(now time.Time) bool {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
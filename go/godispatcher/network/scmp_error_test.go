@@ -0,0 +1,161 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/godispatcher/internal/respool"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/hpkt"
+	"github.com/scionproto/scion/go/lib/l4"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/spkt"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+// receivePacket sends sp over a local UDP socket and decodes it into a
+// dispatcher packet.
+func receivePacket(t *testing.T, sp *spkt.ScnPkt) *respool.Packet {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	xtest.FailOnErr(t, err)
+	defer conn.Close()
+	b := make(common.RawBytes, common.MaxMTU)
+	n, err := hpkt.WriteScnPkt(sp, b)
+	xtest.FailOnErr(t, err)
+	_, err = conn.WriteTo(b[:n], conn.LocalAddr())
+	xtest.FailOnErr(t, err)
+	pkt := respool.GetPacket()
+	xtest.FailOnErr(t, pkt.DecodeFromConn(conn))
+	return pkt
+}
+
+func TestCreateSCMPErrorReply(t *testing.T) {
+	Convey("SCMP error replies quote the offending packet", t, func() {
+		srcIA, dstIA := xtest.MustParseIA("1-ff00:0:110"), xtest.MustParseIA("1-ff00:0:111")
+		srcHost := addr.HostFromIP(net.IP{192, 168, 0, 1})
+		dstHost := addr.HostFromIP(net.IP{192, 168, 0, 2})
+		pkt := receivePacket(t, &spkt.ScnPkt{
+			SrcIA:   srcIA,
+			DstIA:   dstIA,
+			SrcHost: srcHost,
+			DstHost: dstHost,
+			L4:      &l4.UDP{SrcPort: 40000, DstPort: 40001},
+			Pld:     common.RawBytes{1, 2, 3, 4},
+		})
+		ct := scmp.ClassType{Class: scmp.C_Routing, Type: scmp.T_R_UnreachPort}
+		reply, err := createSCMPErrorReply(pkt, ct, dstHost)
+		SoMsg("err", err, ShouldBeNil)
+
+		b := make(common.RawBytes, common.MaxMTU)
+		n, err := hpkt.WriteScnPkt(reply, b)
+		SoMsg("write err", err, ShouldBeNil)
+		var parsed spkt.ScnPkt
+		SoMsg("parse err", hpkt.ParseScnPkt(&parsed, b[:n]), ShouldBeNil)
+		SoMsg("dstIA", parsed.DstIA, ShouldResemble, srcIA)
+		SoMsg("srcIA", parsed.SrcIA, ShouldResemble, dstIA)
+		SoMsg("dstHost", parsed.DstHost, ShouldResemble, srcHost)
+		SoMsg("srcHost", parsed.SrcHost, ShouldResemble, dstHost)
+		hdr, ok := parsed.L4.(*scmp.Hdr)
+		SoMsg("scmp", ok, ShouldBeTrue)
+		SoMsg("class", hdr.Class, ShouldEqual, scmp.C_Routing)
+		SoMsg("type", hdr.Type, ShouldEqual, scmp.T_R_UnreachPort)
+		pld := parsed.Pld.(*scmp.Payload)
+		SoMsg("l4 proto", pld.Meta.L4Proto, ShouldEqual, common.L4UDP)
+		SoMsg("cmn hdr", len(pld.CmnHdr), ShouldEqual, spkt.CmnHdrLen)
+		quotedUDP, err := l4.UDPFromRaw(pld.L4Hdr)
+		SoMsg("quote err", err, ShouldBeNil)
+		SoMsg("quoted src port", quotedUDP.SrcPort, ShouldEqual, 40000)
+		SoMsg("quoted dst port", quotedUDP.DstPort, ShouldEqual, 40001)
+
+		Convey("The error is routed back to the sender's socket", func() {
+			dst, err := ComputeDestination(&parsed)
+			SoMsg("err", err, ShouldBeNil)
+			udpDst, ok := dst.(*UDPDestination)
+			SoMsg("udp", ok, ShouldBeTrue)
+			SoMsg("ip", udpDst.IP.Equal(srcHost.IP()), ShouldBeTrue)
+			SoMsg("port", udpDst.Port, ShouldEqual, 40000)
+		})
+	})
+}
+
+func TestNeedsSCMPError(t *testing.T) {
+	ip := addr.HostFromIP(net.IP{192, 168, 0, 1})
+	tests := []struct {
+		Description string
+		Packet      *spkt.ScnPkt
+		Expected    bool
+	}{
+		{
+			Description: "UDP to IP",
+			Packet:      &spkt.ScnPkt{SrcHost: ip, DstHost: ip, L4: &l4.UDP{}},
+			Expected:    true,
+		},
+		{
+			Description: "UDP to anycast SVC",
+			Packet:      &spkt.ScnPkt{SrcHost: ip, DstHost: addr.SvcPS, L4: &l4.UDP{}},
+			Expected:    true,
+		},
+		{
+			Description: "UDP to multicast SVC",
+			Packet: &spkt.ScnPkt{SrcHost: ip, DstHost: addr.SvcPS.Multicast(),
+				L4: &l4.UDP{}},
+		},
+		{
+			Description: "UDP from SVC",
+			Packet:      &spkt.ScnPkt{SrcHost: addr.SvcPS, DstHost: ip, L4: &l4.UDP{}},
+		},
+		{
+			Description: "SCMP General",
+			Packet: &spkt.ScnPkt{SrcHost: ip, DstHost: ip,
+				L4: &scmp.Hdr{Class: scmp.C_General, Type: scmp.T_G_EchoReply}},
+			Expected: true,
+		},
+		{
+			Description: "SCMP error",
+			Packet: &spkt.ScnPkt{SrcHost: ip, DstHost: ip,
+				L4: &scmp.Hdr{Class: scmp.C_Routing, Type: scmp.T_R_UnreachPort}},
+		},
+	}
+	Convey("needsSCMPError", t, func() {
+		for _, test := range tests {
+			Convey(test.Description, func() {
+				So(needsSCMPError(test.Packet), ShouldEqual, test.Expected)
+			})
+		}
+	})
+}
+
+func TestTokenBucket(t *testing.T) {
+	Convey("Token bucket limits the rate", t, func() {
+		now := time.Now()
+		b := newTokenBucket(10, 2, now)
+		SoMsg("burst 1", b.take(now), ShouldBeTrue)
+		SoMsg("burst 2", b.take(now), ShouldBeTrue)
+		SoMsg("empty", b.take(now), ShouldBeFalse)
+		now = now.Add(100 * time.Millisecond)
+		SoMsg("refilled", b.take(now), ShouldBeTrue)
+		SoMsg("empty again", b.take(now), ShouldBeFalse)
+		now = now.Add(time.Hour)
+		SoMsg("capped 1", b.take(now), ShouldBeTrue)
+		SoMsg("capped 2", b.take(now), ShouldBeTrue)
+		SoMsg("capped 3", b.take(now), ShouldBeFalse)
+	})
+}
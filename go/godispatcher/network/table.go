@@ -36,6 +36,11 @@ func newTableEntry(conn net.PacketConn) *TableEntry {
 	}
 }
 
+// Load returns the number of packets waiting to be read by the application.
+func (e *TableEntry) Load() int {
+	return e.appIngressRing.Readable()
+}
+
 func getBindIP(address *net.UDPAddr) net.IP {
 	if address == nil {
 		return nil
@@ -54,6 +59,14 @@ func NewIATable(minPort, maxPort int) *IATable {
 	}
 }
 
+// NewIATableWithPolicy creates a routing table that uses policy to select the
+// application anycast SVC packets are delivered to.
+func NewIATableWithPolicy(minPort, maxPort int, policy registration.AnycastPolicy) *IATable {
+	return &IATable{
+		IATable: registration.NewIATableWithPolicy(minPort, maxPort, policy),
+	}
+}
+
 func (t *IATable) LookupPublic(ia addr.IA, public *net.UDPAddr) (*TableEntry, bool) {
 	e, ok := t.IATable.LookupPublic(ia, public)
 	if !ok {
@@ -35,6 +35,7 @@ import (
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/fatal"
+	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/infra/modules/itopo"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/overlay"
@@ -42,6 +43,7 @@ import (
 	_ "github.com/scionproto/scion/go/lib/scrypto" // Make sure math/rand is seeded
 	"github.com/scionproto/scion/go/lib/snet"
 	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/tracing"
 	"github.com/scionproto/scion/go/lib/util"
 )
 
@@ -57,6 +59,10 @@ const (
 	// SciondInitConnectPeriod is the default total amount of time spent
 	// attempting to connect to sciond on start.
 	SciondInitConnectPeriod = 20 * time.Second
+
+	// DefaultTracingSampleRate is the default fraction of requests that start
+	// a new trace if tracing is enabled.
+	DefaultTracingSampleRate = 1.0
 )
 
 var sighupC chan os.Signal
@@ -276,3 +282,129 @@ func (cfg *QUIC) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 func (cfg *QUIC) ConfigName() string {
 	return "quic"
 }
+
+var _ config.Config = (*Admission)(nil)
+
+// Admission contains the admission control configuration for incoming
+// control-plane requests.
+type Admission struct {
+	config.NoDefaulter
+	// MaxWorkers is the maximum number of concurrently running handlers. If
+	// it is 0, the number is not limited.
+	MaxWorkers int
+	// Limits maps message type names (e.g. SegRequest) to rate limits.
+	Limits map[string]AdmissionLimit
+}
+
+// AdmissionLimit contains the rate limits for one message type. A rate of 0
+// disables the corresponding limit.
+type AdmissionLimit struct {
+	// Rate is the number of messages per second accepted from all sources.
+	Rate float64
+	// Burst is the number of messages accepted at once from all sources.
+	Burst int
+	// PerSourceRate is the number of messages per second accepted from a
+	// single source IA.
+	PerSourceRate float64
+	// PerSourceBurst is the number of messages accepted at once from a single
+	// source IA.
+	PerSourceBurst int
+}
+
+func (cfg *Admission) Validate() error {
+	if cfg.MaxWorkers < 0 {
+		return common.NewBasicError("MaxWorkers must not be negative", nil,
+			"value", cfg.MaxWorkers)
+	}
+	for name, l := range cfg.Limits {
+		if _, err := infra.MessageTypeFromString(name); err != nil {
+			return err
+		}
+		if l.Rate < 0 || l.PerSourceRate < 0 || l.Burst < 0 || l.PerSourceBurst < 0 {
+			return common.NewBasicError("Rate limits must not be negative", nil,
+				"msgType", name)
+		}
+	}
+	return nil
+}
+
+func (cfg *Admission) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
+	config.WriteString(dst, admissionSample)
+}
+
+func (cfg *Admission) ConfigName() string {
+	return "admission"
+}
+
+var _ config.Config = (*Tracing)(nil)
+
+// Tracing contains the configuration of distributed request tracing.
+type Tracing struct {
+	// Enabled enables tracing.
+	Enabled bool
+	// SampleRate is the fraction of requests that start a new trace. Requests
+	// that are part of a trace started by another service follow the sampling
+	// decision of that service. An explicit 0 disables sampling. (default 1.0)
+	SampleRate *float64
+	// Collector is the URL of a collector that accepts spans in Zipkin v2
+	// JSON format, e.g. the Zipkin endpoint of Jaeger. If empty, spans are
+	// not sent to a collector.
+	Collector string
+	// File is the path of a file spans are appended to as JSON lines. If
+	// empty, spans are not written to a file.
+	File string
+}
+
+func (cfg *Tracing) InitDefaults() {
+	if cfg.SampleRate == nil {
+		r := DefaultTracingSampleRate
+		cfg.SampleRate = &r
+	}
+}
+
+func (cfg *Tracing) Validate() error {
+	if cfg.SampleRate != nil && (*cfg.SampleRate < 0 || *cfg.SampleRate > 1) {
+		return common.NewBasicError("SampleRate must be in [0, 1]", nil,
+			"value", *cfg.SampleRate)
+	}
+	if cfg.Enabled && cfg.Collector == "" && cfg.File == "" {
+		return common.NewBasicError("Tracing enabled, but neither Collector nor File set", nil)
+	}
+	return nil
+}
+
+func (cfg *Tracing) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
+	config.WriteString(dst, tracingSample)
+}
+
+func (cfg *Tracing) ConfigName() string {
+	return "tracing"
+}
+
+// InitGlobalTracer sets the global tracer according to the configuration.
+// The service name is attached to all spans. If tracing is disabled, this is
+// a no-op.
+func (cfg *Tracing) InitGlobalTracer(service string) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	var reporters []tracing.Reporter
+	if cfg.File != "" {
+		r, err := tracing.NewFileReporter(cfg.File)
+		if err != nil {
+			return err
+		}
+		reporters = append(reporters, r)
+	}
+	if cfg.Collector != "" {
+		reporters = append(reporters, tracing.NewCollectorReporter(cfg.Collector))
+	}
+	rate := DefaultTracingSampleRate
+	if cfg.SampleRate != nil {
+		rate = *cfg.SampleRate
+	}
+	tracing.SetGlobal(tracing.NewTracer(service, rate, tracing.NewMultiReporter(reporters...)))
+	log.Info("Tracing enabled", "collector", cfg.Collector, "file", cfg.File,
+		"sampleRate", rate)
+	return nil
+}
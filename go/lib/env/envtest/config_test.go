@@ -76,3 +76,28 @@ func TestSciondClientSample(t *testing.T) {
 		InitTestSciond(&cfg)
 	})
 }
+
+func TestTracingSampleRate(t *testing.T) {
+	Convey("Tracing SampleRate", t, func() {
+		Convey("defaults to DefaultTracingSampleRate if unset", func() {
+			var cfg env.Tracing
+			_, err := toml.Decode("Enabled = true", &cfg)
+			SoMsg("err", err, ShouldBeNil)
+			cfg.InitDefaults()
+			SoMsg("rate", *cfg.SampleRate, ShouldEqual, env.DefaultTracingSampleRate)
+		})
+		Convey("keeps an explicit 0", func() {
+			var cfg env.Tracing
+			_, err := toml.Decode("SampleRate = 0.0", &cfg)
+			SoMsg("err", err, ShouldBeNil)
+			cfg.InitDefaults()
+			SoMsg("rate", *cfg.SampleRate, ShouldEqual, 0)
+			SoMsg("validate", cfg.Validate(), ShouldBeNil)
+		})
+		Convey("rejects values outside [0, 1]", func() {
+			rate := 1.5
+			cfg := env.Tracing{SampleRate: &rate}
+			SoMsg("validate", cfg.Validate(), ShouldNotBeNil)
+		})
+	})
+}
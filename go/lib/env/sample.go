@@ -95,3 +95,37 @@ KeyFile = "/etc/scion/quic/tls.key"
 # resolution step is successful.
 ResolutionFraction = 0.0
 `
+
+const admissionSample = `
+# Maximum number of concurrently running request handlers. If all are busy,
+# incoming requests are rejected. If 0, the number is not limited. (default 0)
+MaxWorkers = 0
+
+# Rate limits per message type, keyed by message type name. Limits are token
+# buckets, Rate is in messages per second. A rate of 0 disables the limit.
+# Rejected messages that expect an ack are answered with a retry ack.
+# [admission.Limits.SegRequest]
+# Rate = 1000.0
+# Burst = 2000
+# PerSourceRate = 100.0
+# PerSourceBurst = 200
+`
+
+const tracingSample = `
+# Enable distributed request tracing. (default false)
+Enabled = false
+
+# Fraction of requests that start a new trace. Requests that are part of a
+# trace started by another service follow its sampling decision. 0 disables
+# sampling. (default 1.0)
+SampleRate = 1.0
+
+# URL of a collector accepting spans in Zipkin v2 JSON format, e.g. the Zipkin
+# endpoint of Jaeger. If not set, spans are not sent to a collector.
+# (default "")
+Collector = ""
+
+# File that spans are appended to as JSON lines. If not set, spans are not
+# written to a file. (default "")
+File = ""
+`
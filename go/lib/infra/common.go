@@ -197,6 +197,17 @@ func (mt MessageType) String() string {
 	}
 }
 
+// MessageTypeFromString returns the message type with the given name, as
+// returned by String. None is not a valid message type name.
+func MessageTypeFromString(s string) (MessageType, error) {
+	for mt := None + 1; mt <= Ack; mt++ {
+		if mt.String() == s {
+			return mt, nil
+		}
+	}
+	return None, common.NewBasicError("Unknown message type", nil, "name", s)
+}
+
 // MetricLabel returns the label for metrics for a given message type.
 // The postfix for requests is always "req" and for replies and push messages it is always "push".
 func (mt MessageType) MetricLabel() string {
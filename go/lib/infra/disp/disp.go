@@ -167,6 +167,9 @@ func (d *Dispatcher) RecvFrom(ctx context.Context) (proto.Cerealizable, net.Addr
 	case <-d.closedChan:
 		// Some other goroutine closed the dispatcher
 		return nil, nil, common.NewBasicError(infra.StrClosedError, nil)
+	case <-d.stoppedChan:
+		// The transport was shut down, no more messages will be read
+		return nil, nil, common.NewBasicError(infra.StrClosedError, nil)
 	}
 }
 
@@ -84,6 +84,9 @@ type NetworkConfig struct {
 	// SVCRouter is used to discover the overlay addresses of intra-AS SVC
 	// servers.
 	SVCRouter messenger.LocalSVCRouter
+	// Admission configures admission control for incoming requests. If it is
+	// nil, all incoming requests are handled.
+	Admission *messenger.AdmissionConfig
 }
 
 // Messenger initializes a SCION control-plane RPC endpoint using the specified
@@ -110,6 +113,7 @@ func (nc *NetworkConfig) Messenger() (infra.Messenger, error) {
 		IA:              nc.IA,
 		TrustStore:      nc.TrustStore,
 		AddressRewriter: nc.AddressRewriter(nil),
+		Admission:       nc.Admission,
 	}
 	msgerCfg.Dispatcher = disp.New(
 		conn,
@@ -129,6 +133,30 @@ func (nc *NetworkConfig) Messenger() (infra.Messenger, error) {
 
 }
 
+// NewAdmissionConfig converts the admission configuration of a service to the
+// messenger admission configuration. If no limits are configured, nil is
+// returned.
+func NewAdmissionConfig(cfg *env.Admission) (*messenger.AdmissionConfig, error) {
+	if cfg.MaxWorkers == 0 && len(cfg.Limits) == 0 {
+		return nil, nil
+	}
+	admission := &messenger.AdmissionConfig{
+		MaxWorkers: cfg.MaxWorkers,
+		Limits:     make(map[infra.MessageType]messenger.MessageLimits),
+	}
+	for name, l := range cfg.Limits {
+		mt, err := infra.MessageTypeFromString(name)
+		if err != nil {
+			return nil, common.NewBasicError("Invalid admission limit", err, "msgType", name)
+		}
+		admission.Limits[mt] = messenger.MessageLimits{
+			Total:     messenger.RateLimit{Rate: l.Rate, Burst: l.Burst},
+			PerSource: messenger.RateLimit{Rate: l.PerSourceRate, Burst: l.PerSourceBurst},
+		}
+	}
+	return admission, nil
+}
+
 // AddressRewriter initializes path and svc resolvers for infra servers.
 //
 // The connection factory is used to open sockets for SVC resolution requests.
@@ -0,0 +1,287 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messenger
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/proto"
+)
+
+const (
+	// RejectRateLimit is the reason for requests rejected because the total
+	// rate limit for the message type was exceeded.
+	RejectRateLimit = "err_rate_limit"
+	// RejectSourceRateLimit is the reason for requests rejected because the
+	// rate limit of the source IA for the message type was exceeded.
+	RejectSourceRateLimit = "err_src_rate_limit"
+	// RejectOverload is the reason for requests rejected because all workers
+	// are busy.
+	RejectOverload = "err_overload"
+)
+
+// maxSourceBuckets is the number of per-source buckets that is kept per
+// message type before idle buckets are evicted.
+const maxSourceBuckets = 10000
+
+// maxRejectAcks is the maximum number of acks for rejected requests that are
+// sent concurrently. Further acks are dropped.
+const maxRejectAcks = 16
+
+// RateLimit describes a token bucket. Rate is the number of messages per
+// second that are accepted on average, Burst the maximum number of messages
+// that are accepted at once. A zero Rate disables the limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// MessageLimits contains the rate limits for a single message type.
+type MessageLimits struct {
+	// Total limits the rate of messages of the type from all sources.
+	Total RateLimit
+	// PerSource limits the rate of messages of the type from a single source
+	// IA.
+	PerSource RateLimit
+}
+
+// AdmissionConfig configures which incoming requests are passed to the
+// registered handlers.
+type AdmissionConfig struct {
+	// MaxWorkers is the maximum number of handlers that run concurrently. If
+	// all workers are busy, incoming requests are rejected. A value of 0
+	// means no limit.
+	MaxWorkers int
+	// Limits contains the rate limits per message type. Message types
+	// without entry are not rate limited.
+	Limits map[infra.MessageType]MessageLimits
+}
+
+// ackOnReject contains the message types for which the sender expects an ack.
+// For those, a retry ack is sent if the request is rejected.
+var ackOnReject = map[infra.MessageType]struct{}{
+	infra.Seg:       {},
+	infra.SegReg:    {},
+	infra.SegSync:   {},
+	infra.SignedRev: {},
+}
+
+// admission decides whether an incoming request is handled. A nil admission
+// accepts all requests.
+type admission struct {
+	workers chan struct{}
+	ackers  chan struct{}
+	limits  map[infra.MessageType]*typeLimiter
+}
+
+func newAdmission(cfg *AdmissionConfig) *admission {
+	if cfg == nil {
+		return nil
+	}
+	a := &admission{
+		ackers: make(chan struct{}, maxRejectAcks),
+		limits: make(map[infra.MessageType]*typeLimiter),
+	}
+	if cfg.MaxWorkers > 0 {
+		a.workers = make(chan struct{}, cfg.MaxWorkers)
+	}
+	for mt, l := range cfg.Limits {
+		a.limits[mt] = newTypeLimiter(l)
+	}
+	return a
+}
+
+// admit checks whether a request of type mt from src may be handled. If it
+// may, the returned release function must be called after the handler
+// finished. Otherwise, release is nil and the reason is returned.
+func (a *admission) admit(mt infra.MessageType, src net.Addr) (func(), string) {
+	if a == nil {
+		return func() {}, ""
+	}
+	if l, ok := a.limits[mt]; ok {
+		if reason := l.allow(srcIA(src), time.Now()); reason != "" {
+			return nil, reason
+		}
+	}
+	if a.workers == nil {
+		return func() {}, ""
+	}
+	select {
+	case a.workers <- struct{}{}:
+		return func() { <-a.workers }, ""
+	default:
+		return nil, RejectOverload
+	}
+}
+
+// reject records a rejected request and informs the sender if it expects an
+// ack. Note that ctx should have a logger attached.
+func reject(ctx context.Context, rw infra.ResponseWriter, mt infra.MessageType,
+	src net.Addr, localIA addr.IA, reason string) {
+
+	recordReject(ctx, mt, src, localIA, reason)
+	if _, ok := ackOnReject[mt]; ok {
+		SendAckHelper(ctx, rw)(proto.Ack_ErrCode_retry, AckRetryRejected)
+	}
+}
+
+// rejectAsync is like reject, but the ack is sent in a separate goroutine, as
+// it might block on path resolution. At most maxRejectAcks acks are sent
+// concurrently, if more are pending the ack is dropped. cancelF is called
+// once the rejection is handled.
+func (a *admission) rejectAsync(ctx context.Context, cancelF context.CancelFunc,
+	rw infra.ResponseWriter, mt infra.MessageType, src net.Addr, localIA addr.IA,
+	reason string) {
+
+	recordReject(ctx, mt, src, localIA, reason)
+	if _, ok := ackOnReject[mt]; !ok {
+		cancelF()
+		return
+	}
+	select {
+	case a.ackers <- struct{}{}:
+	default:
+		log.FromCtx(ctx).Debug("[Messenger] Dropped ack for rejected message", "type", mt,
+			"from", src)
+		metricRejectAckDropped(mt)
+		cancelF()
+		return
+	}
+	go func() {
+		defer log.LogPanicAndExit()
+		defer cancelF()
+		defer func() { <-a.ackers }()
+		SendAckHelper(ctx, rw)(proto.Ack_ErrCode_retry, AckRetryRejected)
+	}()
+}
+
+func recordReject(ctx context.Context, mt infra.MessageType, src net.Addr,
+	localIA addr.IA, reason string) {
+
+	logger := log.FromCtx(ctx)
+	logger.Debug("[Messenger] Rejected message", "type", mt, "from", src, "reason", reason)
+	metricRejected(mt, metricSrcValue(src, localIA), reason)
+}
+
+func srcIA(src net.Addr) addr.IA {
+	if sAddr, ok := src.(*snet.Addr); ok {
+		return sAddr.IA
+	}
+	return addr.IA{}
+}
+
+// typeLimiter enforces the limits of a single message type.
+type typeLimiter struct {
+	mu        sync.Mutex
+	limits    MessageLimits
+	total     *tokenBucket
+	perSource map[addr.IA]*tokenBucket
+}
+
+func newTypeLimiter(limits MessageLimits) *typeLimiter {
+	l := &typeLimiter{
+		limits:    limits,
+		perSource: make(map[addr.IA]*tokenBucket),
+	}
+	if limits.Total.Rate > 0 {
+		l.total = newTokenBucket(limits.Total, time.Now())
+	}
+	return l
+}
+
+func (l *typeLimiter) allow(ia addr.IA, now time.Time) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var src *tokenBucket
+	if l.limits.PerSource.Rate > 0 {
+		src = l.sourceBucket(ia, now)
+		src.refill(now)
+		if src.tokens < 1 {
+			return RejectSourceRateLimit
+		}
+	}
+	if l.total != nil {
+		l.total.refill(now)
+		if l.total.tokens < 1 {
+			return RejectRateLimit
+		}
+		l.total.tokens--
+	}
+	if src != nil {
+		src.tokens--
+	}
+	return ""
+}
+
+func (l *typeLimiter) sourceBucket(ia addr.IA, now time.Time) *tokenBucket {
+	if b, ok := l.perSource[ia]; ok {
+		return b
+	}
+	if len(l.perSource) >= maxSourceBuckets {
+		// Buckets that are full again behave like new buckets, so they can be
+		// dropped without changing the behavior.
+		for k, b := range l.perSource {
+			b.refill(now)
+			if b.full() {
+				delete(l.perSource, k)
+			}
+		}
+	}
+	b := newTokenBucket(l.limits.PerSource, now)
+	l.perSource[ia] = b
+	return b
+}
+
+// tokenBucket is a token bucket rate limiter. It is not concurrency safe.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   limit.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+}
+
+func (b *tokenBucket) full() bool {
+	return b.tokens >= b.burst
+}
@@ -0,0 +1,136 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messenger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/ctrl/ack"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/infra/mock_infra"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestTypeLimiter(t *testing.T) {
+	ia110 := xtest.MustParseIA("1-ff00:0:110")
+	ia111 := xtest.MustParseIA("1-ff00:0:111")
+	now := time.Now()
+	Convey("Per source limit", t, func() {
+		l := newTypeLimiter(MessageLimits{PerSource: RateLimit{Rate: 1, Burst: 2}})
+		SoMsg("1st", l.allow(ia110, now), ShouldEqual, "")
+		SoMsg("2nd", l.allow(ia110, now), ShouldEqual, "")
+		SoMsg("3rd", l.allow(ia110, now), ShouldEqual, RejectSourceRateLimit)
+		SoMsg("other source", l.allow(ia111, now), ShouldEqual, "")
+		SoMsg("refilled", l.allow(ia110, now.Add(time.Second)), ShouldEqual, "")
+		SoMsg("empty again", l.allow(ia110, now.Add(time.Second)), ShouldEqual,
+			RejectSourceRateLimit)
+	})
+	Convey("Total limit", t, func() {
+		l := newTypeLimiter(MessageLimits{Total: RateLimit{Rate: 10, Burst: 1}})
+		l.total.last = now
+		SoMsg("1st", l.allow(ia110, now), ShouldEqual, "")
+		SoMsg("other source", l.allow(ia111, now), ShouldEqual, RejectRateLimit)
+		SoMsg("refilled", l.allow(ia111, now.Add(100*time.Millisecond)), ShouldEqual, "")
+	})
+	Convey("Rejection by total limit does not consume source tokens", t, func() {
+		l := newTypeLimiter(MessageLimits{
+			Total:     RateLimit{Rate: 1, Burst: 1},
+			PerSource: RateLimit{Rate: 1, Burst: 1},
+		})
+		l.total.last = now
+		SoMsg("1st", l.allow(ia110, now), ShouldEqual, "")
+		SoMsg("total exhausted", l.allow(ia111, now), ShouldEqual, RejectRateLimit)
+		SoMsg("source tokens left", l.perSource[ia111].tokens, ShouldEqual, 1.0)
+	})
+}
+
+func TestAdmission(t *testing.T) {
+	src := &snet.Addr{IA: xtest.MustParseIA("1-ff00:0:110")}
+	Convey("Nil admission accepts everything", t, func() {
+		var a *admission
+		release, reason := a.admit(infra.SegRequest, src)
+		SoMsg("release", release, ShouldNotBeNil)
+		SoMsg("reason", reason, ShouldEqual, "")
+	})
+	Convey("Worker limit", t, func() {
+		a := newAdmission(&AdmissionConfig{MaxWorkers: 1})
+		release, _ := a.admit(infra.SegRequest, src)
+		SoMsg("1st admitted", release, ShouldNotBeNil)
+		rejected, reason := a.admit(infra.ChainRequest, src)
+		SoMsg("2nd rejected", rejected, ShouldBeNil)
+		SoMsg("reason", reason, ShouldEqual, RejectOverload)
+		release()
+		release, _ = a.admit(infra.ChainRequest, src)
+		SoMsg("admitted after release", release, ShouldNotBeNil)
+	})
+	Convey("Rate limit only applies to configured type", t, func() {
+		a := newAdmission(&AdmissionConfig{
+			Limits: map[infra.MessageType]MessageLimits{
+				infra.SegRequest: {PerSource: RateLimit{Rate: 1, Burst: 1}},
+			},
+		})
+		release, _ := a.admit(infra.SegRequest, src)
+		SoMsg("1st admitted", release, ShouldNotBeNil)
+		release, reason := a.admit(infra.SegRequest, src)
+		SoMsg("2nd rejected", release, ShouldBeNil)
+		SoMsg("reason", reason, ShouldEqual, RejectSourceRateLimit)
+		release, _ = a.admit(infra.ChainRequest, src)
+		SoMsg("other type admitted", release, ShouldNotBeNil)
+	})
+}
+
+func TestRejectAsync(t *testing.T) {
+	initAdmissionMetrics()
+	localIA := xtest.MustParseIA("1-ff00:0:111")
+	src := &snet.Addr{IA: xtest.MustParseIA("1-ff00:0:110")}
+	Convey("Acks for rejected requests are dropped if too many are pending", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		block := make(chan struct{})
+		rw := mock_infra.NewMockResponseWriter(ctrl)
+		rw.EXPECT().SendAckReply(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(context.Context, *ack.Ack) error {
+				<-block
+				return nil
+			},
+		).Times(maxRejectAcks)
+		a := newAdmission(&AdmissionConfig{})
+		var wg sync.WaitGroup
+		var done int32
+		cancelF := func() {
+			atomic.AddInt32(&done, 1)
+			wg.Done()
+		}
+		wg.Add(maxRejectAcks + 2)
+		for i := 0; i < maxRejectAcks+1; i++ {
+			a.rejectAsync(context.Background(), cancelF, rw, infra.SegReg, src, localIA,
+				RejectOverload)
+		}
+		SoMsg("dropped ack", atomic.LoadInt32(&done), ShouldEqual, 1)
+		a.rejectAsync(context.Background(), cancelF, rw, infra.SegRequest, src, localIA,
+			RejectOverload)
+		SoMsg("no ack needed", atomic.LoadInt32(&done), ShouldEqual, 2)
+		close(block)
+		wg.Wait()
+	})
+}
@@ -126,6 +126,9 @@ type Config struct {
 	// QUIC defines whether the Messenger should also operate on top of QUIC
 	// instead of only on UDP.
 	QUIC *QUICConfig
+	// Admission configures rate limits and the maximum number of concurrently
+	// running handlers. If it is nil, all incoming requests are handled.
+	Admission *AdmissionConfig
 }
 
 type QUICConfig struct {
@@ -181,6 +184,8 @@ type Messenger struct {
 	quicClient  *rpc.Client
 	quicServer  *rpc.Server
 	quicHandler *QUICHandler
+
+	admission *admission
 }
 
 // New creates a new Messenger based on config.
@@ -197,6 +202,11 @@ func New(config *Config) *Messenger {
 	var quicClient *rpc.Client
 	var quicHandler *QUICHandler
 
+	admission := newAdmission(config.Admission)
+	if admission != nil {
+		initAdmissionMetrics()
+	}
+
 	if config.QUIC != nil {
 		quicClient = &rpc.Client{
 			Conn:       config.QUIC.Conn,
@@ -208,6 +218,8 @@ func New(config *Config) *Messenger {
 			timeout:      config.HandlerTimeout,
 			parentLogger: config.Logger,
 			parentCtx:    ctx,
+			ia:           config.IA,
+			admission:    admission,
 		}
 		quicServer = &rpc.Server{
 			Conn:       config.QUIC.Conn,
@@ -238,6 +250,7 @@ func New(config *Config) *Messenger {
 		quicServer:      quicServer,
 		quicClient:      quicClient,
 		quicHandler:     quicHandler,
+		admission:       admission,
 	}
 }
 
@@ -691,13 +704,12 @@ func (m *Messenger) serve(ctx context.Context, cancelF context.CancelFunc, pld *
 	signedPld *ctrl.SignedPld, address net.Addr) {
 
 	logger := log.FromCtx(ctx)
-	ctx = infra.NewContextWithResponseWriter(ctx,
-		&UDPResponseWriter{
-			Messenger: m,
-			Remote:    address,
-			ID:        pld.ReqId,
-		},
-	)
+	rw := &UDPResponseWriter{
+		Messenger: m,
+		Remote:    address,
+		ID:        pld.ReqId,
+	}
+	ctx = infra.NewContextWithResponseWriter(ctx, rw)
 	// Validate that the message is of acceptable type, and that its top-level
 	// signature is correct.
 	msgType, msg, err := validate(pld)
@@ -721,9 +733,19 @@ func (m *Messenger) serve(ctx context.Context, cancelF context.CancelFunc, pld *
 			"msgType", msgType, "id", pld.ReqId)
 		return
 	}
+	release, reason := m.admission.admit(msgType, address)
+	if release == nil {
+		// Don't block the receive loop with sending the ack.
+		m.admission.rejectAsync(ctx, cancelF, rw, msgType, address, m.ia, reason)
+		return
+	}
+	span, ctx := startServerSpan(ctx, msgType, pld, address)
+	logger = loggerWithTrace(logger, span)
 	go func() {
 		defer log.LogPanicAndExit()
 		defer cancelF()
+		defer release()
+		defer span.Finish()
 		handler.Handle(infra.NewRequest(log.CtxWith(ctx, logger),
 			msg, signedPld, address, pld.ReqId))
 	}()
@@ -829,6 +851,16 @@ type pathingRequester struct {
 func (pr *pathingRequester) Request(ctx context.Context, pld *ctrl.Pld,
 	a net.Addr, downgradeToNotify bool) (*ctrl.Pld, error) {
 
+	span, ctx := startClientSpan(ctx, pld, a)
+	defer span.Finish()
+	reply, err := pr.request(ctx, pld, a, downgradeToNotify)
+	span.SetError(err)
+	return reply, err
+}
+
+func (pr *pathingRequester) request(ctx context.Context, pld *ctrl.Pld,
+	a net.Addr, downgradeToNotify bool) (*ctrl.Pld, error) {
+
 	newAddr, redirect, err := pr.addressRewriter.RedirectToQUIC(ctx, a)
 	if err != nil {
 		return nil, err
@@ -848,19 +880,29 @@ func (pr *pathingRequester) Request(ctx context.Context, pld *ctrl.Pld,
 }
 
 func (pr *pathingRequester) Notify(ctx context.Context, pld *ctrl.Pld, a net.Addr) error {
+	span, ctx := startClientSpan(ctx, pld, a)
+	defer span.Finish()
 	newAddr, _, err := pr.addressRewriter.RedirectToQUIC(ctx, a)
 	if err != nil {
+		span.SetError(err)
 		return err
 	}
-	return pr.requester.Notify(ctx, pld, newAddr)
+	err = pr.requester.Notify(ctx, pld, newAddr)
+	span.SetError(err)
+	return err
 }
 
 func (pr *pathingRequester) NotifyUnreliable(ctx context.Context, pld *ctrl.Pld, a net.Addr) error {
+	span, ctx := startClientSpan(ctx, pld, a)
+	defer span.Finish()
 	newAddr, _, err := pr.addressRewriter.RedirectToQUIC(ctx, a)
 	if err != nil {
+		span.SetError(err)
 		return err
 	}
-	return pr.requester.NotifyUnreliable(ctx, pld, newAddr)
+	err = pr.requester.NotifyUnreliable(ctx, pld, newAddr)
+	span.SetError(err)
+	return err
 }
 
 type QUICRequester struct {
@@ -43,7 +43,11 @@ var (
 	inResultsTotal *prometheus.CounterVec
 	inCallsLatency *prometheus.HistogramVec
 
-	initOnce sync.Once
+	inRejectedTotal     *prometheus.CounterVec
+	inRejectAcksDropped *prometheus.CounterVec
+
+	initOnce          sync.Once
+	initAdmissionOnce sync.Once
 )
 
 func initMetrics() {
@@ -70,6 +74,32 @@ func initMetrics() {
 	})
 }
 
+func initAdmissionMetrics() {
+	initAdmissionOnce.Do(func() {
+		inRejectedTotal = prom.NewCounterVec(promNamespace, "", "in_rejected_total",
+			"Total in calls rejected by admission control.",
+			[]string{prom.LabelOperation, prom.LabelSrc, prom.LabelResult})
+		inRejectAcksDropped = prom.NewCounterVec(promNamespace, "",
+			"in_reject_acks_dropped_total",
+			"Total acks for rejected in calls that were dropped because too many were pending.",
+			[]string{prom.LabelOperation})
+	})
+}
+
+func metricRejected(msgType infra.MessageType, src string, reason string) {
+	inRejectedTotal.With(prometheus.Labels{
+		prom.LabelOperation: msgType.MetricLabel(),
+		prom.LabelSrc:       src,
+		prom.LabelResult:    reason,
+	}).Inc()
+}
+
+func metricRejectAckDropped(msgType infra.MessageType) {
+	inRejectAcksDropped.With(prometheus.Labels{
+		prom.LabelOperation: msgType.MetricLabel(),
+	}).Inc()
+}
+
 func metricSrcValue(peer net.Addr, localIA addr.IA) string {
 	sAddr, ok := peer.(*snet.Addr)
 	if !ok {
@@ -22,6 +22,7 @@ import (
 	capnp "zombiezen.com/go/capnproto2"
 	"zombiezen.com/go/capnproto2/pogs"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/ctrl"
 	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/infra/rpc"
@@ -41,6 +42,9 @@ type QUICHandler struct {
 	timeout      time.Duration
 	parentLogger log.Logger
 	parentCtx    context.Context
+
+	ia        addr.IA
+	admission *admission
 }
 
 func (h *QUICHandler) ServeRPC(rw rpc.ReplyWriter, request *rpc.Request) {
@@ -76,13 +80,22 @@ func (h *QUICHandler) ServeRPC(rw rpc.ReplyWriter, request *rpc.Request) {
 	serveCtx, serveCancelF := context.WithTimeout(h.parentCtx, h.timeout)
 	defer serveCancelF()
 
-	serveCtx = infra.NewContextWithResponseWriter(serveCtx,
-		&QUICResponseWriter{
-			ReplyWriter: rw,
-			ID:          pld.ReqId,
-		},
-	)
-	serveCtx = log.CtxWith(serveCtx, h.parentLogger.New("debug_id", util.GetDebugID()))
+	responseWriter := &QUICResponseWriter{
+		ReplyWriter: rw,
+		ID:          pld.ReqId,
+	}
+	serveCtx = infra.NewContextWithResponseWriter(serveCtx, responseWriter)
+	span, serveCtx := startServerSpan(serveCtx, messageType, pld, request.Address)
+	defer span.Finish()
+	logger := loggerWithTrace(h.parentLogger.New("debug_id", util.GetDebugID()), span)
+	serveCtx = log.CtxWith(serveCtx, logger)
+
+	release, reason := h.admission.admit(messageType, request.Address)
+	if release == nil {
+		reject(serveCtx, responseWriter, messageType, request.Address, h.ia, reason)
+		return
+	}
+	defer release()
 
 	handler.Handle(infra.NewRequest(serveCtx, messageContent, signedPld,
 		request.Address, pld.ReqId))
@@ -0,0 +1,67 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messenger
+
+import (
+	"context"
+	"net"
+
+	"github.com/scionproto/scion/go/lib/ctrl"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/tracing"
+)
+
+// startServerSpan starts the span for handling a received message. If the
+// payload carries a span context, the span continues the remote trace.
+func startServerSpan(ctx context.Context, msgType infra.MessageType, pld *ctrl.Pld,
+	from net.Addr) (*tracing.Span, context.Context) {
+
+	var raw []byte
+	if pld.Data != nil {
+		raw = pld.TraceId
+	}
+	span, ctx := tracing.StartSpanFromRemote(ctx, msgType.String(), tracing.KindServer, raw)
+	span.SetTag("from", from)
+	span.SetTag("id", pld.ReqId)
+	return span, ctx
+}
+
+// startClientSpan starts the span for sending a message and injects the span
+// context into the payload, such that the remote can continue the trace.
+func startClientSpan(ctx context.Context, pld *ctrl.Pld,
+	to net.Addr) (*tracing.Span, context.Context) {
+
+	name := infra.None.String()
+	if msgType, _, err := validate(pld); err == nil {
+		name = msgType.String()
+	}
+	span, ctx := tracing.StartSpan(ctx, name, tracing.KindClient)
+	if span != nil && pld.Data != nil {
+		pld.TraceId = span.Context().Pack()
+		span.SetTag("id", pld.ReqId)
+	}
+	span.SetTag("to", to)
+	return span, ctx
+}
+
+// loggerWithTrace adds the trace ID to the logger, such that log entries can
+// be correlated with the trace.
+func loggerWithTrace(logger log.Logger, span *tracing.Span) log.Logger {
+	if span == nil {
+		return logger
+	}
+	return logger.New("trace_id", span.Context().TraceID)
+}
@@ -28,6 +28,7 @@ const (
 	AckRejectFailedToVerify = "Failed to verfiy"
 	AckRejectPolicyError    = "Message rejected due to policy"
 	AckRetryDBError         = "DB Error"
+	AckRetryRejected        = "Rejected by admission control"
 )
 
 // SendAckHelper binds the given arguments and returns a function that is convenient to call.
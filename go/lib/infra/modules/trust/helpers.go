@@ -24,6 +24,7 @@ import (
 	"github.com/scionproto/scion/go/lib/ctrl"
 	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/infra/modules/trust/trustdb"
+	"github.com/scionproto/scion/go/lib/keyconf"
 	"github.com/scionproto/scion/go/lib/scrypto/cert"
 	"github.com/scionproto/scion/go/lib/scrypto/trc"
 	"github.com/scionproto/scion/go/lib/util"
@@ -57,6 +58,37 @@ func CreateSignMeta(ctx context.Context, ia addr.IA,
 	return meta, nil
 }
 
+// KeyAlgos returns the algorithms of the signing keys of ia. The algorithm of
+// the AS signing key and the issuer signing key are taken from the newest
+// certificate chain, the ones of the root keys from the newest TRC. Keys that
+// ia does not hold default to ed25519.
+func KeyAlgos(ctx context.Context, ia addr.IA, trustDB trustdb.TrustDB) (keyconf.Algos, error) {
+	algos := keyconf.DefaultAlgos
+	c, err := trustDB.GetChainMaxVersion(ctx, ia)
+	if err != nil {
+		return algos, common.NewBasicError("Unable to find local certificate chain", err)
+	}
+	if c == nil {
+		return algos, common.NewBasicError("Local certificate chain not found", nil, "ia", ia)
+	}
+	t, err := trustDB.GetTRCMaxVersion(ctx, ia.I)
+	if err != nil {
+		return algos, common.NewBasicError("Unable to find local TRC", err)
+	}
+	if t == nil {
+		return algos, common.NewBasicError("Local TRC not found", nil, "isd", ia.I)
+	}
+	algos.Sign = c.Leaf.SignAlgorithm
+	if c.Issuer.Subject.Equal(ia) {
+		algos.IssSig = c.Issuer.SignAlgorithm
+	}
+	if coreAS, ok := t.CoreASes[ia]; ok {
+		algos.OnRoot = coreAS.OnlineKeyAlg
+		algos.OffRoot = coreAS.OfflineKeyAlg
+	}
+	return algos, nil
+}
+
 // VerifyChain verifies the chain based on the TRCs present in the store.
 func VerifyChain(ctx context.Context, subject addr.IA, chain *cert.Chain,
 	store infra.TrustStore) error {
@@ -25,6 +25,7 @@ import (
 	"github.com/scionproto/scion/go/lib/ctrl"
 	"github.com/scionproto/scion/go/lib/ctrl/cert_mgmt"
 	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/keysigner"
 	"github.com/scionproto/scion/go/lib/scrypto"
 	"github.com/scionproto/scion/go/lib/util"
 	"github.com/scionproto/scion/go/proto"
@@ -41,12 +42,22 @@ type BasicSigner struct {
 	meta      infra.SignerMeta
 	signType  proto.SignType
 	packedSrc common.RawBytes
-	key       common.RawBytes
+	key       keysigner.KeySigner
 }
 
 // NewBasicSigner creates a Signer that uses the supplied meta to sign
 // messages.
 func NewBasicSigner(key common.RawBytes, meta infra.SignerMeta) (*BasicSigner, error) {
+	ks, err := keysigner.NewRaw(key, meta.Algo)
+	if err != nil {
+		return nil, common.NewBasicError("Invalid signing key", err, "algo", meta.Algo)
+	}
+	return NewKeySigner(ks, meta)
+}
+
+// NewKeySigner creates a Signer that uses the supplied meta to sign messages
+// with the key signer. The key signer must use the algorithm of the meta.
+func NewKeySigner(key keysigner.KeySigner, meta infra.SignerMeta) (*BasicSigner, error) {
 	if meta.Src.IA.IsWildcard() {
 		return nil, common.NewBasicError("IA must not contain wildcard", nil, "ia", meta.Src.IA)
 	}
@@ -56,6 +67,10 @@ func NewBasicSigner(key common.RawBytes, meta infra.SignerMeta) (*BasicSigner, e
 	if meta.Src.TRCVer == scrypto.LatestVer {
 		return nil, common.NewBasicError("TRCVer must be valid", nil, "ver", meta.Src.TRCVer)
 	}
+	if key.Algo() != meta.Algo {
+		return nil, common.NewBasicError("Key algorithm does not match meta", nil,
+			"key", key.Algo(), "meta", meta.Algo)
+	}
 	signer := &BasicSigner{
 		meta:      meta,
 		key:       key,
@@ -64,6 +79,10 @@ func NewBasicSigner(key common.RawBytes, meta infra.SignerMeta) (*BasicSigner, e
 	switch meta.Algo {
 	case scrypto.Ed25519:
 		signer.signType = proto.SignType_ed25519
+	case scrypto.ECDSAP256:
+		signer.signType = proto.SignType_ecdsaP256
+	case scrypto.ECDSAP384:
+		signer.signType = proto.SignType_ecdsaP384
 	default:
 		return nil, common.NewBasicError("Unsupported signing algorithm", nil, "algo", meta.Algo)
 	}
@@ -74,7 +93,7 @@ func NewBasicSigner(key common.RawBytes, meta infra.SignerMeta) (*BasicSigner, e
 func (b *BasicSigner) Sign(msg common.RawBytes) (*proto.SignS, error) {
 	var err error
 	sign := proto.NewSignS(b.signType, append(common.RawBytes(nil), b.packedSrc...))
-	sign.Signature, err = scrypto.Sign(sign.SigInput(msg, true), b.key, b.meta.Algo)
+	sign.Signature, err = b.key.Sign(sign.SigInput(msg, true))
 	return sign, err
 }
 
@@ -0,0 +1,109 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trust
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/infra/modules/trust/trustdb/mock_trustdb"
+	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/keysigner"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/scrypto/cert"
+	"github.com/scionproto/scion/go/lib/scrypto/trc"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestNewKeySignerECDSA(t *testing.T) {
+	Convey("Given a key directory with a generated P-256 AS signing key", t, func() {
+		dir, cleanF := xtest.MustTempDir("", "signhelper")
+		defer cleanF()
+		// Key files store the private scalar of ECDSA keys, see scion-pki keys gen.
+		pub, priv, err := scrypto.GenKeyPair(scrypto.ECDSAP256)
+		xtest.FailOnErr(t, err)
+		xtest.FailOnErr(t, ioutil.WriteFile(filepath.Join(dir, keyconf.SigKeyFile),
+			[]byte(base64.StdEncoding.EncodeToString(priv)), 0600))
+		cfg := &keysigner.Config{}
+		cfg.InitDefaults()
+		meta := infra.SignerMeta{
+			Src: ctrl.SignSrcDef{
+				IA:       xtest.MustParseIA("1-ff00:0:110"),
+				ChainVer: 1,
+				TRCVer:   1,
+			},
+			Algo: scrypto.ECDSAP256,
+		}
+		Convey("The key loaded with its algorithm creates valid signatures", func() {
+			keys, err := cfg.Load(dir, keyconf.Algos{Sign: scrypto.ECDSAP256}, false)
+			SoMsg("load err", err, ShouldBeNil)
+			SoMsg("pub", keys.Sign.PublicKey(), ShouldResemble, pub)
+			signer, err := NewKeySigner(keys.Sign, meta)
+			SoMsg("signer err", err, ShouldBeNil)
+			msg := common.RawBytes("message")
+			sign, err := signer.Sign(msg)
+			SoMsg("sign err", err, ShouldBeNil)
+			err = scrypto.Verify(sign.SigInput(msg, false), sign.Signature, pub,
+				scrypto.ECDSAP256)
+			SoMsg("verify", err, ShouldBeNil)
+		})
+		Convey("The key loaded as ed25519 does not match the meta", func() {
+			keys, err := cfg.Load(dir, keyconf.DefaultAlgos, false)
+			SoMsg("load err", err, ShouldBeNil)
+			_, err = NewKeySigner(keys.Sign, meta)
+			SoMsg("signer err", err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestKeyAlgos(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	Convey("KeyAlgos takes the algorithms from the chain and the TRC", t, func() {
+		mctrl := gomock.NewController(t)
+		defer mctrl.Finish()
+		db := mock_trustdb.NewMockTrustDB(mctrl)
+		chain := &cert.Chain{
+			Leaf:   &cert.Certificate{SignAlgorithm: scrypto.ECDSAP256},
+			Issuer: &cert.Certificate{Subject: ia, SignAlgorithm: scrypto.ECDSAP384},
+		}
+		localTRC := &trc.TRC{
+			CoreASes: trc.CoreASMap{
+				ia: &trc.CoreAS{
+					OnlineKeyAlg:  scrypto.ECDSAP256,
+					OfflineKeyAlg: scrypto.Ed25519,
+				},
+			},
+		}
+		db.EXPECT().GetChainMaxVersion(gomock.Any(), ia).Return(chain, nil)
+		db.EXPECT().GetTRCMaxVersion(gomock.Any(), ia.I).Return(localTRC, nil)
+		algos, err := KeyAlgos(context.Background(), ia, db)
+		SoMsg("err", err, ShouldBeNil)
+		SoMsg("algos", algos, ShouldResemble, keyconf.Algos{
+			Sign:    scrypto.ECDSAP256,
+			IssSig:  scrypto.ECDSAP384,
+			OnRoot:  scrypto.ECDSAP256,
+			OffRoot: scrypto.Ed25519,
+		})
+	})
+}
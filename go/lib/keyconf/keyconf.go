@@ -58,9 +58,39 @@ const (
 	ErrorUnknown = "Unknown algorithm"
 )
 
-// Load loads key configuration from specified path.
+// Algos contains the algorithms of the AS signing keys.
+type Algos struct {
+	// Sign is the algorithm of the AS signing key.
+	Sign string
+	// IssSig is the algorithm of the issuer signing key.
+	IssSig string
+	// OnRoot is the algorithm of the online root key.
+	OnRoot string
+	// OffRoot is the algorithm of the offline root key.
+	OffRoot string
+}
+
+// DefaultAlgos uses ed25519 for all signing keys.
+var DefaultAlgos = Algos{
+	Sign:    scrypto.Ed25519,
+	IssSig:  scrypto.Ed25519,
+	OnRoot:  scrypto.Ed25519,
+	OffRoot: scrypto.Ed25519,
+}
+
+// Load loads key configuration from specified path. The signing keys are
+// loaded as ed25519 keys.
 // issSigKey, onKey, offKey, master can be set true, to load the respective keys.
 func Load(path string, issSigKey, onKey, offKey, master bool) (*Conf, error) {
+	return LoadWithAlgos(path, DefaultAlgos, issSigKey, onKey, offKey, master)
+}
+
+// LoadWithAlgos loads key configuration from specified path. The signing keys
+// are loaded with the algorithms in algos.
+// issSigKey, onKey, offKey, master can be set true, to load the respective keys.
+func LoadWithAlgos(path string, algos Algos, issSigKey, onKey, offKey,
+	master bool) (*Conf, error) {
+
 	conf := &Conf{}
 	var err error
 	conf.DecryptKey, err = loadKeyCond(filepath.Join(path, DecKeyFile),
@@ -68,20 +98,20 @@ func Load(path string, issSigKey, onKey, offKey, master bool) (*Conf, error) {
 	if err != nil {
 		return nil, err
 	}
-	conf.SignKey, err = loadKeyCond(filepath.Join(path, SigKeyFile), scrypto.Ed25519, true)
+	conf.SignKey, err = loadKeyCond(filepath.Join(path, SigKeyFile), algos.Sign, true)
 	if err != nil {
 		return nil, err
 	}
 	conf.IssSigKey, err = loadKeyCond(filepath.Join(path, IssSigKeyFile),
-		scrypto.Ed25519, issSigKey)
+		algos.IssSig, issSigKey)
 	if err != nil {
 		return nil, err
 	}
-	conf.OffRootKey, err = loadKeyCond(filepath.Join(path, OffKeyFile), scrypto.Ed25519, offKey)
+	conf.OffRootKey, err = loadKeyCond(filepath.Join(path, OffKeyFile), algos.OffRoot, offKey)
 	if err != nil {
 		return nil, err
 	}
-	conf.OnRootKey, err = loadKeyCond(filepath.Join(path, OnKeyFile), scrypto.Ed25519, onKey)
+	conf.OnRootKey, err = loadKeyCond(filepath.Join(path, OnKeyFile), algos.OnRoot, onKey)
 	if err != nil {
 		return nil, err
 	}
@@ -116,12 +146,27 @@ func LoadKey(file string, algo string) (common.RawBytes, error) {
 	if err != nil {
 		return nil, common.NewBasicError(ErrorParse, err)
 	}
-	dbuf = dbuf[:n]
+	return ParseKey(dbuf[:n], algo)
+}
+
+// ParseKey returns the key for the decoded key file content. For Ed25519, the
+// content is the seed of the private key. For ECDSA, the content is the
+// private scalar.
+func ParseKey(raw common.RawBytes, algo string) (common.RawBytes, error) {
 	switch strings.ToLower(algo) {
 	case RawKey, scrypto.Curve25519xSalsa20Poly1305:
-		return dbuf, nil
+		return raw, nil
 	case scrypto.Ed25519:
-		return common.RawBytes(ed25519.NewKeyFromSeed(dbuf)), nil
+		if len(raw) != ed25519.SeedSize {
+			return nil, common.NewBasicError(ErrorParse, nil, "expected", ed25519.SeedSize,
+				"actual", len(raw))
+		}
+		return common.RawBytes(ed25519.NewKeyFromSeed(raw)), nil
+	case scrypto.ECDSAP256, scrypto.ECDSAP384:
+		if _, err := scrypto.PublicKey(raw, algo); err != nil {
+			return nil, common.NewBasicError(ErrorParse, err)
+		}
+		return raw, nil
 	default:
 		return nil, common.NewBasicError(ErrorUnknown, nil, "algo", algo)
 	}
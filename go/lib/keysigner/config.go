@@ -0,0 +1,233 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keysigner
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/config"
+	"github.com/scionproto/scion/go/lib/keyconf"
+)
+
+type Backend string
+
+const (
+	BackendFile          Backend = "file"
+	BackendEncryptedFile Backend = "encrypted_file"
+	BackendPKCS11        Backend = "pkcs11"
+)
+
+const (
+	// DefaultSignKeyLabel is the default label of the AS signing key on a
+	// PKCS#11 token.
+	DefaultSignKeyLabel = "as-sig"
+	// DefaultIssSigKeyLabel is the default label of the issuer signing key
+	// on a PKCS#11 token.
+	DefaultIssSigKeyLabel = "iss-sig"
+)
+
+var _ config.Config = (*Config)(nil)
+
+// Config is the configuration of the backend that holds the AS signing key
+// and the issuer signing key.
+type Config struct {
+	// Backend is the key backend (file|encrypted_file|pkcs11).
+	Backend Backend
+	// PassphraseFile is the file containing the passphrase of the encrypted
+	// key files. Only used by the encrypted_file backend.
+	PassphraseFile string
+	// PKCS11 is the configuration of the pkcs11 backend.
+	PKCS11 PKCS11Config
+}
+
+func (cfg *Config) InitDefaults() {
+	if cfg.Backend == "" {
+		cfg.Backend = BackendFile
+	}
+	config.InitAll(&cfg.PKCS11)
+}
+
+func (cfg *Config) Validate() error {
+	switch cfg.Backend {
+	case BackendFile:
+		return nil
+	case BackendEncryptedFile:
+		if cfg.PassphraseFile == "" {
+			return common.NewBasicError("PassphraseFile must be set", nil,
+				"backend", cfg.Backend)
+		}
+		return nil
+	case BackendPKCS11:
+		return config.ValidateAll(&cfg.PKCS11)
+	}
+	return common.NewBasicError("Unsupported backend", nil, "backend", cfg.Backend)
+}
+
+func (cfg *Config) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
+	config.WriteString(dst, keysSample)
+	config.WriteSample(dst, path, ctx, &cfg.PKCS11)
+}
+
+func (cfg *Config) ConfigName() string {
+	return "keys"
+}
+
+// Keys contains the signers of the AS keys.
+type Keys struct {
+	// Sign is the AS signing key.
+	Sign KeySigner
+	// IssSig is the issuer signing key. It is only loaded for issuing ASes.
+	IssSig KeySigner
+
+	token *Token
+}
+
+// Close releases the resources held by the key backend.
+func (k *Keys) Close() error {
+	if k.token == nil {
+		return nil
+	}
+	return k.token.Close()
+}
+
+// Load loads the AS signing key and, if issuer is set, the issuer signing key
+// from the configured backend. keyDir is the directory that contains the key
+// files. The keys are loaded with the algorithms in algos.
+func (cfg *Config) Load(keyDir string, algos keyconf.Algos, issuer bool) (*Keys, error) {
+	switch cfg.Backend {
+	case BackendFile:
+		return loadFiles(keyDir, algos, issuer, func(file, algo string) (common.RawBytes, error) {
+			return keyconf.LoadKey(file, algo)
+		})
+	case BackendEncryptedFile:
+		passphrase, err := readSecret(cfg.PassphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		return loadFiles(keyDir, algos, issuer, func(file, algo string) (common.RawBytes, error) {
+			return LoadEncryptedKey(file+EncryptedSuffix, []byte(passphrase), algo)
+		})
+	case BackendPKCS11:
+		return cfg.PKCS11.load(algos, issuer)
+	}
+	return nil, common.NewBasicError("Unsupported backend", nil, "backend", cfg.Backend)
+}
+
+func loadFiles(keyDir string, algos keyconf.Algos, issuer bool,
+	load func(file, algo string) (common.RawBytes, error)) (*Keys, error) {
+
+	keys := &Keys{}
+	key, err := load(filepath.Join(keyDir, keyconf.SigKeyFile), algos.Sign)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to load AS signing key", err)
+	}
+	if keys.Sign, err = NewRaw(key, algos.Sign); err != nil {
+		return nil, err
+	}
+	if !issuer {
+		return keys, nil
+	}
+	key, err = load(filepath.Join(keyDir, keyconf.IssSigKeyFile), algos.IssSig)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to load issuer signing key", err)
+	}
+	if keys.IssSig, err = NewRaw(key, algos.IssSig); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+var _ config.Config = (*PKCS11Config)(nil)
+
+// PKCS11Config is the configuration of the pkcs11 key backend.
+type PKCS11Config struct {
+	// Module is the path of the PKCS#11 module.
+	Module string
+	// TokenLabel is the label of the token that holds the keys.
+	TokenLabel string
+	// PINFile is the file containing the user PIN of the token.
+	PINFile string
+	// SignKeyLabel is the label of the AS signing key.
+	SignKeyLabel string
+	// IssSigKeyLabel is the label of the issuer signing key.
+	IssSigKeyLabel string
+}
+
+func (cfg *PKCS11Config) InitDefaults() {
+	if cfg.SignKeyLabel == "" {
+		cfg.SignKeyLabel = DefaultSignKeyLabel
+	}
+	if cfg.IssSigKeyLabel == "" {
+		cfg.IssSigKeyLabel = DefaultIssSigKeyLabel
+	}
+}
+
+func (cfg *PKCS11Config) Validate() error {
+	if cfg.Module == "" {
+		return common.NewBasicError("Module must be set", nil)
+	}
+	if cfg.TokenLabel == "" {
+		return common.NewBasicError("TokenLabel must be set", nil)
+	}
+	if cfg.PINFile == "" {
+		return common.NewBasicError("PINFile must be set", nil)
+	}
+	return nil
+}
+
+func (cfg *PKCS11Config) Sample(dst io.Writer, _ config.Path, _ config.CtxMap) {
+	config.WriteString(dst, pkcs11Sample)
+}
+
+func (cfg *PKCS11Config) ConfigName() string {
+	return "pkcs11"
+}
+
+func (cfg *PKCS11Config) load(algos keyconf.Algos, issuer bool) (*Keys, error) {
+	pin, err := readSecret(cfg.PINFile)
+	if err != nil {
+		return nil, err
+	}
+	token, err := OpenToken(cfg.Module, cfg.TokenLabel, pin)
+	if err != nil {
+		return nil, err
+	}
+	keys := &Keys{token: token}
+	if keys.Sign, err = token.Signer(cfg.SignKeyLabel, algos.Sign); err != nil {
+		token.Close()
+		return nil, common.NewBasicError("Unable to load AS signing key", err)
+	}
+	if !issuer {
+		return keys, nil
+	}
+	if keys.IssSig, err = token.Signer(cfg.IssSigKeyLabel, algos.IssSig); err != nil {
+		token.Close()
+		return nil, common.NewBasicError("Unable to load issuer signing key", err)
+	}
+	return keys, nil
+}
+
+// readSecret reads a secret from the file. Surrounding whitespace is removed.
+func readSecret(file string) (string, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", common.NewBasicError("Unable to read secret", err, "file", file)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keysigner
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/keyconf"
+)
+
+const (
+	// EncryptedSuffix is the suffix of encrypted key files.
+	EncryptedSuffix = ".enc"
+
+	saltLen  = 16
+	nonceLen = 24
+	// Parameters of the scrypt key derivation function.
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+const (
+	ErrorDecrypt = "Unable to decrypt key"
+)
+
+// EncryptKey encrypts the raw key (e.g., an Ed25519 seed) with the passphrase
+// and returns the content of the encrypted key file. The raw key is sealed with
+// NaCl secretbox, using a key that is derived from the passphrase with scrypt.
+// The encrypted key file contains the base64 encoding of salt || nonce || box.
+func EncryptKey(raw common.RawBytes, passphrase []byte) (common.RawBytes, error) {
+	salt := make([]byte, saltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, common.NewBasicError("Unable to generate salt", err)
+	}
+	var nonce [nonceLen]byte
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, common.NewBasicError("Unable to generate nonce", err)
+	}
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	out := append(salt, nonce[:]...)
+	out = secretbox.Seal(out, raw, &nonce, key)
+	enc := make([]byte, base64.StdEncoding.EncodedLen(len(out)))
+	base64.StdEncoding.Encode(enc, out)
+	return enc, nil
+}
+
+// DecryptKey decrypts the content of an encrypted key file and returns the
+// raw key.
+func DecryptKey(enc common.RawBytes, passphrase []byte) (common.RawBytes, error) {
+	b := make([]byte, base64.StdEncoding.DecodedLen(len(enc)))
+	n, err := base64.StdEncoding.Decode(b, enc)
+	if err != nil {
+		return nil, common.NewBasicError(keyconf.ErrorParse, err)
+	}
+	b = b[:n]
+	if len(b) < saltLen+nonceLen+secretbox.Overhead {
+		return nil, common.NewBasicError(keyconf.ErrorParse, nil, "len", len(b))
+	}
+	var nonce [nonceLen]byte
+	copy(nonce[:], b[saltLen:saltLen+nonceLen])
+	key, err := deriveKey(passphrase, b[:saltLen])
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := secretbox.Open(nil, b[saltLen+nonceLen:], &nonce, key)
+	if !ok {
+		return nil, common.NewBasicError(ErrorDecrypt, nil)
+	}
+	return raw, nil
+}
+
+// LoadEncryptedKey loads the key from the encrypted key file. The key is
+// parsed according to the algorithm, see keyconf.ParseKey.
+func LoadEncryptedKey(file string, passphrase []byte, algo string) (common.RawBytes, error) {
+	enc, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, common.NewBasicError(keyconf.ErrorOpen, err, "file", file)
+	}
+	raw, err := DecryptKey(enc, passphrase)
+	if err != nil {
+		return nil, common.NewBasicError(ErrorDecrypt, err, "file", file)
+	}
+	return keyconf.ParseKey(raw, algo)
+}
+
+func deriveKey(passphrase, salt []byte) (*[32]byte, error) {
+	k, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to derive key", err)
+	}
+	var key [32]byte
+	copy(key[:], k)
+	return &key, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package keysigner provides signers for the private keys of an AS.
+//
+// A KeySigner creates signatures without necessarily exposing the private key.
+// The following backends are supported:
+//  - file: the key is read from an unencrypted key file (e.g., as-sig.seed).
+//  - encrypted_file: the key is read from a key file that is encrypted with a
+//    passphrase (e.g., as-sig.seed.enc). See EncryptKey for the file format.
+//  - pkcs11: the key is stored on a PKCS#11 token and never leaves it.
+package keysigner
+
+import (
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/scrypto"
+)
+
+// KeySigner creates signatures with a private key.
+type KeySigner interface {
+	// Sign signs the input.
+	Sign(input common.RawBytes) (common.RawBytes, error)
+	// PublicKey returns the public key that verifies the signatures.
+	PublicKey() common.RawBytes
+	// Algo returns the signing algorithm.
+	Algo() string
+}
+
+var _ KeySigner = (*Raw)(nil)
+
+// Raw is a KeySigner for a private key that is held in memory.
+type Raw struct {
+	key  common.RawBytes
+	pub  common.RawBytes
+	algo string
+}
+
+// NewRaw creates a KeySigner for the private key.
+func NewRaw(key common.RawBytes, algo string) (*Raw, error) {
+	pub, err := scrypto.PublicKey(key, algo)
+	if err != nil {
+		return nil, err
+	}
+	return &Raw{key: key, pub: pub, algo: algo}, nil
+}
+
+func (r *Raw) Sign(input common.RawBytes) (common.RawBytes, error) {
+	return scrypto.Sign(input, r.key, r.algo)
+}
+
+func (r *Raw) PublicKey() common.RawBytes {
+	return r.pub
+}
+
+func (r *Raw) Algo() string {
+	return r.algo
+}
@@ -0,0 +1,173 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keysigner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestRaw(t *testing.T) {
+	Convey("Given a raw signer", t, func() {
+		key, err := keyconf.LoadKey("testdata/keys/as-sig.seed", scrypto.Ed25519)
+		xtest.FailOnErr(t, err)
+		s, err := NewRaw(key, scrypto.Ed25519)
+		SoMsg("err", err, ShouldBeNil)
+		Convey("Signatures verify with the public key", func() {
+			msg := common.RawBytes("message")
+			sig, err := s.Sign(msg)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("verify", scrypto.Verify(msg, sig, s.PublicKey(), s.Algo()), ShouldBeNil)
+		})
+	})
+	Convey("Given an ECDSA raw signer", t, func() {
+		_, key, err := scrypto.GenKeyPair(scrypto.ECDSAP256)
+		xtest.FailOnErr(t, err)
+		s, err := NewRaw(key, scrypto.ECDSAP256)
+		SoMsg("err", err, ShouldBeNil)
+		msg := common.RawBytes("message")
+		sig, err := s.Sign(msg)
+		SoMsg("err", err, ShouldBeNil)
+		SoMsg("verify", scrypto.Verify(msg, sig, s.PublicKey(), s.Algo()), ShouldBeNil)
+	})
+	Convey("NewRaw fails for invalid keys", t, func() {
+		_, err := NewRaw(make(common.RawBytes, 10), scrypto.Ed25519)
+		SoMsg("size", err, ShouldNotBeNil)
+		_, err = NewRaw(make(common.RawBytes, 64), "rsa")
+		SoMsg("algo", err, ShouldNotBeNil)
+	})
+}
+
+func TestEncryptKey(t *testing.T) {
+	Convey("Encrypted keys can be decrypted with the passphrase", t, func() {
+		raw := common.RawBytes("raw key")
+		enc, err := EncryptKey(raw, []byte("secret"))
+		SoMsg("err", err, ShouldBeNil)
+		dec, err := DecryptKey(enc, []byte("secret"))
+		SoMsg("err", err, ShouldBeNil)
+		SoMsg("dec", dec, ShouldResemble, raw)
+		_, err = DecryptKey(enc, []byte("wrong"))
+		SoMsg("wrong passphrase", err, ShouldNotBeNil)
+		_, err = DecryptKey(enc[:20], []byte("secret"))
+		SoMsg("truncated", err, ShouldNotBeNil)
+	})
+}
+
+func TestConfigLoad(t *testing.T) {
+	asSig, err := keyconf.LoadKey("testdata/keys/as-sig.seed", scrypto.Ed25519)
+	xtest.FailOnErr(t, err)
+	issSig, err := keyconf.LoadKey("testdata/keys/core-sig.seed", scrypto.Ed25519)
+	xtest.FailOnErr(t, err)
+	Convey("The file backend loads the key files", t, func() {
+		cfg := &Config{}
+		cfg.InitDefaults()
+		keys, err := cfg.Load("testdata/keys", keyconf.DefaultAlgos, true)
+		SoMsg("err", err, ShouldBeNil)
+		SoMsg("sign", keys.Sign, ShouldResemble, mustRaw(t, asSig))
+		SoMsg("iss", keys.IssSig, ShouldResemble, mustRaw(t, issSig))
+		keys, err = cfg.Load("testdata/keys", keyconf.DefaultAlgos, false)
+		SoMsg("err", err, ShouldBeNil)
+		SoMsg("no iss", keys.IssSig, ShouldBeNil)
+	})
+	Convey("The encrypted_file backend loads the encrypted key files", t, func() {
+		dir, cleanF := xtest.MustTempDir("", "keysigner")
+		defer cleanF()
+		passFile := filepath.Join(dir, "passphrase")
+		xtest.FailOnErr(t, ioutil.WriteFile(passFile, []byte("secret\n"), 0600))
+		encrypt(t, "testdata/keys/as-sig.seed", filepath.Join(dir, keyconf.SigKeyFile))
+		cfg := &Config{Backend: BackendEncryptedFile, PassphraseFile: passFile}
+		cfg.InitDefaults()
+		keys, err := cfg.Load(dir, keyconf.DefaultAlgos, false)
+		SoMsg("err", err, ShouldBeNil)
+		SoMsg("sign", keys.Sign, ShouldResemble, mustRaw(t, asSig))
+		_, err = cfg.Load(dir, keyconf.DefaultAlgos, true)
+		SoMsg("missing iss", err, ShouldNotBeNil)
+	})
+}
+
+func TestParseECPoint(t *testing.T) {
+	Convey("parseECPoint handles DER encoded and raw points", t, func() {
+		pub := make([]byte, 32)
+		pub[0] = 0x42
+		p, err := parseECPoint(append([]byte{0x04, 32}, pub...), 32)
+		SoMsg("der err", err, ShouldBeNil)
+		SoMsg("der", p, ShouldResemble, common.RawBytes(pub))
+		p, err = parseECPoint(pub, 32)
+		SoMsg("raw err", err, ShouldBeNil)
+		SoMsg("raw", p, ShouldResemble, common.RawBytes(pub))
+		_, err = parseECPoint(pub[:10], 32)
+		SoMsg("invalid", err, ShouldNotBeNil)
+	})
+	Convey("parseECPoint handles DER encoded ECDSA points", t, func() {
+		pub, _, err := scrypto.GenKeyPair(scrypto.ECDSAP256)
+		xtest.FailOnErr(t, err)
+		size := pubKeySize(scrypto.ECDSAP256)
+		p, err := parseECPoint(append([]byte{0x04, byte(size)}, pub...), size)
+		SoMsg("der err", err, ShouldBeNil)
+		SoMsg("der", p, ShouldResemble, pub)
+		p, err = parseECPoint(pub, size)
+		SoMsg("raw err", err, ShouldBeNil)
+		SoMsg("raw", p, ShouldResemble, pub)
+	})
+}
+
+// TestPKCS11 runs against a token that holds a key pair with the label
+// as-sig, e.g., a SoftHSM token. The algorithm of the key is read from
+// PKCS11_ALGO and defaults to ed25519. It is skipped if PKCS11_MODULE is not
+// set.
+func TestPKCS11(t *testing.T) {
+	module := os.Getenv("PKCS11_MODULE")
+	if module == "" {
+		t.Skip("PKCS11_MODULE not set")
+	}
+	algo := os.Getenv("PKCS11_ALGO")
+	if algo == "" {
+		algo = scrypto.Ed25519
+	}
+	Convey("Signatures of the token verify with its public key", t, func() {
+		token, err := OpenToken(module, os.Getenv("PKCS11_TOKEN"), os.Getenv("PKCS11_PIN"))
+		SoMsg("open err", err, ShouldBeNil)
+		defer token.Close()
+		s, err := token.Signer(DefaultSignKeyLabel, algo)
+		SoMsg("signer err", err, ShouldBeNil)
+		msg := common.RawBytes("message")
+		sig, err := s.Sign(msg)
+		SoMsg("sign err", err, ShouldBeNil)
+		SoMsg("verify", scrypto.Verify(msg, sig, s.PublicKey(), s.Algo()), ShouldBeNil)
+	})
+}
+
+func mustRaw(t *testing.T, key common.RawBytes) *Raw {
+	s, err := NewRaw(key, scrypto.Ed25519)
+	xtest.FailOnErr(t, err)
+	return s
+}
+
+func encrypt(t *testing.T, src, dst string) {
+	raw, err := keyconf.LoadKey(src, keyconf.RawKey)
+	xtest.FailOnErr(t, err)
+	enc, err := EncryptKey(raw, []byte("secret"))
+	xtest.FailOnErr(t, err)
+	xtest.FailOnErr(t, ioutil.WriteFile(dst+EncryptedSuffix, enc, 0600))
+}
@@ -0,0 +1,39 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keysignertest
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/keysigner"
+)
+
+func InitTestConfig(cfg *keysigner.Config) {
+	cfg.Backend = keysigner.BackendPKCS11
+	cfg.PassphraseFile = "passphrase"
+	cfg.PKCS11.Module = "module"
+	cfg.PKCS11.SignKeyLabel = "sign"
+}
+
+func CheckTestConfig(cfg *keysigner.Config) {
+	SoMsg("Backend", cfg.Backend, ShouldEqual, keysigner.BackendFile)
+	SoMsg("PassphraseFile", cfg.PassphraseFile, ShouldBeEmpty)
+	SoMsg("Module", cfg.PKCS11.Module, ShouldBeEmpty)
+	SoMsg("TokenLabel", cfg.PKCS11.TokenLabel, ShouldBeEmpty)
+	SoMsg("PINFile", cfg.PKCS11.PINFile, ShouldBeEmpty)
+	SoMsg("SignKeyLabel", cfg.PKCS11.SignKeyLabel, ShouldEqual, keysigner.DefaultSignKeyLabel)
+	SoMsg("IssSigKeyLabel", cfg.PKCS11.IssSigKeyLabel, ShouldEqual,
+		keysigner.DefaultIssSigKeyLabel)
+}
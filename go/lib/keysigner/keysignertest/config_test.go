@@ -0,0 +1,56 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keysignertest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/keysigner"
+)
+
+func TestConfigSample(t *testing.T) {
+	Convey("Sample correct", t, func() {
+		var sample bytes.Buffer
+		var cfg keysigner.Config
+		cfg.Sample(&sample, nil, nil)
+		InitTestConfig(&cfg)
+		meta, err := toml.Decode(sample.String(), &cfg)
+		SoMsg("err", err, ShouldBeNil)
+		SoMsg("unparsed", meta.Undecoded(), ShouldBeEmpty)
+		CheckTestConfig(&cfg)
+	})
+}
+
+func TestConfigValidate(t *testing.T) {
+	Convey("Validate checks the backend configuration", t, func() {
+		var cfg keysigner.Config
+		cfg.InitDefaults()
+		SoMsg("file", cfg.Validate(), ShouldBeNil)
+		cfg.Backend = keysigner.BackendEncryptedFile
+		SoMsg("no passphrase", cfg.Validate(), ShouldNotBeNil)
+		cfg.PassphraseFile = "passphrase"
+		SoMsg("encrypted", cfg.Validate(), ShouldBeNil)
+		cfg.Backend = keysigner.BackendPKCS11
+		SoMsg("no module", cfg.Validate(), ShouldNotBeNil)
+		cfg.PKCS11 = keysigner.PKCS11Config{Module: "m", TokenLabel: "t", PINFile: "p"}
+		SoMsg("pkcs11", cfg.Validate(), ShouldBeNil)
+		cfg.Backend = "hsm"
+		SoMsg("unknown", cfg.Validate(), ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,229 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keysigner
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/scrypto"
+)
+
+// ckmEdDSA is the EdDSA mechanism defined in PKCS#11 v3.0. SoftHSM v2.5 and
+// later support it for Ed25519 keys.
+const ckmEdDSA = 0x1057
+
+// Token is an open session to a PKCS#11 token. All signers of a token share
+// its session.
+type Token struct {
+	mtx     sync.Mutex
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+}
+
+// OpenToken loads the PKCS#11 module and logs into the token with the
+// configured label.
+func OpenToken(module, tokenLabel, pin string) (*Token, error) {
+	ctx := pkcs11.New(module)
+	if ctx == nil {
+		return nil, common.NewBasicError("Unable to load PKCS#11 module", nil, "module", module)
+	}
+	err := ctx.Initialize()
+	if err != nil && err != pkcs11.Error(pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		ctx.Destroy()
+		return nil, common.NewBasicError("Unable to initialize PKCS#11 module", err,
+			"module", module)
+	}
+	t := &Token{ctx: ctx}
+	if err := t.open(tokenLabel, pin); err != nil {
+		ctx.Finalize()
+		ctx.Destroy()
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Token) open(tokenLabel, pin string) error {
+	slots, err := t.ctx.GetSlotList(true)
+	if err != nil {
+		return common.NewBasicError("Unable to list PKCS#11 slots", err)
+	}
+	for _, slot := range slots {
+		info, err := t.ctx.GetTokenInfo(slot)
+		if err != nil {
+			return common.NewBasicError("Unable to get PKCS#11 token info", err, "slot", slot)
+		}
+		if info.Label != tokenLabel {
+			continue
+		}
+		t.session, err = t.ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+		if err != nil {
+			return common.NewBasicError("Unable to open PKCS#11 session", err,
+				"token", tokenLabel)
+		}
+		err = t.ctx.Login(t.session, pkcs11.CKU_USER, pin)
+		if err != nil && err != pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+			t.ctx.CloseSession(t.session)
+			return common.NewBasicError("Unable to log into PKCS#11 token", err,
+				"token", tokenLabel)
+		}
+		return nil
+	}
+	return common.NewBasicError("PKCS#11 token not found", nil, "token", tokenLabel)
+}
+
+// Signer returns a signer for the private key with the given label. The
+// token must hold a public key object with the same label. Ed25519 keys sign
+// with the EdDSA mechanism, ECDSA keys with the CKM_ECDSA mechanism.
+func (t *Token) Signer(keyLabel, algo string) (*PKCS11, error) {
+	switch algo {
+	case scrypto.Ed25519, scrypto.ECDSAP256, scrypto.ECDSAP384:
+	default:
+		return nil, common.NewBasicError(scrypto.UnsupportedSignAlgo, nil, "algo", algo)
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	key, err := t.findObject(pkcs11.CKO_PRIVATE_KEY, keyLabel)
+	if err != nil {
+		return nil, err
+	}
+	pubObj, err := t.findObject(pkcs11.CKO_PUBLIC_KEY, keyLabel)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := t.ctx.GetAttributeValue(t.session, pubObj,
+		[]*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil)})
+	if err != nil {
+		return nil, common.NewBasicError("Unable to read public key", err, "label", keyLabel)
+	}
+	pub, err := parseECPoint(attrs[0].Value, pubKeySize(algo))
+	if err != nil {
+		return nil, common.NewBasicError("Unable to parse public key", err, "label", keyLabel)
+	}
+	return &PKCS11{token: t, key: key, pub: pub, algo: algo}, nil
+}
+
+// Close logs out of the token and unloads the module.
+func (t *Token) Close() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.ctx.Logout(t.session)
+	t.ctx.CloseSession(t.session)
+	err := t.ctx.Finalize()
+	t.ctx.Destroy()
+	return err
+}
+
+// findObject returns the object of the class with the label. The caller must
+// hold the lock.
+func (t *Token) findObject(class uint, label string) (pkcs11.ObjectHandle, error) {
+	tmpl := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := t.ctx.FindObjectsInit(t.session, tmpl); err != nil {
+		return 0, common.NewBasicError("Unable to search PKCS#11 objects", err, "label", label)
+	}
+	objs, _, err := t.ctx.FindObjects(t.session, 1)
+	if errFinal := t.ctx.FindObjectsFinal(t.session); err == nil {
+		err = errFinal
+	}
+	if err != nil {
+		return 0, common.NewBasicError("Unable to search PKCS#11 objects", err, "label", label)
+	}
+	if len(objs) == 0 {
+		return 0, common.NewBasicError("PKCS#11 object not found", nil,
+			"label", label, "class", class)
+	}
+	return objs[0], nil
+}
+
+// parseECPoint extracts the public key of the given size from the
+// CKA_EC_POINT attribute, which is a DER encoded octet string.
+func parseECPoint(point []byte, size int) (common.RawBytes, error) {
+	if len(point) == size+2 && point[0] == 0x04 && int(point[1]) == size {
+		return common.RawBytes(point[2:]), nil
+	}
+	if len(point) == size {
+		return common.RawBytes(point), nil
+	}
+	return nil, common.NewBasicError(scrypto.InvalidPubKeySize, nil,
+		"expected", size, "actual", len(point))
+}
+
+// pubKeySize returns the size of the public key of the signing algorithm.
+// ECDSA public keys are uncompressed curve points.
+func pubKeySize(algo string) int {
+	switch algo {
+	case scrypto.ECDSAP256:
+		return 1 + 2*32
+	case scrypto.ECDSAP384:
+		return 1 + 2*48
+	}
+	return ed25519.PublicKeySize
+}
+
+// ecdsaDigest hashes the input for the CKM_ECDSA mechanism, which signs a
+// precomputed digest. The hash functions are the ones scrypto uses.
+func ecdsaDigest(input common.RawBytes, algo string) []byte {
+	if algo == scrypto.ECDSAP384 {
+		h := sha512.Sum384(input)
+		return h[:]
+	}
+	h := sha256.Sum256(input)
+	return h[:]
+}
+
+var _ KeySigner = (*PKCS11)(nil)
+
+// PKCS11 is a KeySigner for a private key that is stored on a PKCS#11 token.
+type PKCS11 struct {
+	token *Token
+	key   pkcs11.ObjectHandle
+	pub   common.RawBytes
+	algo  string
+}
+
+func (s *PKCS11) Sign(input common.RawBytes) (common.RawBytes, error) {
+	s.token.mtx.Lock()
+	defer s.token.mtx.Unlock()
+	// The CKM_ECDSA signature is r || s, which is the encoding scrypto uses.
+	mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(ckmEdDSA, nil)}
+	if s.algo != scrypto.Ed25519 {
+		mech = []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}
+		input = ecdsaDigest(input, s.algo)
+	}
+	if err := s.token.ctx.SignInit(s.token.session, mech, s.key); err != nil {
+		return nil, common.NewBasicError("Unable to initialize PKCS#11 signing", err)
+	}
+	sig, err := s.token.ctx.Sign(s.token.session, input)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to sign with PKCS#11 token", err)
+	}
+	return common.RawBytes(sig), nil
+}
+
+func (s *PKCS11) PublicKey() common.RawBytes {
+	return s.pub
+}
+
+func (s *PKCS11) Algo() string {
+	return s.algo
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keysigner
+
+const keysSample = `
+# The backend that holds the AS signing key and the issuer signing key
+# (file|encrypted_file|pkcs11). The file backend reads the unencrypted key
+# files from the keys directory. The encrypted_file backend reads the key files
+# with the suffix .enc, which are encrypted with the passphrase in
+# PassphraseFile. The pkcs11 backend uses the keys on a PKCS#11 token. The
+# algorithms of the keys are the ones specified in the AS certificate chain.
+# (default file)
+Backend = "file"
+
+# The file containing the passphrase of the encrypted key files. Only used by
+# the encrypted_file backend. (default "")
+PassphraseFile = ""
+`
+
+const pkcs11Sample = `
+# The path of the PKCS#11 module, e.g. "/usr/lib/softhsm/libsofthsm2.so".
+# (default "")
+Module = ""
+
+# The label of the token that holds the keys. (default "")
+TokenLabel = ""
+
+# The file containing the user PIN of the token. (default "")
+PINFile = ""
+
+# The label of the AS signing key. (default as-sig)
+SignKeyLabel = "as-sig"
+
+# The label of the issuer signing key. (default iss-sig)
+IssSigKeyLabel = "iss-sig"
+`
@@ -0,0 +1,132 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package naming
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+)
+
+var _ Resolver = (*HostsFile)(nil)
+
+// HostsFile resolves names from a hosts file. Each line of the file contains
+// a SCION address followed by one or more names, e.g.:
+//  1-ff00:0:110,[10.0.0.1]  myhost myhost.example.org
+// Text after a '#' is a comment. Names are case-insensitive. If a name appears
+// on multiple lines, its addresses are returned in file order.
+type HostsFile struct {
+	hosts map[string][]Address
+}
+
+// LoadHostsFile reads the hosts file at path.
+func LoadHostsFile(path string) (*HostsFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to open hosts file", err, "path", path)
+	}
+	defer file.Close()
+	hosts, err := ParseHosts(file)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to parse hosts file", err, "path", path)
+	}
+	return hosts, nil
+}
+
+// ParseHosts parses a hosts file from r.
+func ParseHosts(r io.Reader) (*HostsFile, error) {
+	hosts := &HostsFile{hosts: make(map[string][]Address)}
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, common.NewBasicError("Missing host name", nil, "line", lineNo)
+		}
+		address, err := parseAddress(fields[0])
+		if err != nil {
+			return nil, common.NewBasicError("Invalid address", err, "line", lineNo)
+		}
+		for _, name := range fields[1:] {
+			if !ValidName(name) {
+				return nil, common.NewBasicError("Invalid host name", nil,
+					"line", lineNo, "name", name)
+			}
+			key := strings.ToLower(name)
+			hosts.hosts[key] = append(hosts.hosts[key], address)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return hosts, nil
+}
+
+func (h *HostsFile) Resolve(_ context.Context, name string) ([]Address, error) {
+	addrs, ok := h.hosts[strings.ToLower(name)]
+	if !ok {
+		return nil, common.NewBasicError(ErrNotFound, nil, "name", name)
+	}
+	return append([]Address(nil), addrs...), nil
+}
+
+// parseAddress parses an address of the form isd-as,[host] or isd-as,host.
+// The host is an IP address or a service address.
+func parseAddress(s string) (Address, error) {
+	parts := strings.SplitN(s, ",", 2)
+	if len(parts) != 2 {
+		return Address{}, common.NewBasicError("Missing host", nil, "addr", s)
+	}
+	ia, err := addr.IAFromString(parts[0])
+	if err != nil {
+		return Address{}, err
+	}
+	rawHost := strings.TrimSuffix(strings.TrimPrefix(parts[1], "["), "]")
+	var host addr.HostAddr
+	if svc := addr.HostSVCFromString(rawHost); svc != addr.SvcNone {
+		host = svc
+	} else if host = addr.HostFromIPStr(rawHost); host == nil {
+		return Address{}, common.NewBasicError("Invalid host", nil, "host", rawHost)
+	}
+	return Address{IA: ia, Host: host}, nil
+}
+
+// ValidName returns true if name can be used as a host name. Host names
+// consist of letters, digits, '-', '_' and '.'.
+func ValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package naming
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+const testHosts = `
+# Test hosts
+1-ff00:0:110,[10.0.0.1]  myhost MyHost.example.org
+1-ff00:0:111,10.0.0.2    other   # trailing comment
+1-ff00:0:112,[2001:db8::1] myhost
+1-ff00:0:110,[CS]        cs
+`
+
+func TestParseHosts(t *testing.T) {
+	Convey("Given a hosts file", t, func() {
+		hosts, err := ParseHosts(strings.NewReader(testHosts))
+		So(err, ShouldBeNil)
+		Convey("names resolve to all their addresses in file order", func() {
+			addrs, err := hosts.Resolve(context.Background(), "myhost")
+			So(err, ShouldBeNil)
+			SoMsg("addrs", addrs, ShouldResemble, []Address{
+				{IA: xtest.MustParseIA("1-ff00:0:110"), Host: addr.HostFromIPStr("10.0.0.1")},
+				{IA: xtest.MustParseIA("1-ff00:0:112"), Host: addr.HostFromIPStr("2001:db8::1")},
+			})
+		})
+		Convey("names are case-insensitive", func() {
+			addrs, err := hosts.Resolve(context.Background(), "myhost.EXAMPLE.org")
+			So(err, ShouldBeNil)
+			SoMsg("ia", addrs[0].IA, ShouldResemble, xtest.MustParseIA("1-ff00:0:110"))
+		})
+		Convey("brackets around the host are optional", func() {
+			addrs, err := hosts.Resolve(context.Background(), "other")
+			So(err, ShouldBeNil)
+			SoMsg("host", addrs[0].Host, ShouldResemble, addr.HostFromIPStr("10.0.0.2"))
+		})
+		Convey("service addresses are supported", func() {
+			addrs, err := hosts.Resolve(context.Background(), "cs")
+			So(err, ShouldBeNil)
+			SoMsg("host", addrs[0].Host, ShouldEqual, addr.SvcCS)
+		})
+		Convey("unknown names are not found", func() {
+			_, err := hosts.Resolve(context.Background(), "unknown")
+			SoMsg("err", common.GetErrorMsg(err), ShouldEqual, ErrNotFound)
+		})
+	})
+	Convey("Invalid hosts files are rejected", t, func() {
+		invalid := []string{
+			"1-ff00:0:110,[10.0.0.1]",
+			"1-ff00:0:110 myhost",
+			"1-ff00:0:110,[not-an-ip] myhost",
+			"1-ff00:0:110,[10.0.0.1] my,host",
+		}
+		for _, hosts := range invalid {
+			_, err := ParseHosts(strings.NewReader(hosts))
+			SoMsg(hosts, err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestChain(t *testing.T) {
+	Convey("Given a chain of hosts files", t, func() {
+		first, err := ParseHosts(strings.NewReader("1-ff00:0:110,[10.0.0.1] a"))
+		So(err, ShouldBeNil)
+		second, err := ParseHosts(strings.NewReader("1-ff00:0:111,[10.0.0.2] a b"))
+		So(err, ShouldBeNil)
+		chain := Chain{first, second}
+		Convey("the first resolver that knows a name answers", func() {
+			addrs, err := chain.Resolve(context.Background(), "a")
+			So(err, ShouldBeNil)
+			SoMsg("ia", addrs[0].IA, ShouldResemble, xtest.MustParseIA("1-ff00:0:110"))
+			addrs, err = chain.Resolve(context.Background(), "b")
+			So(err, ShouldBeNil)
+			SoMsg("ia", addrs[0].IA, ShouldResemble, xtest.MustParseIA("1-ff00:0:111"))
+		})
+		Convey("names unknown to all resolvers are not found", func() {
+			_, err := chain.Resolve(context.Background(), "c")
+			SoMsg("err", common.GetErrorMsg(err), ShouldEqual, ErrNotFound)
+		})
+	})
+}
@@ -0,0 +1,128 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package naming resolves host names to SCION addresses.
+//
+// Names are resolved by a Resolver. The package provides a resolver backed by
+// a hosts file (see HostsFile), and a resolver that queries multiple resolvers
+// in turn (see Chain). Other naming services can be plugged in by implementing
+// the Resolver interface and installing it with SetDefault.
+//
+// The default resolver reads the hosts file at DefaultHostsFile, or at the
+// path in the SCION_HOSTS environment variable if it is set.
+package naming
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+)
+
+const (
+	// DefaultHostsFile is the path of the hosts file read by the default
+	// resolver.
+	DefaultHostsFile = "/etc/scion/hosts"
+	// HostsFileEnv is the environment variable that overrides the path of the
+	// hosts file read by the default resolver.
+	HostsFileEnv = "SCION_HOSTS"
+)
+
+const (
+	// ErrNotFound indicates that a name is unknown to the resolver.
+	ErrNotFound = "Host name not found"
+)
+
+// Address is the SCION address of a host.
+type Address struct {
+	IA   addr.IA
+	Host addr.HostAddr
+}
+
+func (a Address) String() string {
+	return fmt.Sprintf("%s,[%s]", a.IA, a.Host)
+}
+
+// Resolver maps host names to SCION addresses.
+type Resolver interface {
+	// Resolve returns the addresses of name, in order of preference. If the
+	// name is unknown, an error with message ErrNotFound is returned.
+	Resolve(ctx context.Context, name string) ([]Address, error)
+}
+
+// Chain is a resolver that queries its resolvers in turn, and returns the
+// addresses from the first one that knows the name.
+type Chain []Resolver
+
+func (c Chain) Resolve(ctx context.Context, name string) ([]Address, error) {
+	for _, r := range c {
+		addrs, err := r.Resolve(ctx, name)
+		if err == nil {
+			return addrs, nil
+		}
+		if common.GetErrorMsg(err) != ErrNotFound {
+			return nil, err
+		}
+	}
+	return nil, common.NewBasicError(ErrNotFound, nil, "name", name)
+}
+
+var (
+	defaultMtx      sync.Mutex
+	defaultResolver Resolver
+)
+
+// Default returns the default resolver. Unless another resolver was installed
+// with SetDefault, it is a hosts file resolver. If the hosts file does not
+// exist, all names are unknown.
+func Default() (Resolver, error) {
+	defaultMtx.Lock()
+	defer defaultMtx.Unlock()
+	if defaultResolver != nil {
+		return defaultResolver, nil
+	}
+	path := DefaultHostsFile
+	if envPath, ok := os.LookupEnv(HostsFileEnv); ok {
+		path = envPath
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		defaultResolver = &HostsFile{}
+		return defaultResolver, nil
+	}
+	hosts, err := LoadHostsFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defaultResolver = hosts
+	return defaultResolver, nil
+}
+
+// SetDefault installs r as the default resolver.
+func SetDefault(r Resolver) {
+	defaultMtx.Lock()
+	defer defaultMtx.Unlock()
+	defaultResolver = r
+}
+
+// Resolve resolves name with the default resolver.
+func Resolve(ctx context.Context, name string) ([]Address, error) {
+	r, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return r.Resolve(ctx, name)
+}
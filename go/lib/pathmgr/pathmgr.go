@@ -27,12 +27,17 @@
 // paths, the resolver will atomically change the value within the SyncPaths
 // object. The data can be accessed by calling Load again.
 //
+// If SCIOND supports path subscriptions, watches do not poll. Instead, SCIOND
+// pushes new paths whenever they change. If SCIOND does not answer a
+// subscription request, the resolver falls back to polling for all watches.
+//
 // An example of how this package can be used can be found in the associated
 // infra test file.
 package pathmgr
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/scionproto/scion/go/lib/addr"
@@ -120,6 +125,12 @@ type resolver struct {
 	timers       Timers
 	logger       log.Logger
 	watchFactory *WatchFactory
+
+	// subMtx protects noSubscriptions
+	subMtx sync.Mutex
+	// noSubscriptions is set once SCIOND failed to answer a subscription
+	// request, after which watches always poll.
+	noSubscriptions bool
 }
 
 // New creates a new path management context.
@@ -168,21 +179,21 @@ func (r *resolver) QueryFilter(ctx context.Context, src, dst addr.IA,
 func (r *resolver) WatchFilter(ctx context.Context, src, dst addr.IA,
 	filter *pathpol.Policy) (*SyncPaths, error) {
 
-	aps := r.Query(ctx, src, dst, sciond.PathReqFlags{})
-	if filter != nil {
-		aps = filter.Act(aps).(spathmeta.AppPathSet)
-	}
-	sp := NewSyncPaths()
-	sp.update(aps)
-
 	query := &queryConfig{
 		querier: Querier(r),
 		src:     src,
 		dst:     dst,
 		filter:  filter,
 	}
+	sp := NewSyncPaths()
+	sub := r.subscribe(ctx, src, dst)
+	if sub != nil {
+		sp.update(query.apply(appPathSet(<-sub.Updates())))
+	} else {
+		sp.update(query.Do(ctx, sciond.PathReqFlags{}))
+	}
 	pp := NewPollingPolicy(filter != nil, r.timers)
-	w := r.watchFactory.New(sp, query, pp)
+	w := r.watchFactory.New(sp, query, pp, sub)
 	sp.setDestructor(w.Destroy)
 
 	go func() {
@@ -192,6 +203,33 @@ func (r *resolver) WatchFilter(ctx context.Context, src, dst addr.IA,
 	return sp, nil
 }
 
+// subscribe subscribes to the paths from src to dst. It returns nil if the
+// subscription failed, in which case the caller must poll for paths.
+func (r *resolver) subscribe(ctx context.Context, src, dst addr.IA) *sciond.PathSubscription {
+	r.subMtx.Lock()
+	noSubscriptions := r.noSubscriptions
+	r.subMtx.Unlock()
+	if noSubscriptions {
+		return nil
+	}
+	subCtx, cancelF := context.WithTimeout(ctx, DefaultQueryTimeout)
+	defer cancelF()
+	sub, err := r.sciondConn.SubscribePaths(subCtx, dst, src, numReqPaths)
+	if err != nil {
+		r.logger.Info("Path subscription failed, polling for paths",
+			"src", src, "dst", dst, "err", err)
+		if subCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+			// SCIOND did not answer, it most likely does not support
+			// subscriptions.
+			r.subMtx.Lock()
+			r.noSubscriptions = true
+			r.subMtx.Unlock()
+		}
+		return nil
+	}
+	return sub
+}
+
 func (r *resolver) Watch(ctx context.Context, src, dst addr.IA) (*SyncPaths, error) {
 	return r.WatchFilter(ctx, src, dst, nil)
 }
@@ -248,6 +286,15 @@ func (r *resolver) Sciond() sciond.Connector {
 	return r.sciondConn
 }
 
+// appPathSet converts a path set pushed by SCIOND. A closed subscription or a
+// reply with an error code yields an empty set.
+func appPathSet(reply *sciond.PathReply) spathmeta.AppPathSet {
+	if reply == nil || reply.ErrorCode != sciond.ErrorOk {
+		return make(spathmeta.AppPathSet)
+	}
+	return spathmeta.NewAppPathSet(reply)
+}
+
 func dropRevoked(aps spathmeta.AppPathSet, pi sciond.PathInterface) spathmeta.AppPathSet {
 	other := make(spathmeta.AppPathSet)
 	for key, path := range aps {
@@ -170,6 +170,7 @@ func TestWatchCount(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		sd := mock_sciond.NewMockConnector(ctrl)
+		expectNoSubscriptions(sd)
 		pr := New(sd, Timers{}, nil)
 		Convey("the count is initially 0", func() {
 			So(pr.WatchCount(), ShouldEqual, 0)
@@ -200,6 +201,7 @@ func TestWatchPolling(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		sd := mock_sciond.NewMockConnector(ctrl)
+		expectNoSubscriptions(sd)
 		gomock.InOrder(
 			sd.EXPECT().Paths(gomock.Any(), dst, src, gomock.Any(), gomock.Any()).Return(
 				buildSDAnswer(), nil,
@@ -225,6 +227,44 @@ func TestWatchPolling(t *testing.T) {
 	})
 }
 
+func TestWatchSubscription(t *testing.T) {
+	src := xtest.MustParseIA("1-ff00:0:111")
+	dst := xtest.MustParseIA("1-ff00:0:110")
+	Convey("Given a path manager and a SCIOND that supports subscriptions", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		sd := mock_sciond.NewMockConnector(ctrl)
+		sub, push, end := sciond.NewMockPathSubscription()
+		sd.EXPECT().SubscribePaths(gomock.Any(), dst, src, gomock.Any()).Return(sub, nil)
+		push(buildSDAnswer())
+		pr := New(sd, Timers{NormalRefire: getDuration(1), ErrorRefire: getDuration(1)}, nil)
+		sp, err := pr.Watch(context.Background(), src, dst)
+		xtest.FailOnErr(t, err)
+		defer sp.Destroy()
+		Convey("the watch starts with the subscribed paths", func() {
+			So(len(sp.Load().APS), ShouldEqual, 0)
+		})
+		Convey("paths pushed by SCIOND are applied without polling", func() {
+			push(buildSDAnswer(
+				"1-ff00:0:111#105 1-ff00:0:130#1002 1-ff00:0:130#1004 1-ff00:0:110#2",
+			))
+			time.Sleep(getDuration(4))
+			So(len(sp.Load().APS), ShouldEqual, 1)
+		})
+		Convey("the watch polls for paths once the subscription ends", func() {
+			sd.EXPECT().Paths(gomock.Any(), dst, src, gomock.Any(), gomock.Any()).Return(
+				buildSDAnswer(
+					"1-ff00:0:111#105 1-ff00:0:130#1002 1-ff00:0:130#1004 1-ff00:0:110#2",
+					"1-ff00:0:111#104 1-ff00:0:120#5 1-ff00:0:120#6 1-ff00:0:110#1",
+				), nil,
+			).MinTimes(1)
+			end()
+			time.Sleep(getDuration(4))
+			So(len(sp.Load().APS), ShouldEqual, 2)
+		})
+	})
+}
+
 func TestWatchFilter(t *testing.T) {
 	src := xtest.MustParseIA("1-ff00:0:111")
 	dst := xtest.MustParseIA("1-ff00:0:110")
@@ -232,6 +272,7 @@ func TestWatchFilter(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		sd := mock_sciond.NewMockConnector(ctrl)
+		expectNoSubscriptions(sd)
 		gomock.InOrder(
 			sd.EXPECT().Paths(gomock.Any(), dst, src, gomock.Any(), gomock.Any()).Return(
 				buildSDAnswer(
@@ -272,6 +313,7 @@ func TestRevokeFastRecovery(t *testing.T) {
 		defer ctrl.Finish()
 
 		sd := mock_sciond.NewMockConnector(ctrl)
+		expectNoSubscriptions(sd)
 		// First SCIOND query populates the watch
 		sd.EXPECT().Paths(gomock.Any(), dst, src, gomock.Any(), gomock.Any()).Return(
 			buildSDAnswer(
@@ -309,6 +351,7 @@ func TestRevoke(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		sd := mock_sciond.NewMockConnector(ctrl)
+		expectNoSubscriptions(sd)
 		pr := New(sd, Timers{}, nil)
 		Convey("and a watch that retrieves one path", func() {
 			sd.EXPECT().Paths(gomock.Any(), dst, src, gomock.Any(), gomock.Any()).Return(
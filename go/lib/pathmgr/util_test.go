@@ -15,11 +15,23 @@
 package pathmgr
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/golang/mock/gomock"
+
 	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/sciond/mock_sciond"
 )
 
+// expectNoSubscriptions makes sd reject path subscriptions, such that watches
+// poll for paths.
+func expectNoSubscriptions(sd *mock_sciond.MockConnector) {
+	sd.EXPECT().SubscribePaths(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		nil, fmt.Errorf("subscriptions not supported"),
+	).AnyTimes()
+}
+
 func buildSDAnswer(pathStrings ...string) *sciond.PathReply {
 	reply := &sciond.PathReply{
 		ErrorCode: sciond.ErrorOk,
@@ -39,12 +39,15 @@ func NewWatchFactory(timers Timers) *WatchFactory {
 	}
 }
 
-func (factory *WatchFactory) New(sp *SyncPaths, bq *queryConfig, pp PollingPolicy) *WatchReference {
+func (factory *WatchFactory) New(sp *SyncPaths, bq *queryConfig, pp PollingPolicy,
+	sub *sciond.PathSubscription) *WatchReference {
+
 	ref := &WatchReference{parent: factory}
 	factory.instances[ref] = &WatchRunner{
 		sp:      sp,
 		querier: bq,
 		pp:      pp,
+		sub:     sub,
 		closeC:  make(chan struct{}),
 	}
 	return ref
@@ -107,7 +110,9 @@ func (ref *WatchReference) Destroy() {
 }
 
 // WatchRunner polls SCIOND in accordance to a polling policy, updating a
-// concurrency-safe store of paths after every poll.
+// concurrency-safe store of paths after every poll. If the runner has a path
+// subscription, it applies the paths pushed by SCIOND instead, and only starts
+// polling once the subscription ends.
 //
 // Call Stop to shut down the running goroutine. It is safe to call Stop
 // multiple times from different goroutines.
@@ -115,10 +120,14 @@ type WatchRunner struct {
 	pp      PollingPolicy
 	sp      *SyncPaths
 	querier *queryConfig
+	sub     *sciond.PathSubscription
 	closeC  chan struct{}
 }
 
 func (w *WatchRunner) Run() {
+	if w.sub != nil && w.runSubscription() {
+		return
+	}
 	for {
 		w.pp.UpdateState(w.sp.Load().APS)
 		select {
@@ -133,6 +142,25 @@ func (w *WatchRunner) Run() {
 	}
 }
 
+// runSubscription updates the paths whenever SCIOND pushes a new set, until
+// the watch is stopped or the subscription ends. It returns true if the watch
+// was stopped.
+func (w *WatchRunner) runSubscription() bool {
+	defer w.sub.Close(context.Background())
+	for {
+		select {
+		case <-w.closeC:
+			w.pp.Destroy()
+			return true
+		case reply, ok := <-w.sub.Updates():
+			if !ok {
+				return false
+			}
+			w.sp.update(w.querier.apply(appPathSet(reply)))
+		}
+	}
+}
+
 func (w *WatchRunner) Stop() {
 	select {
 	case <-w.closeC:
@@ -151,7 +179,11 @@ type queryConfig struct {
 }
 
 func (bq *queryConfig) Do(ctx context.Context, flags sciond.PathReqFlags) spathmeta.AppPathSet {
-	aps := bq.querier.Query(ctx, bq.src, bq.dst, flags)
+	return bq.apply(bq.querier.Query(ctx, bq.src, bq.dst, flags))
+}
+
+// apply removes the paths rejected by the filter from aps.
+func (bq *queryConfig) apply(aps spathmeta.AppPathSet) spathmeta.AppPathSet {
 	if bq.filter != nil {
 		aps = bq.filter.Act(aps).(spathmeta.AppPathSet)
 	}
@@ -18,24 +18,31 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/infra/modules/db"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/pathdb"
+	pgpathdb "github.com/scionproto/scion/go/lib/pathdb/postgres"
 	sqlitepathdb "github.com/scionproto/scion/go/lib/pathdb/sqlite"
+	"github.com/scionproto/scion/go/lib/periodic"
 	"github.com/scionproto/scion/go/lib/revcache"
 	"github.com/scionproto/scion/go/lib/revcache/memrevcache"
+	"github.com/scionproto/scion/go/lib/revcache/pgrevcache"
+	"github.com/scionproto/scion/go/lib/revcache/sqliterevcache"
 	"github.com/scionproto/scion/go/lib/util"
 )
 
 type Backend string
 
 const (
-	BackendNone   Backend = ""
-	BackendSqlite Backend = "sqlite"
-	BackendMem    Backend = "mem"
+	BackendNone     Backend = ""
+	BackendSqlite   Backend = "sqlite"
+	BackendMem      Backend = "mem"
+	BackendPostgres Backend = "postgres"
 )
 
 const (
@@ -45,6 +52,21 @@ const (
 	MaxIdleConnsKey = "maxidleconns"
 )
 
+const (
+	// ExportFileKey is the key of the file the revocations are exported to.
+	ExportFileKey = "exportfile"
+	// ImportFilesKey is the key of the comma separated list of files the
+	// revocations are imported from.
+	ImportFilesKey = "importfiles"
+	// ShareIntervalKey is the key of the interval at which revocations are
+	// exported and imported.
+	ShareIntervalKey = "shareinterval"
+)
+
+// DefaultShareInterval is the default interval at which revocations are
+// exported and imported.
+const DefaultShareInterval = 10 * time.Second
+
 var _ config.Config = (*PathDBConf)(nil)
 
 // PathDBConf is the configuration for the connection to the path database.
@@ -86,7 +108,7 @@ func (cfg *PathDBConf) Validate() error {
 		return err
 	}
 	switch cfg.Backend() {
-	case BackendSqlite:
+	case BackendSqlite, BackendPostgres:
 		return nil
 	case BackendNone:
 		return common.NewBasicError("No backend set", nil)
@@ -147,13 +169,48 @@ func (cfg *RevCacheConf) MaxIdleConns() (int, bool) {
 	return val, ok
 }
 
+// ExportFile returns the file the valid revocations are periodically exported
+// to. If empty, revocations are not exported.
+func (cfg *RevCacheConf) ExportFile() string {
+	return (*cfg)[ExportFileKey]
+}
+
+// ImportFiles returns the files, exported by other instances of the same
+// service, the revocations are periodically imported from.
+func (cfg *RevCacheConf) ImportFiles() []string {
+	var files []string
+	for _, file := range strings.Split((*cfg)[ImportFilesKey], ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// ShareInterval returns the interval at which revocations are exported and
+// imported.
+func (cfg *RevCacheConf) ShareInterval() time.Duration {
+	val, ok := (*cfg)[ShareIntervalKey]
+	if !ok || val == "" {
+		return DefaultShareInterval
+	}
+	dur, _ := util.ParseDuration(val)
+	return dur
+}
+
 // Validate validates the configuration, should be called after InitDefaults.
 func (cfg *RevCacheConf) Validate() error {
 	if err := validateLimits(*cfg); err != nil {
 		return err
 	}
+	if val := (*cfg)[ShareIntervalKey]; val != "" {
+		dur, err := util.ParseDuration(val)
+		if err != nil || dur <= 0 {
+			return common.NewBasicError("Invalid ShareInterval", err, "value", val)
+		}
+	}
 	switch cfg.Backend() {
-	case BackendSqlite, BackendMem:
+	case BackendSqlite, BackendMem, BackendPostgres:
 		return nil
 	case BackendNone:
 		return common.NewBasicError("No backend set", nil)
@@ -208,6 +265,12 @@ func NewPathStorage(pdbConf PathDBConf,
 	if sameBackend(pdbConf, rcConf) {
 		return newCombinedBackend(pdbConf, rcConf)
 	}
+	return newSeparateBackends(pdbConf, rcConf)
+}
+
+func newSeparateBackends(pdbConf PathDBConf,
+	rcConf RevCacheConf) (pathdb.PathDB, revcache.RevCache, error) {
+
 	if err := pdbConf.validate(); err != nil {
 		return nil, nil, common.NewBasicError("Invalid pathdb config", err)
 	}
@@ -232,6 +295,20 @@ func sameBackend(pdbConf PathDBConf, rcConf RevCacheConf) bool {
 func newCombinedBackend(pdbConf PathDBConf,
 	rcConf RevCacheConf) (pathdb.PathDB, revcache.RevCache, error) {
 
+	switch pdbConf.Backend() {
+	case BackendPostgres:
+		// The path DB and the revocation cache use separate tables, thus they can
+		// share the same database.
+		return newSeparateBackends(pdbConf, rcConf)
+	case BackendSqlite:
+		// The schema version is stored per SQLite file, thus the path DB and
+		// the revocation cache need separate files.
+		if pdbConf.Connection() == rcConf.Connection() {
+			return nil, nil, common.NewBasicError("PathDB and RevCache must not share "+
+				"the same sqlite file", nil, "connection", pdbConf.Connection())
+		}
+		return newSeparateBackends(pdbConf, rcConf)
+	}
 	panic("Combined backend not supported")
 }
 
@@ -243,6 +320,8 @@ func newPathDB(conf PathDBConf) (pathdb.PathDB, error) {
 	switch conf.Backend() {
 	case BackendSqlite:
 		pdb, err = sqlitepathdb.New(conf.Connection())
+	case BackendPostgres:
+		pdb, err = pgpathdb.New(conf.Connection())
 	case BackendNone:
 		return nil, nil
 	default:
@@ -258,14 +337,47 @@ func newPathDB(conf PathDBConf) (pathdb.PathDB, error) {
 
 func newRevCache(conf RevCacheConf) (revcache.RevCache, error) {
 	log.Info("Connecting RevCache", "backend", conf.Backend(), "connection", conf.Connection())
+	var err error
+	var rc revcache.RevCache
+
 	switch conf.Backend() {
 	case BackendMem:
 		return memrevcache.New(), nil
+	case BackendSqlite:
+		rc, err = sqliterevcache.New(conf.Connection())
+	case BackendPostgres:
+		rc, err = pgrevcache.New(conf.Connection())
 	case BackendNone:
 		return nil, nil
 	default:
 		return nil, common.NewBasicError("Unsupported backend", nil, "backend", conf.Backend())
 	}
+
+	if err != nil {
+		return nil, err
+	}
+	setConnLimits(&conf, rc)
+	return rc, nil
+}
+
+// StartRevCacheSharing starts the periodic export of the valid revocations in
+// rc and the periodic import of the revocations exported by other instances,
+// as configured in conf. The caller is responsible for stopping the returned
+// runners.
+func StartRevCacheSharing(conf RevCacheConf, rc revcache.RevCache) []*periodic.Runner {
+	var runners []*periodic.Runner
+	interval := conf.ShareInterval()
+	if file := conf.ExportFile(); file != "" {
+		log.Info("Exporting revocations", "file", file, "interval", interval)
+		runners = append(runners, periodic.StartPeriodicTask(revcache.NewExporter(rc, file),
+			periodic.NewTicker(interval), interval))
+	}
+	if files := conf.ImportFiles(); len(files) > 0 {
+		log.Info("Importing revocations", "files", files, "interval", interval)
+		runners = append(runners, periodic.StartPeriodicTask(revcache.NewImporter(rc, files),
+			periodic.NewTicker(interval), interval))
+	}
+	return runners
 }
 
 type limitConfig interface {
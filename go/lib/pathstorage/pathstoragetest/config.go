@@ -53,6 +53,10 @@ func CheckTestRevCacheConf(cfg *pathstorage.RevCacheConf) {
 	SoMsg("MaxOpenConns", isSet(cfg.MaxOpenConns()), ShouldBeFalse)
 	SoMsg("MaxIdleConns", isSet(cfg.MaxIdleConns()), ShouldBeFalse)
 	SoMsg("Backend correct", cfg.Backend(), ShouldEqual, pathstorage.BackendMem)
+	SoMsg("ExportFile correct", cfg.ExportFile(), ShouldBeEmpty)
+	SoMsg("ImportFiles correct", cfg.ImportFiles(), ShouldBeEmpty)
+	SoMsg("ShareInterval correct", cfg.ShareInterval(), ShouldEqual,
+		pathstorage.DefaultShareInterval)
 }
 
 func isSet(_ int, set bool) bool {
@@ -0,0 +1,214 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pgrevcache implements the revcache interface with a PostgreSQL backed
+// database, which can be shared by multiple service instances.
+package pgrevcache
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/infra/modules/db"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/revcache"
+)
+
+const (
+	// SchemaName identifies the schema in the database.
+	SchemaName = "revcache"
+	// SchemaVersion is the version of the PostgreSQL schema understood by this backend.
+	// Whenever changes to the schema are made, this version number should be increased
+	// to prevent data corruption between incompatible database schemas.
+	SchemaVersion = 1
+	// Schema is the PostgreSQL database layout. Timestamps are stored in
+	// nanoseconds since the epoch.
+	Schema = `CREATE TABLE Revocations(
+		IsdID INTEGER NOT NULL,
+		AsID BIGINT NOT NULL,
+		IfID BIGINT NOT NULL,
+		IssuingTime BIGINT NOT NULL,
+		Expiration BIGINT NOT NULL,
+		RawSignedRev BYTEA NOT NULL,
+		PRIMARY KEY (IsdID, AsID, IfID)
+	);
+	CREATE INDEX RevExpirationIndex ON Revocations(Expiration);`
+	RevocationsTable = "Revocations"
+)
+
+var _ revcache.RevCache = (*Backend)(nil)
+
+// Backend is a revocation cache backed by PostgreSQL.
+type Backend struct {
+	db *sql.DB
+}
+
+// New returns a new PostgreSQL backed revocation cache connecting to the
+// database described by connection. If the tables do not exist, they are
+// created. If the schema version of the stored tables is different from the
+// one in this file, an error is returned.
+func New(connection string) (*Backend, error) {
+	db, err := db.NewPostgres(connection, SchemaName, Schema, SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &Backend{db: db}, nil
+}
+
+func (b *Backend) Get(ctx context.Context, keys revcache.KeySet) (revcache.Revocations, error) {
+	if len(keys) == 0 {
+		return revcache.Revocations{}, nil
+	}
+	args := []interface{}{time.Now().UnixNano()}
+	subQ := make([]string, 0, len(keys))
+	for k := range keys {
+		args = append(args, k.IA.I, k.IA.A, k.IfId)
+		n := len(args)
+		subQ = append(subQ, fmt.Sprintf("(IsdID=$%d AND AsID=$%d AND IfID=$%d)", n-2, n-1, n))
+	}
+	query := fmt.Sprintf("SELECT RawSignedRev FROM Revocations WHERE Expiration>$1 AND (%s)",
+		strings.Join(subQ, " OR "))
+	rows, err := b.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, db.NewReadError("Failed to lookup revocations", err)
+	}
+	defer rows.Close()
+	revs := make(revcache.Revocations, len(keys))
+	for rows.Next() {
+		rev, info, err := scanRev(rows)
+		if err != nil {
+			return nil, err
+		}
+		revs[*revcache.NewKey(info.IA(), info.IfID)] = rev
+	}
+	if err := rows.Err(); err != nil {
+		return nil, db.NewReadError("Failed to read revocations", err)
+	}
+	return revs, nil
+}
+
+func (b *Backend) GetAll(ctx context.Context) (revcache.ResultChan, error) {
+	query := "SELECT RawSignedRev FROM Revocations WHERE Expiration>$1"
+	rows, err := b.db.QueryContext(ctx, query, time.Now().UnixNano())
+	if err != nil {
+		return nil, db.NewReadError("Failed to lookup revocations", err)
+	}
+	resCh := make(chan revcache.RevOrErr)
+	go func() {
+		defer log.LogPanicAndExit()
+		defer close(resCh)
+		defer rows.Close()
+		for rows.Next() {
+			rev, _, err := scanRev(rows)
+			if !sendResult(ctx, resCh, revcache.RevOrErr{Rev: rev, Err: err}) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			sendResult(ctx, resCh,
+				revcache.RevOrErr{Err: db.NewReadError("Failed to read revocations", err)})
+		}
+	}()
+	return resCh, nil
+}
+
+// sendResult sends res on resCh. It returns false if ctx is done before the
+// result is received, in which case the producer must stop and release the
+// rows.
+func sendResult(ctx context.Context, resCh chan<- revcache.RevOrErr,
+	res revcache.RevOrErr) bool {
+
+	select {
+	case resCh <- res:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func scanRev(rows *sql.Rows) (*path_mgmt.SignedRevInfo, *path_mgmt.RevInfo, error) {
+	var raw common.RawBytes
+	if err := rows.Scan(&raw); err != nil {
+		return nil, nil, db.NewReadError("Failed to scan revocation", err)
+	}
+	rev, err := path_mgmt.NewSignedRevInfoFromRaw(raw)
+	if err != nil {
+		return nil, nil, db.NewDataError("Failed to parse revocation", err)
+	}
+	info, err := rev.RevInfo()
+	if err != nil {
+		return nil, nil, db.NewDataError("Failed to parse revocation info", err)
+	}
+	return rev, info, nil
+}
+
+func (b *Backend) Insert(ctx context.Context, rev *path_mgmt.SignedRevInfo) (bool, error) {
+	newInfo, err := rev.RevInfo()
+	if err != nil {
+		panic(err)
+	}
+	now := time.Now()
+	if !newInfo.Expiration().After(now) {
+		return false, nil
+	}
+	packed, err := rev.Pack()
+	if err != nil {
+		return false, db.NewInputDataError("Failed to pack revocation", err)
+	}
+	// An existing entry is only replaced by a newer revocation or if it is
+	// expired.
+	query := `INSERT INTO Revocations (IsdID, AsID, IfID, IssuingTime, Expiration, RawSignedRev)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (IsdID, AsID, IfID) DO UPDATE SET IssuingTime=EXCLUDED.IssuingTime,
+			Expiration=EXCLUDED.Expiration, RawSignedRev=EXCLUDED.RawSignedRev
+		WHERE Revocations.IssuingTime<EXCLUDED.IssuingTime OR Revocations.Expiration<=$7`
+	ia := newInfo.IA()
+	res, err := b.db.ExecContext(ctx, query, ia.I, ia.A, newInfo.IfID,
+		newInfo.Timestamp().UnixNano(), newInfo.Expiration().UnixNano(), packed, now.UnixNano())
+	if err != nil {
+		return false, db.NewWriteError("Failed to insert revocation", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, db.NewWriteError("Failed to determine affected rows", err)
+	}
+	return n > 0, nil
+}
+
+func (b *Backend) DeleteExpired(ctx context.Context) (int64, error) {
+	cnt, err := db.DeleteInTx(ctx, b.db, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.ExecContext(ctx, "DELETE FROM Revocations WHERE Expiration<=$1",
+			time.Now().UnixNano())
+	})
+	return int64(cnt), err
+}
+
+func (b *Backend) Close() error {
+	return b.db.Close()
+}
+
+func (b *Backend) SetMaxOpenConns(maxOpenConns int) {
+	b.db.SetMaxOpenConns(maxOpenConns)
+}
+
+func (b *Backend) SetMaxIdleConns(maxIdleConns int) {
+	b.db.SetMaxIdleConns(maxIdleConns)
+}
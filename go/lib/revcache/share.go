@@ -0,0 +1,167 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revcache
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/util"
+)
+
+// exportFile is the on-disk format of exported revocations.
+type exportFile struct {
+	// Revocations contains the packed signed revocations.
+	Revocations []common.RawBytes
+}
+
+// Export writes all valid revocations in revCache to file. The file is
+// replaced atomically, such that readers never observe a partial export.
+// Returns the number of exported revocations.
+func Export(ctx context.Context, revCache RevCache, file string) (int, error) {
+	resCh, err := revCache.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var export exportFile
+	for res := range resCh {
+		if res.Err != nil {
+			log.Warn("[revcache] Skipping revocation in export", "err", res.Err)
+			continue
+		}
+		raw, err := res.Rev.Pack()
+		if err != nil {
+			log.Warn("[revcache] Failed to pack revocation for export", "err", err)
+			continue
+		}
+		export.Revocations = append(export.Revocations, raw)
+	}
+	raw, err := json.Marshal(export)
+	if err != nil {
+		return 0, common.NewBasicError("Failed to encode revocations", err)
+	}
+	if err := util.WriteFile(file, raw, 0644); err != nil {
+		return 0, common.NewBasicError("Failed to write revocations", err, "file", file)
+	}
+	return len(export.Revocations), nil
+}
+
+// Import inserts the valid revocations in file, which was written by Export,
+// into revCache. Expired revocations and revocations that are older than the
+// ones in revCache are skipped. The signatures of the revocations are not
+// verified, the file must thus only be written by trusted instances, i.e.,
+// instances that only insert verified revocations into their cache. Returns
+// the number of inserted revocations.
+func Import(ctx context.Context, revCache RevCache, file string) (int, error) {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	var export exportFile
+	if err := json.Unmarshal(raw, &export); err != nil {
+		return 0, common.NewBasicError("Failed to decode revocations", err, "file", file)
+	}
+	inserted := 0
+	for _, rawRev := range export.Revocations {
+		rev, err := path_mgmt.NewSignedRevInfoFromRaw(rawRev)
+		if err != nil {
+			log.Warn("[revcache] Skipping unparsable revocation", "file", file, "err", err)
+			continue
+		}
+		info, err := rev.RevInfo()
+		if err != nil {
+			log.Warn("[revcache] Skipping unparsable revocation info", "file", file,
+				"err", err)
+			continue
+		}
+		if info.Active() != nil {
+			continue
+		}
+		ok, err := revCache.Insert(ctx, rev)
+		if err != nil {
+			return inserted, err
+		}
+		if ok {
+			inserted++
+		}
+	}
+	return inserted, nil
+}
+
+// Exporter is a periodic task that exports the valid revocations of a
+// revocation cache to a file, from where other instances of the same service
+// can import them using the Importer.
+type Exporter struct {
+	revCache RevCache
+	file     string
+}
+
+// NewExporter returns a new exporter that writes the revocations in revCache
+// to file.
+func NewExporter(revCache RevCache, file string) *Exporter {
+	return &Exporter{
+		revCache: revCache,
+		file:     file,
+	}
+}
+
+// Run exports the revocations once.
+func (e *Exporter) Run(ctx context.Context) {
+	cnt, err := Export(ctx, e.revCache, e.file)
+	if err != nil {
+		log.Error("[revcache.Exporter] Failed to export revocations", "file", e.file,
+			"err", err)
+		return
+	}
+	log.Debug("[revcache.Exporter] Exported revocations", "file", e.file, "count", cnt)
+}
+
+// Importer is a periodic task that imports the revocations exported by other
+// instances of the same service into a revocation cache.
+type Importer struct {
+	revCache RevCache
+	files    []string
+}
+
+// NewImporter returns a new importer that inserts the revocations exported to
+// files into revCache.
+func NewImporter(revCache RevCache, files []string) *Importer {
+	return &Importer{
+		revCache: revCache,
+		files:    files,
+	}
+}
+
+// Run imports the revocations of all files once. Missing files are skipped,
+// since the exporting instance might not have started yet.
+func (i *Importer) Run(ctx context.Context) {
+	for _, file := range i.files {
+		cnt, err := Import(ctx, i.revCache, file)
+		switch {
+		case os.IsNotExist(err):
+			log.Debug("[revcache.Importer] Export file does not exist (yet)", "file", file)
+		case err != nil:
+			log.Error("[revcache.Importer] Failed to import revocations", "file", file,
+				"err", err)
+		case cnt > 0:
+			log.Info("[revcache.Importer] Imported revocations", "file", file, "count", cnt)
+		}
+	}
+}
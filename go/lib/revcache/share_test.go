@@ -0,0 +1,78 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revcache_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/revcache"
+	"github.com/scionproto/scion/go/lib/revcache/mock_revcache"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestExportImport(t *testing.T) {
+	sr10, err := path_mgmt.NewSignedRevInfo(defaultRevInfo(ia110, ifid10, now), infra.NullSigner)
+	xtest.FailOnErr(t, err)
+	sr11Expired, err := path_mgmt.NewSignedRevInfo(
+		defaultRevInfo(ia110, ifid11, now.Add(-time.Minute)), infra.NullSigner)
+	xtest.FailOnErr(t, err)
+	Convey("Exported revocations are imported", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		dir, err := ioutil.TempDir("", "revcache-share")
+		xtest.FailOnErr(t, err)
+		defer os.RemoveAll(dir)
+		file := filepath.Join(dir, "revs.json")
+		ctx := context.Background()
+
+		src := mock_revcache.NewMockRevCache(ctrl)
+		resCh := make(chan revcache.RevOrErr, 2)
+		resCh <- revcache.RevOrErr{Rev: sr10}
+		resCh <- revcache.RevOrErr{Rev: sr11Expired}
+		close(resCh)
+		src.EXPECT().GetAll(gomock.Any()).Return(revcache.ResultChan(resCh), nil)
+		cnt, err := revcache.Export(ctx, src, file)
+		SoMsg("export err", err, ShouldBeNil)
+		SoMsg("exported", cnt, ShouldEqual, 2)
+
+		dst := mock_revcache.NewMockRevCache(ctrl)
+		dst.EXPECT().Insert(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, rev *path_mgmt.SignedRevInfo) (bool, error) {
+				SoMsg("imported rev", rev.Blob, ShouldResemble, sr10.Blob)
+				return true, nil
+			},
+		)
+		cnt, err = revcache.Import(ctx, dst, file)
+		SoMsg("import err", err, ShouldBeNil)
+		SoMsg("imported", cnt, ShouldEqual, 1)
+	})
+	Convey("Importing a missing file fails", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		dst := mock_revcache.NewMockRevCache(ctrl)
+		_, err := revcache.Import(context.Background(), dst, "nonexistent/revs.json")
+		SoMsg("err", os.IsNotExist(err), ShouldBeTrue)
+	})
+}
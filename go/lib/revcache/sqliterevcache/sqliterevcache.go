@@ -0,0 +1,216 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqliterevcache implements the revcache interface with a SQLite
+// backed database, such that revocations survive restarts of the service.
+package sqliterevcache
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/infra/modules/db"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/revcache"
+)
+
+const (
+	// SchemaVersion is the version of the SQLite schema understood by this backend.
+	// Whenever changes to the schema are made, this version number should be increased
+	// to prevent data corruption between incompatible database schemas.
+	SchemaVersion = 1
+	// Schema is the SQLite database layout. Timestamps are stored in
+	// nanoseconds since the epoch.
+	Schema = `CREATE TABLE Revocations(
+		IsdID INTEGER NOT NULL,
+		AsID INTEGER NOT NULL,
+		IfID INTEGER NOT NULL,
+		IssuingTime INTEGER NOT NULL,
+		Expiration INTEGER NOT NULL,
+		RawSignedRev BLOB NOT NULL,
+		PRIMARY KEY (IsdID, AsID, IfID)
+	);
+	CREATE INDEX RevExpirationIndex ON Revocations(Expiration);`
+	RevocationsTable = "Revocations"
+)
+
+var _ revcache.RevCache = (*Backend)(nil)
+
+// Backend is a revocation cache backed by SQLite.
+type Backend struct {
+	db *sql.DB
+}
+
+// New returns a new SQLite backed revocation cache opening a database at the
+// given path. If no database exists a new database is created. If the schema
+// version of the stored database is different from the one in this file, an
+// error is returned.
+func New(path string) (*Backend, error) {
+	db, err := db.NewSqlite(path, Schema, SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &Backend{db: db}, nil
+}
+
+func (b *Backend) Get(ctx context.Context, keys revcache.KeySet) (revcache.Revocations, error) {
+	if len(keys) == 0 {
+		return revcache.Revocations{}, nil
+	}
+	args := []interface{}{time.Now().UnixNano()}
+	subQ := make([]string, 0, len(keys))
+	for k := range keys {
+		subQ = append(subQ, "(IsdID=? AND AsID=? AND IfID=?)")
+		args = append(args, k.IA.I, k.IA.A, k.IfId)
+	}
+	query := fmt.Sprintf("SELECT RawSignedRev FROM Revocations WHERE Expiration>? AND (%s)",
+		strings.Join(subQ, " OR "))
+	rows, err := b.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, db.NewReadError("Failed to lookup revocations", err)
+	}
+	defer rows.Close()
+	revs := make(revcache.Revocations, len(keys))
+	for rows.Next() {
+		rev, info, err := scanRev(rows)
+		if err != nil {
+			return nil, err
+		}
+		revs[*revcache.NewKey(info.IA(), info.IfID)] = rev
+	}
+	if err := rows.Err(); err != nil {
+		return nil, db.NewReadError("Failed to read revocations", err)
+	}
+	return revs, nil
+}
+
+func (b *Backend) GetAll(ctx context.Context) (revcache.ResultChan, error) {
+	// Read all rows before returning, the database only allows a single
+	// connection, which would otherwise be blocked until the channel is
+	// drained.
+	query := "SELECT RawSignedRev FROM Revocations WHERE Expiration>?"
+	rows, err := b.db.QueryContext(ctx, query, time.Now().UnixNano())
+	if err != nil {
+		return nil, db.NewReadError("Failed to lookup revocations", err)
+	}
+	defer rows.Close()
+	var res []revcache.RevOrErr
+	for rows.Next() {
+		rev, _, err := scanRev(rows)
+		res = append(res, revcache.RevOrErr{Rev: rev, Err: err})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, db.NewReadError("Failed to read revocations", err)
+	}
+	resCh := make(chan revcache.RevOrErr)
+	go func() {
+		defer log.LogPanicAndExit()
+		defer close(resCh)
+		for _, r := range res {
+			resCh <- r
+		}
+	}()
+	return resCh, nil
+}
+
+func scanRev(rows *sql.Rows) (*path_mgmt.SignedRevInfo, *path_mgmt.RevInfo, error) {
+	var raw common.RawBytes
+	if err := rows.Scan(&raw); err != nil {
+		return nil, nil, db.NewReadError("Failed to scan revocation", err)
+	}
+	rev, err := path_mgmt.NewSignedRevInfoFromRaw(raw)
+	if err != nil {
+		return nil, nil, db.NewDataError("Failed to parse revocation", err)
+	}
+	info, err := rev.RevInfo()
+	if err != nil {
+		return nil, nil, db.NewDataError("Failed to parse revocation info", err)
+	}
+	return rev, info, nil
+}
+
+func (b *Backend) Insert(ctx context.Context, rev *path_mgmt.SignedRevInfo) (bool, error) {
+	newInfo, err := rev.RevInfo()
+	if err != nil {
+		panic(err)
+	}
+	now := time.Now()
+	if !newInfo.Expiration().After(now) {
+		return false, nil
+	}
+	packed, err := rev.Pack()
+	if err != nil {
+		return false, db.NewInputDataError("Failed to pack revocation", err)
+	}
+	var inserted bool
+	err = db.DoInTx(ctx, b.db, func(ctx context.Context, tx *sql.Tx) error {
+		inserted = false
+		ia := newInfo.IA()
+		// An existing entry is only replaced by a newer revocation or if it is
+		// expired.
+		var issuingTime, expiration int64
+		query := `SELECT IssuingTime, Expiration FROM Revocations
+			WHERE IsdID=? AND AsID=? AND IfID=?`
+		err := tx.QueryRowContext(ctx, query, ia.I, ia.A, newInfo.IfID).Scan(
+			&issuingTime, &expiration)
+		switch {
+		case err == sql.ErrNoRows:
+		case err != nil:
+			return db.NewReadError("Failed to lookup existing revocation", err)
+		case issuingTime >= newInfo.Timestamp().UnixNano() && expiration > now.UnixNano():
+			return nil
+		}
+		query = `INSERT OR REPLACE INTO Revocations
+			(IsdID, AsID, IfID, IssuingTime, Expiration, RawSignedRev)
+			VALUES (?, ?, ?, ?, ?, ?)`
+		_, err = tx.ExecContext(ctx, query, ia.I, ia.A, newInfo.IfID,
+			newInfo.Timestamp().UnixNano(), newInfo.Expiration().UnixNano(), packed)
+		if err != nil {
+			return db.NewWriteError("Failed to insert revocation", err)
+		}
+		inserted = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return inserted, nil
+}
+
+func (b *Backend) DeleteExpired(ctx context.Context) (int64, error) {
+	cnt, err := db.DeleteInTx(ctx, b.db, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.ExecContext(ctx, "DELETE FROM Revocations WHERE Expiration<=?",
+			time.Now().UnixNano())
+	})
+	return int64(cnt), err
+}
+
+func (b *Backend) Close() error {
+	return b.db.Close()
+}
+
+func (b *Backend) SetMaxOpenConns(maxOpenConns int) {
+	b.db.SetMaxOpenConns(maxOpenConns)
+}
+
+func (b *Backend) SetMaxIdleConns(maxIdleConns int) {
+	b.db.SetMaxIdleConns(maxIdleConns)
+}
@@ -137,6 +137,13 @@ func (r *Ring) Read(entries EntryList, block bool) (int, bool) {
 	return n, blocked
 }
 
+// Readable returns the number of entries currently available for reading.
+func (r *Ring) Readable() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.readable
+}
+
 // Close closes the ring buffer, and causes all blocked readers/writers to be
 // notified.
 func (r *Ring) Close() {
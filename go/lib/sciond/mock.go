@@ -123,6 +123,22 @@ func (m *MockConn) Paths(ctx context.Context, dst, src addr.IA, max uint16,
 	}, nil
 }
 
+// SubscribePaths is not implemented.
+func (m *MockConn) SubscribePaths(ctx context.Context, dst, src addr.IA,
+	max uint16) (*PathSubscription, error) {
+
+	return nil, common.NewBasicError("Path subscriptions not supported by mock", nil)
+}
+
+// NewMockPathSubscription creates a path subscription that is not backed by
+// SCIOND, for testing subscription consumers. Path sets passed to push are
+// delivered on the subscription, and end closes it as if the connection to
+// SCIOND was lost.
+func NewMockPathSubscription() (sub *PathSubscription, push func(*PathReply), end func()) {
+	sub = newPathSubscription(func(context.Context) error { return nil })
+	return sub, sub.push, func() { sub.terminate() }
+}
+
 // ASInfo is not implemented.
 func (m *MockConn) ASInfo(ctx context.Context, ia addr.IA) (*ASInfoReply, error) {
 	panic("not implemented")
@@ -76,6 +76,28 @@ func (c *reconnector) Paths(ctx context.Context, dst, src addr.IA, max uint16,
 	return conn.Paths(ctx, dst, src, max, f)
 }
 
+// SubscribePaths opens a dedicated connection to SCIOND for the subscription.
+// The connection is closed when the subscription is closed.
+func (c *reconnector) SubscribePaths(ctx context.Context, dst, src addr.IA,
+	max uint16) (*PathSubscription, error) {
+
+	conn, err := c.ctxAwareConnect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := conn.SubscribePaths(ctx, dst, src, max)
+	if err != nil {
+		conn.Close(ctx)
+		return nil, err
+	}
+	unsubscribe := sub.closeF
+	sub.closeF = func(ctx context.Context) error {
+		defer conn.Close(ctx)
+		return unsubscribe(ctx)
+	}
+	return sub, nil
+}
+
 func (c *reconnector) ASInfo(ctx context.Context, ia addr.IA) (*ASInfoReply, error) {
 	conn, err := c.ctxAwareConnect(ctx)
 	if err != nil {
@@ -110,6 +110,16 @@ type Connector interface {
 	// Paths requests from SCIOND a set of end to end paths between src and
 	// dst. max specifies the maximum number of paths returned.
 	Paths(ctx context.Context, dst, src addr.IA, max uint16, f PathReqFlags) (*PathReply, error)
+	// SubscribePaths subscribes to the end to end paths between src and dst.
+	// SCIOND pushes a new set of at most max paths whenever the paths change.
+	// Changes caused by revocations or segments fetched by SCIOND are pushed
+	// right away. Other changes, e.g., new segments at the path servers, are
+	// picked up by a periodic refresh at most 30 seconds later.
+	// SubscribePaths blocks until SCIOND replies with the current set of
+	// paths, which is the first value delivered by the subscription. SCIOND
+	// versions without subscription support never reply, so ctx should have
+	// a deadline.
+	SubscribePaths(ctx context.Context, dst, src addr.IA, max uint16) (*PathSubscription, error)
 	// ASInfo requests from SCIOND information about AS ia.
 	ASInfo(ctx context.Context, ia addr.IA) (*ASInfoReply, error)
 	// IFInfo requests from SCIOND addresses and ports of interfaces.  Slice
@@ -139,6 +149,13 @@ type connector struct {
 	asInfos  *cache.Cache
 	ifInfos  *cache.Cache
 	svcInfos *cache.Cache
+
+	// subscriptions contains the active path subscriptions, indexed by the
+	// message ID of the subscription request.
+	subMtx        sync.Mutex
+	subscriptions map[uint64]*PathSubscription
+	// recvOnce starts the receiver of pushed path updates.
+	recvOnce sync.Once
 }
 
 func connect(socketName string) (*connector, error) {
@@ -156,9 +173,10 @@ func connectTimeout(socketName string, timeout time.Duration) (*connector, error
 			&Adapter{},
 			log.Root(),
 		),
-		asInfos:  cache.New(ASInfoTTL, time.Minute),
-		ifInfos:  cache.New(IFInfoTTL, time.Minute),
-		svcInfos: cache.New(SVCInfoTTL, time.Minute),
+		asInfos:       cache.New(ASInfoTTL, time.Minute),
+		ifInfos:       cache.New(IFInfoTTL, time.Minute),
+		svcInfos:      cache.New(SVCInfoTTL, time.Minute),
+		subscriptions: make(map[uint64]*PathSubscription),
 	}, nil
 }
 
@@ -192,6 +210,104 @@ func (c *connector) Paths(ctx context.Context, dst, src addr.IA, max uint16,
 	return reply.(*Pld).PathReply, nil
 }
 
+func (c *connector) SubscribePaths(ctx context.Context, dst, src addr.IA,
+	max uint16) (*PathSubscription, error) {
+
+	id := c.nextID()
+	sub := newPathSubscription(func(ctx context.Context) error {
+		return c.unsubscribe(ctx, id)
+	})
+	c.subMtx.Lock()
+	c.subscriptions[id] = sub
+	c.subMtx.Unlock()
+	c.recvOnce.Do(func() {
+		go func() {
+			defer log.LogPanicAndExit()
+			c.receiveUpdates()
+		}()
+	})
+	err := c.dispatcher.Notify(
+		ctx,
+		&Pld{
+			Id:    id,
+			Which: proto.SCIONDMsg_Which_pathSubscribeReq,
+			PathSubscribeReq: &PathSubscribeReq{
+				Dst:      dst.IAInt(),
+				Src:      src.IAInt(),
+				MaxPaths: max,
+			},
+		},
+		nil,
+	)
+	if err != nil {
+		c.removeSubscription(id)
+		return nil, common.NewBasicError("[sciond-API] Failed to subscribe to paths", err)
+	}
+	select {
+	case reply, ok := <-sub.updates:
+		if !ok {
+			return nil, common.NewBasicError("[sciond-API] Failed to subscribe to paths", nil,
+				"err", "connection closed")
+		}
+		sub.requeue(reply)
+		return sub, nil
+	case <-ctx.Done():
+		sub.Close(context.Background())
+		return nil, common.NewBasicError("[sciond-API] Failed to subscribe to paths", ctx.Err())
+	}
+}
+
+func (c *connector) unsubscribe(ctx context.Context, id uint64) error {
+	c.removeSubscription(id)
+	err := c.dispatcher.Notify(
+		ctx,
+		&Pld{
+			Id:               id,
+			Which:            proto.SCIONDMsg_Which_pathSubscribeReq,
+			PathSubscribeReq: &PathSubscribeReq{Unsubscribe: true},
+		},
+		nil,
+	)
+	if err != nil {
+		return common.NewBasicError("[sciond-API] Failed to unsubscribe from paths", err)
+	}
+	return nil
+}
+
+func (c *connector) removeSubscription(id uint64) {
+	c.subMtx.Lock()
+	defer c.subMtx.Unlock()
+	delete(c.subscriptions, id)
+}
+
+// receiveUpdates passes the path updates pushed by SCIOND to their
+// subscriptions. Once the connection is closed, all subscriptions are
+// terminated.
+func (c *connector) receiveUpdates() {
+	for {
+		msg, _, err := c.dispatcher.RecvFrom(context.Background())
+		if err != nil {
+			break
+		}
+		pld := msg.(*Pld)
+		if pld.Which != proto.SCIONDMsg_Which_pathUpdate || pld.PathUpdate == nil {
+			continue
+		}
+		c.subMtx.Lock()
+		sub, ok := c.subscriptions[pld.Id]
+		c.subMtx.Unlock()
+		if ok {
+			sub.push(pld.PathUpdate.Reply)
+		}
+	}
+	c.subMtx.Lock()
+	defer c.subMtx.Unlock()
+	for id, sub := range c.subscriptions {
+		sub.terminate()
+		delete(c.subscriptions, id)
+	}
+}
+
 func (c *connector) ASInfo(ctx context.Context, ia addr.IA) (*ASInfoReply, error) {
 	c.Lock()
 	defer c.Unlock()
@@ -0,0 +1,93 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sciond
+
+import (
+	"context"
+	"sync"
+)
+
+// PathSubscription receives the path sets SCIOND pushes for a subscribed
+// (src, dst) pair. It is created by calling SubscribePaths on a Connector.
+type PathSubscription struct {
+	updates chan *PathReply
+	closeF  func(ctx context.Context) error
+
+	mtx    sync.Mutex
+	closed bool
+}
+
+func newPathSubscription(closeF func(ctx context.Context) error) *PathSubscription {
+	return &PathSubscription{
+		updates: make(chan *PathReply, 1),
+		closeF:  closeF,
+	}
+}
+
+// Updates returns the channel on which path sets are delivered, starting with
+// the path set at the time of subscription. If the receiver falls behind, only
+// the most recent path set is kept. The channel is closed when the
+// subscription or the connection to SCIOND is closed.
+func (s *PathSubscription) Updates() <-chan *PathReply {
+	return s.updates
+}
+
+// Close cancels the subscription.
+func (s *PathSubscription) Close(ctx context.Context) error {
+	if !s.terminate() {
+		return nil
+	}
+	return s.closeF(ctx)
+}
+
+// push delivers reply, replacing an undelivered older path set.
+func (s *PathSubscription) push(reply *PathReply) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case <-s.updates:
+	default:
+	}
+	s.updates <- reply
+}
+
+// requeue delivers reply if no newer path set is waiting.
+func (s *PathSubscription) requeue(reply *PathReply) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.updates <- reply:
+	default:
+	}
+}
+
+// terminate closes the updates channel. It returns false if the subscription
+// was already terminated.
+func (s *PathSubscription) terminate() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed {
+		return false
+	}
+	s.closed = true
+	close(s.updates)
+	return true
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sciond
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPathSubscription(t *testing.T) {
+	Convey("Given a path subscription", t, func() {
+		var closeCalls int
+		sub := newPathSubscription(func(_ context.Context) error {
+			closeCalls++
+			return nil
+		})
+		first := &PathReply{ErrorCode: ErrorNoPaths}
+		second := &PathReply{ErrorCode: ErrorOk}
+		Convey("a pushed path set replaces an undelivered one", func() {
+			sub.push(first)
+			sub.push(second)
+			SoMsg("update", <-sub.Updates(), ShouldEqual, second)
+		})
+		Convey("a requeued path set does not replace a newer one", func() {
+			sub.push(second)
+			sub.requeue(first)
+			SoMsg("update", <-sub.Updates(), ShouldEqual, second)
+		})
+		Convey("closing closes the channel once", func() {
+			So(sub.Close(context.Background()), ShouldBeNil)
+			So(sub.Close(context.Background()), ShouldBeNil)
+			_, ok := <-sub.Updates()
+			SoMsg("open", ok, ShouldBeFalse)
+			SoMsg("close calls", closeCalls, ShouldEqual, 1)
+			sub.push(first)
+		})
+		Convey("a terminated subscription is not unsubscribed on close", func() {
+			sub.terminate()
+			So(sub.Close(context.Background()), ShouldBeNil)
+			SoMsg("close calls", closeCalls, ShouldEqual, 0)
+		})
+	})
+}
@@ -74,6 +74,8 @@ type Pld struct {
 	IfInfoReply        *IFInfoReply
 	ServiceInfoRequest *ServiceInfoRequest
 	ServiceInfoReply   *ServiceInfoReply
+	PathSubscribeReq   *PathSubscribeReq
+	PathUpdate         *PathUpdate
 }
 
 func NewPldFromRaw(b common.RawBytes) (*Pld, error) {
@@ -118,6 +120,10 @@ func (p *Pld) union() (interface{}, error) {
 		return p.ServiceInfoRequest, nil
 	case proto.SCIONDMsg_Which_serviceInfoReply:
 		return p.ServiceInfoReply, nil
+	case proto.SCIONDMsg_Which_pathSubscribeReq:
+		return p.PathSubscribeReq, nil
+	case proto.SCIONDMsg_Which_pathUpdate:
+		return p.PathUpdate, nil
 	}
 	return nil, common.NewBasicError("Unsupported SCIOND union type", nil, "type", p.Which)
 }
@@ -147,6 +153,27 @@ type PathReqFlags struct {
 	Refresh bool
 }
 
+// PathSubscribeReq subscribes to the paths between Src and Dst. SCIOND replies
+// with a PathUpdate containing the current paths, and pushes further updates
+// with the same message ID whenever the paths change. Setting Unsubscribe
+// cancels the subscription with the same message ID.
+type PathSubscribeReq struct {
+	Dst         addr.IAInt
+	Src         addr.IAInt
+	MaxPaths    uint16
+	Unsubscribe bool
+}
+
+func (r *PathSubscribeReq) String() string {
+	return fmt.Sprintf("%v -> %v, maxPaths=%d, unsubscribe=%v",
+		r.Src, r.Dst, r.MaxPaths, r.Unsubscribe)
+}
+
+// PathUpdate contains the current paths of a path subscription.
+type PathUpdate struct {
+	Reply *PathReply
+}
+
 type PathReply struct {
 	ErrorCode PathErrorCode
 	Entries   []PathReplyEntry
@@ -15,6 +15,7 @@
 package scrypto
 
 import (
+	"crypto/elliptic"
 	"crypto/rand"
 	"strings"
 
@@ -27,6 +28,8 @@ import (
 // Available asymmetric crypto algorithms. The values must be lower case.
 const (
 	Ed25519                    = "ed25519"
+	ECDSAP256                  = "ecdsa-p256"
+	ECDSAP384                  = "ecdsa-p384"
 	Curve25519xSalsa20Poly1305 = "curve25519xsalsa20poly1305"
 )
 
@@ -37,7 +40,9 @@ const (
 )
 
 const (
+	InvalidPubKey           = "Invalid public key"
 	InvalidPubKeySize       = "Invalid public key size"
+	InvalidPrivKey          = "Invalid private key"
 	InvalidPrivKeySize      = "Invalid private key size"
 	InvalidSignatureSize    = "Invalid signature size"
 	InvalidSignatureFormat  = "Invalid signature format: sig[63]&224 should equal 0"
@@ -66,13 +71,42 @@ func GenKeyPair(algo string) (common.RawBytes, common.RawBytes, error) {
 				"algo", algo)
 		}
 		return common.RawBytes(pubkey), common.RawBytes(privkey), nil
+	case ECDSAP256, ECDSAP384:
+		curve, _, _ := ecdsaParams(strings.ToLower(algo))
+		pubkey, privkey, err := genECDSAKeyPair(curve)
+		if err != nil {
+			return nil, nil, common.NewBasicError(UnableToGenerateKeyPair, err,
+				"algo", algo)
+		}
+		return pubkey, privkey, nil
 	default:
 		return nil, nil, common.NewBasicError(UnsupportedAlgo, nil, "algo", algo)
 	}
 }
 
-// Sign takes a signature input and a signing key to create a signature. Currently only
-// ed25519 is supported.
+// PublicKey derives the public key from the private signing key.
+func PublicKey(signKey common.RawBytes, signAlgo string) (common.RawBytes, error) {
+	switch strings.ToLower(signAlgo) {
+	case Ed25519:
+		if len(signKey) != ed25519.PrivateKeySize {
+			return nil, common.NewBasicError(InvalidPrivKeySize, nil, "expected",
+				ed25519.PrivateKeySize, "actual", len(signKey))
+		}
+		return common.RawBytes(ed25519.PrivateKey(signKey).Public().(ed25519.PublicKey)), nil
+	case ECDSAP256, ECDSAP384:
+		curve, _, _ := ecdsaParams(strings.ToLower(signAlgo))
+		priv, err := parseECDSAPrivKey(curve, signKey)
+		if err != nil {
+			return nil, err
+		}
+		return elliptic.Marshal(curve, priv.X, priv.Y), nil
+	default:
+		return nil, common.NewBasicError(UnsupportedSignAlgo, nil, "algo", signAlgo)
+	}
+}
+
+// Sign takes a signature input and a signing key to create a signature. Currently
+// ed25519, ecdsa-p256 and ecdsa-p384 are supported.
 func Sign(sigInput, signKey common.RawBytes, signAlgo string) (common.RawBytes, error) {
 	switch strings.ToLower(signAlgo) {
 	case Ed25519:
@@ -81,13 +115,15 @@ func Sign(sigInput, signKey common.RawBytes, signAlgo string) (common.RawBytes,
 				ed25519.PrivateKeySize, "actual", len(signKey))
 		}
 		return ed25519.Sign(ed25519.PrivateKey(signKey), sigInput), nil
+	case ECDSAP256, ECDSAP384:
+		return signECDSA(sigInput, signKey, strings.ToLower(signAlgo))
 	default:
 		return nil, common.NewBasicError(UnsupportedSignAlgo, nil, "algo", signAlgo)
 	}
 }
 
 // Verify takes a signature input and a verifying key and returns an error, if the
-// signature does not match. Currently only ed25519 is supported.
+// signature does not match. Currently ed25519, ecdsa-p256 and ecdsa-p384 are supported.
 func Verify(sigInput, sig, verifyKey common.RawBytes, signAlgo string) error {
 	switch strings.ToLower(signAlgo) {
 	case Ed25519:
@@ -106,6 +142,8 @@ func Verify(sigInput, sig, verifyKey common.RawBytes, signAlgo string) error {
 			return common.NewBasicError(VerificationError, nil, "msg", sigInput)
 		}
 		return nil
+	case ECDSAP256, ECDSAP384:
+		return verifyECDSA(sigInput, sig, verifyKey, strings.ToLower(signAlgo))
 	default:
 		return common.NewBasicError(UnsupportedSignAlgo, nil, "algo", signAlgo)
 	}
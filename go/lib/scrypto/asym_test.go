@@ -57,8 +57,44 @@ var (
 		a1186ac0dfc17c98dce87b4da7f011ec48c97271d2c20f9b928fe2270d6fb863d51738b48eeee314a7cc8ab93216
 		4548e526ae90224368517acfeabd6bb3732bc0e9da99832b61ca01b6de56244a9e88d5f9b37973f622a43d14a659
 		9b1f654cb45a74e355a5`)
+
+	// ECDSA test vectors with message "sample"
+	// Taken from RFC 6979, appendix A.2.5 (P-256, SHA-256) and A.2.6 (P-384, SHA-384).
+	ECDSATestMsg            = []byte("sample")
+	ECDSAP256TestPrivateKey = xtest.MustParseHexString(
+		`c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721`)
+	ECDSAP256TestPublicKey = xtest.MustParseHexString(
+		`0460fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb67903fe1008b8bc99a41ae9e9
+		5628bc64f2f1b20c2d7e9f5177a3c294d4462299`)
+	ECDSAP256TestSignature = xtest.MustParseHexString(
+		`efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716f7cb1c942d657c41d436c7a1b6
+		e29f65f3e900dbb9aff4064dc4ab2f843acda8`)
+	ECDSAP384TestPrivateKey = xtest.MustParseHexString(
+		`6b9d3dad2e1b8c1c05b19875b6659f4de23c3b667bf297ba9aa47740787137d896d5724e4c70a825f872c9ea60
+		d2edf5`)
+	ECDSAP384TestPublicKey = xtest.MustParseHexString(
+		`04ec3a4e415b4e19a4568618029f427fa5da9a8bc4ae92e02e06aae5286b300c64def8f0ea9055866064a25451
+		5480bc138015d9b72d7d57244ea8ef9ac0c621896708a59367f9dfb9f54ca84b3f1c9db1288b231c3ae0d4fe73
+		44fd2533264720`)
+	ECDSAP384TestSignature = xtest.MustParseHexString(
+		`94edbb92a5ecb8aad4736e56c691916b3f88140666ce9fa73d64c4ea95ad133c81a648152e44acf96e36dd1e80
+		fabe4699ef4aeb15f178cea1fe40db2603138f130e740a19624526203b6351d0a3a94fa329c145786e679e7b82
+		c71a38628ac8`)
 )
 
+type ecdsaVector struct {
+	algo    string
+	priv    common.RawBytes
+	pub     common.RawBytes
+	sig     common.RawBytes
+	keySize int
+}
+
+var ecdsaVectors = []ecdsaVector{
+	{ECDSAP256, ECDSAP256TestPrivateKey, ECDSAP256TestPublicKey, ECDSAP256TestSignature, 32},
+	{ECDSAP384, ECDSAP384TestPrivateKey, ECDSAP384TestPublicKey, ECDSAP384TestSignature, 48},
+}
+
 func TestGenKeyPairs(t *testing.T) {
 	Convey("GenKeyPairs should return a valid Curve25519xSalsa20Poly1305 key pair", t, func() {
 		rawPubkey, rawPrivkey, err := GenKeyPair(Curve25519xSalsa20Poly1305)
@@ -82,6 +118,18 @@ func TestGenKeyPairs(t *testing.T) {
 		SoMsg("rawPrivkey", rawPrivkey, ShouldNotResemble, newPrivkey)
 	})
 
+	for _, v := range ecdsaVectors {
+		Convey("GenKeyPairs should return a valid key pair for "+v.algo, t, func() {
+			rawPubkey, rawPrivkey, err := GenKeyPair(v.algo)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("rawPubkey", len(rawPubkey), ShouldEqual, 1+2*v.keySize)
+			SoMsg("rawPrivkey", len(rawPrivkey), ShouldEqual, v.keySize)
+			pub, err := PublicKey(rawPrivkey, v.algo)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("pub", pub, ShouldResemble, rawPubkey)
+		})
+	}
+
 	Convey("GenKeyPairs should throw error for unknown algo", t, func() {
 		_, _, err := GenKeyPair("asdf")
 		SoMsg("err", err, ShouldNotBeNil)
@@ -110,6 +158,64 @@ func TestSign(t *testing.T) {
 	})
 }
 
+func TestPublicKey(t *testing.T) {
+	Convey("PublicKey should derive the Ed25519 public key", t, func() {
+		privKey := common.RawBytes(ed25519.NewKeyFromSeed(Ed25519TestPrivateKey))
+		pub, err := PublicKey(privKey, Ed25519)
+		SoMsg("err", err, ShouldBeNil)
+		SoMsg("pub", pub, ShouldResemble, Ed25519TestPublicKey)
+	})
+
+	for _, v := range ecdsaVectors {
+		Convey("PublicKey should derive the "+v.algo+" public key", t, func() {
+			pub, err := PublicKey(v.priv, v.algo)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("pub", pub, ShouldResemble, v.pub)
+		})
+
+		Convey("PublicKey should throw error for invalid "+v.algo+" key", t, func() {
+			_, err := PublicKey(make(common.RawBytes, v.keySize), v.algo)
+			SoMsg("zero", err, ShouldNotBeNil)
+			_, err = PublicKey(v.priv[1:], v.algo)
+			SoMsg("size", err, ShouldNotBeNil)
+		})
+	}
+}
+
+func TestECDSA(t *testing.T) {
+	for _, v := range ecdsaVectors {
+		Convey("Verify should verify the "+v.algo+" test vector", t, func() {
+			err := Verify(ECDSATestMsg, v.sig, v.pub, v.algo)
+			SoMsg("err", err, ShouldBeNil)
+		})
+
+		Convey("Sign should create a verifiable "+v.algo+" signature", t, func() {
+			sig, err := Sign(ECDSATestMsg, v.priv, v.algo)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("sig", len(sig), ShouldEqual, 2*v.keySize)
+			SoMsg("verify", Verify(ECDSATestMsg, sig, v.pub, v.algo), ShouldBeNil)
+		})
+
+		Convey("Verify should throw an error for a mangled "+v.algo+" signature", t, func() {
+			mangled := append(common.RawBytes{}, v.sig...)
+			mangled[0] ^= 0xFF
+			SoMsg("err", Verify(ECDSATestMsg, mangled, v.pub, v.algo), ShouldNotBeNil)
+		})
+
+		Convey("Verify should throw an error for an invalid "+v.algo+" signature length",
+			t, func() {
+				err := Verify(ECDSATestMsg, v.sig[1:], v.pub, v.algo)
+				SoMsg("err", err, ShouldNotBeNil)
+			})
+
+		Convey("Verify should throw an error for an invalid "+v.algo+" public key", t, func() {
+			pub := append(common.RawBytes{}, v.pub...)
+			pub[len(pub)-1] ^= 0xFF
+			SoMsg("err", Verify(ECDSATestMsg, v.sig, pub, v.algo), ShouldNotBeNil)
+		})
+	}
+}
+
 func TestVerify(t *testing.T) {
 	Convey("Verify should verify signature correctly", t, func() {
 		err := Verify(Ed25519TestMsg, Ed25519TestSignature, Ed25519TestPublicKey, Ed25519)
@@ -150,6 +150,32 @@ func (c *Certificate) Sign(signKey common.RawBytes, signAlgo string) error {
 	return nil
 }
 
+// KeySigner creates signatures with a private key that is not necessarily
+// accessible, e.g., because it is stored on a hardware token.
+type KeySigner interface {
+	Sign(input common.RawBytes) (common.RawBytes, error)
+	Algo() string
+}
+
+// SignWith signs the certificate with the key signer. The algorithm of the
+// key signer must match signAlgo.
+func (c *Certificate) SignWith(signer KeySigner, signAlgo string) error {
+	if signer.Algo() != signAlgo {
+		return common.NewBasicError(scrypto.UnsupportedSignAlgo, nil,
+			"expected", signAlgo, "actual", signer.Algo())
+	}
+	sigInput, err := c.sigPack()
+	if err != nil {
+		return err
+	}
+	sig, err := signer.Sign(sigInput)
+	if err != nil {
+		return err
+	}
+	c.Signature = sig
+	return nil
+}
+
 // sigPack creates a sorted json object of all fields, except for the signature field.
 func (c *Certificate) sigPack() (common.RawBytes, error) {
 	if c.Version == scrypto.LatestVer {
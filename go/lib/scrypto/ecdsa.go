@@ -0,0 +1,131 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scrypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"math/big"
+
+	"github.com/scionproto/scion/go/lib/common"
+)
+
+// ECDSA keys and signatures are encoded as follows:
+//  - Private key: the big-endian scalar, left-padded to the curve size.
+//  - Public key: the uncompressed curve point (0x04 || X || Y), as defined in SEC 1.
+//  - Signature: r || s, each left-padded to the curve size (IEEE P1363).
+// The signature input is hashed with SHA-256 for P-256 and SHA-384 for P-384.
+
+// ecdsaParams returns the curve and hash function for the ECDSA algorithm.
+func ecdsaParams(algo string) (elliptic.Curve, func([]byte) []byte, bool) {
+	switch algo {
+	case ECDSAP256:
+		return elliptic.P256(), func(b []byte) []byte {
+			h := sha256.Sum256(b)
+			return h[:]
+		}, true
+	case ECDSAP384:
+		return elliptic.P384(), func(b []byte) []byte {
+			h := sha512.Sum384(b)
+			return h[:]
+		}, true
+	}
+	return nil, nil, false
+}
+
+// ecdsaSize returns the byte size of scalars and coordinates on curve.
+func ecdsaSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
+
+func genECDSAKeyPair(curve elliptic.Curve) (common.RawBytes, common.RawBytes, error) {
+	priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	size := ecdsaSize(curve)
+	return elliptic.Marshal(curve, priv.X, priv.Y), padBytes(priv.D.Bytes(), size), nil
+}
+
+func parseECDSAPrivKey(curve elliptic.Curve, key common.RawBytes) (*ecdsa.PrivateKey, error) {
+	if len(key) != ecdsaSize(curve) {
+		return nil, common.NewBasicError(InvalidPrivKeySize, nil,
+			"expected", ecdsaSize(curve), "actual", len(key))
+	}
+	d := new(big.Int).SetBytes(key)
+	if d.Sign() == 0 || d.Cmp(curve.Params().N) >= 0 {
+		return nil, common.NewBasicError(InvalidPrivKey, nil)
+	}
+	priv := &ecdsa.PrivateKey{D: d}
+	priv.Curve = curve
+	priv.X, priv.Y = curve.ScalarBaseMult(key)
+	return priv, nil
+}
+
+func parseECDSAPubKey(curve elliptic.Curve, key common.RawBytes) (*ecdsa.PublicKey, error) {
+	if len(key) != 1+2*ecdsaSize(curve) {
+		return nil, common.NewBasicError(InvalidPubKeySize, nil,
+			"expected", 1+2*ecdsaSize(curve), "actual", len(key))
+	}
+	x, y := elliptic.Unmarshal(curve, key)
+	if x == nil {
+		return nil, common.NewBasicError(InvalidPubKey, nil)
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func signECDSA(sigInput, signKey common.RawBytes, algo string) (common.RawBytes, error) {
+	curve, hash, _ := ecdsaParams(algo)
+	priv, err := parseECDSAPrivKey(curve, signKey)
+	if err != nil {
+		return nil, err
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, priv, hash(sigInput))
+	if err != nil {
+		return nil, common.NewBasicError("Unable to create signature", err)
+	}
+	size := ecdsaSize(curve)
+	return append(padBytes(r.Bytes(), size), padBytes(s.Bytes(), size)...), nil
+}
+
+func verifyECDSA(sigInput, sig, verifyKey common.RawBytes, algo string) error {
+	curve, hash, _ := ecdsaParams(algo)
+	pub, err := parseECDSAPubKey(curve, verifyKey)
+	if err != nil {
+		return err
+	}
+	size := ecdsaSize(curve)
+	if len(sig) != 2*size {
+		return common.NewBasicError(InvalidSignatureSize, nil,
+			"expected", 2*size, "actual", len(sig))
+	}
+	r := new(big.Int).SetBytes(sig[:size])
+	s := new(big.Int).SetBytes(sig[size:])
+	if !ecdsa.Verify(pub, hash(sigInput), r, s) {
+		return common.NewBasicError(VerificationError, nil, "msg", sigInput)
+	}
+	return nil
+}
+
+// padBytes left-pads b with zeros to size bytes.
+func padBytes(b []byte, size int) common.RawBytes {
+	if len(b) >= size {
+		return b
+	}
+	return append(make(common.RawBytes, size-len(b)), b...)
+}
@@ -46,7 +46,9 @@ const (
 	InvalidISD          = "Invalid TRC ISD"
 	InvalidQuorum       = "Not enough valid signatures"
 	InvalidVersion      = "Invalid TRC version"
+	PayloadMismatch     = "TRC payloads do not match"
 	ReservedVersion     = "Invalid version 0"
+	SignatureConflict   = "Conflicting signature"
 	SignatureMissing    = "Signature missing"
 	UnableSigPack       = "TRC: Unable to create signature input"
 )
@@ -252,6 +254,20 @@ func (t *TRC) IsActive(maxTRC *TRC) error {
 	return nil
 }
 
+// VerifyTime checks that the time ts is between creation and expiration time. This function does
+// not check the validity of the signatures, nor whether the TRC is the active version.
+func (t *TRC) VerifyTime(ts uint32) error {
+	if ts < t.CreationTime {
+		return common.NewBasicError(EarlyUsage, nil,
+			"now", timeToString(ts), "creation", timeToString(t.CreationTime))
+	}
+	if ts > t.ExpirationTime {
+		return common.NewBasicError(Expired, nil,
+			"now", timeToString(ts), "expiration", timeToString(t.ExpirationTime))
+	}
+	return nil
+}
+
 // Sign adds signature to the TRC. The signature is computed over the TRC without the signature map.
 func (t *TRC) Sign(name string, signKey common.RawBytes, signAlgo string) error {
 	sigInput, err := t.sigPack()
@@ -275,6 +291,15 @@ func (t *TRC) Verify(trust *TRC) (*TRCVerResult, error) {
 	return nil, t.verifyXSig(trust)
 }
 
+// VerifyBase checks that a base TRC is signed by a quorum of its own core ASes.
+// A base TRC has no predecessor, thus it can only be verified against itself.
+func (t *TRC) VerifyBase() (*TRCVerResult, error) {
+	if t.Quarantine {
+		return nil, common.NewBasicError(EarlyAnnouncement, nil)
+	}
+	return t.verifySignatures(t)
+}
+
 // verifyUpdate checks the validity of a updated TRC.
 func (t *TRC) verifyUpdate(old *TRC) (*TRCVerResult, error) {
 	if old.ISD != t.ISD {
@@ -297,13 +322,45 @@ func (t *TRC) verifyUpdate(old *TRC) (*TRCVerResult, error) {
 	return t.verifySignatures(old)
 }
 
+// MergeSignatures adds the signatures of other to t. Both TRCs must have the
+// same signature input, i.e., only differ in their signatures. A signature in
+// other that differs from the signature of the same signer in t is an error.
+func (t *TRC) MergeSignatures(other *TRC) error {
+	own, err := t.sigPack()
+	if err != nil {
+		return err
+	}
+	theirs, err := other.sigPack()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(own, theirs) {
+		return common.NewBasicError(PayloadMismatch, nil)
+	}
+	for signer, sig := range other.Signatures {
+		if existing, ok := t.Signatures[signer]; ok && !bytes.Equal(existing, sig) {
+			return common.NewBasicError(SignatureConflict, nil, "signer", signer)
+		}
+	}
+	if t.Signatures == nil {
+		t.Signatures = make(map[string]common.RawBytes)
+	}
+	for signer, sig := range other.Signatures {
+		t.Signatures[signer] = sig
+	}
+	return nil
+}
+
 // verifySignatures checks the signatures of the updated TRC.
 func (t *TRC) verifySignatures(old *TRC) (*TRCVerResult, error) {
 	sigInput, err := t.sigPack()
 	if err != nil {
 		return nil, err
 	}
-	var tvr = &TRCVerResult{}
+	var tvr = &TRCVerResult{
+		Quorum: old.QuorumTRC,
+		Failed: make(map[addr.IA]error),
+	}
 	// Only verify signatures which are from core ASes defined in old TRC
 	for signer, coreAS := range old.CoreASes {
 		sig, ok := t.Signatures[signer.String()]
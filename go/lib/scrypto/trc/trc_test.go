@@ -272,6 +272,15 @@ func Test_TRC_CheckActive(t *testing.T) {
 	})
 }
 
+func Test_TRC_VerifyTime(t *testing.T) {
+	Convey("VerifyTime should check the validity period", t, func() {
+		trc := loadTRC(fnTRC, t)
+		SoMsg("valid", trc.VerifyTime(trc.CreationTime), ShouldBeNil)
+		SoMsg("early", trc.VerifyTime(trc.CreationTime-1), ShouldNotBeNil)
+		SoMsg("expired", trc.VerifyTime(trc.ExpirationTime+1), ShouldNotBeNil)
+	})
+}
+
 func Test_TRC_Compress(t *testing.T) {
 	Convey("TRC is compressed correctly", t, func() {
 		trc := loadTRC(fnTRC, t)
@@ -347,6 +356,129 @@ func Test_TRCFromFile(t *testing.T) {
 	})
 }
 
+func Test_TRC_MergeSignatures(t *testing.T) {
+	Convey("MergeSignatures should combine partial signatures", t, func() {
+		base, keys := newSignedTRC(t)
+		a, b := copyTRC(base, t), copyTRC(base, t)
+		a.Signatures = map[string]common.RawBytes{}
+		b.Signatures = map[string]common.RawBytes{}
+		So(a.Sign(ia110.String(), keys[ia110], scrypto.Ed25519), ShouldBeNil)
+		So(b.Sign(ia120.String(), keys[ia120], scrypto.Ed25519), ShouldBeNil)
+		Convey("Disjoint signatures are merged", func() {
+			SoMsg("err", a.MergeSignatures(b), ShouldBeNil)
+			SoMsg("sigs", a.Signatures, ShouldResemble, base.Signatures)
+		})
+		Convey("Different payload fails", func() {
+			b.Description = "modified"
+			SoMsg("err", a.MergeSignatures(b), ShouldNotBeNil)
+		})
+		Convey("Conflicting signature fails", func() {
+			b.Signatures[ia110.String()] = common.RawBytes("bogus")
+			SoMsg("err", a.MergeSignatures(b), ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_TRC_Verify(t *testing.T) {
+	Convey("Verify should check the quorum of the previous TRC", t, func() {
+		old, keys := newSignedTRC(t)
+		Convey("Base TRC verifies against itself", func() {
+			tvr, err := old.VerifyBase()
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("verified", len(tvr.Verified), ShouldEqual, 2)
+		})
+		upd := copyTRC(old, t)
+		upd.Version = old.Version + 1
+		upd.CreationTime = old.CreationTime + old.GracePeriod
+		upd.Signatures = map[string]common.RawBytes{}
+		So(upd.Sign(ia110.String(), keys[ia110], scrypto.Ed25519), ShouldBeNil)
+		Convey("Quorum reached", func() {
+			old.QuorumTRC = 1
+			tvr, err := upd.Verify(old)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("verified", tvr.Verified, ShouldResemble, []addr.IA{ia110})
+			SoMsg("failed", tvr.Failed, ShouldContainKey, ia120)
+		})
+		Convey("Quorum not reached", func() {
+			tvr, err := upd.Verify(old)
+			SoMsg("err", err, ShouldNotBeNil)
+			SoMsg("quorum", tvr.QuorumOk(), ShouldBeFalse)
+		})
+		Convey("Invalid version", func() {
+			upd.Version = old.Version + 2
+			_, err := upd.Verify(old)
+			SoMsg("err", err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_TRC_ECDSA(t *testing.T) {
+	Convey("TRC signed with ECDSA keys verifies after JSON round trip", t, func() {
+		for _, algo := range []string{scrypto.ECDSAP256, scrypto.ECDSAP384} {
+			trc := loadTRC(fnTRC, t)
+			trc.Signatures = make(map[string]common.RawBytes)
+			trc.QuorumTRC = 1
+			pub, priv, err := scrypto.GenKeyPair(algo)
+			xtest.FailOnErr(t, err)
+			trc.CoreASes = CoreASMap{ia110: &CoreAS{
+				OnlineKey:     pub,
+				OnlineKeyAlg:  algo,
+				OfflineKey:    pub,
+				OfflineKeyAlg: algo,
+			}}
+			So(trc.Sign(ia110.String(), priv, algo), ShouldBeNil)
+			_, err = copyTRC(trc, t).VerifyBase()
+			SoMsg(algo, err, ShouldBeNil)
+		}
+	})
+}
+
+var (
+	ia110 = xtest.MustParseIA("1-ff00:0:110")
+	ia120 = xtest.MustParseIA("1-ff00:0:120")
+)
+
+// newSignedTRC creates a TRC with two freshly generated core ASes that both
+// signed it. The private online keys are returned alongside.
+func newSignedTRC(t *testing.T) (*TRC, map[addr.IA]common.RawBytes) {
+	trc := loadTRC(fnTRC, t)
+	trc.CoreASes = make(CoreASMap)
+	trc.Signatures = make(map[string]common.RawBytes)
+	trc.QuorumTRC = 2
+	keys := make(map[addr.IA]common.RawBytes)
+	for _, ia := range []addr.IA{ia110, ia120} {
+		pub, priv, err := scrypto.GenKeyPair(scrypto.Ed25519)
+		if err != nil {
+			t.Fatalf("Unable to generate key: %v", err)
+		}
+		trc.CoreASes[ia] = &CoreAS{
+			OnlineKey:     pub,
+			OnlineKeyAlg:  scrypto.Ed25519,
+			OfflineKey:    pub,
+			OfflineKeyAlg: scrypto.Ed25519,
+		}
+		keys[ia] = priv
+	}
+	for ia, key := range keys {
+		if err := trc.Sign(ia.String(), key, scrypto.Ed25519); err != nil {
+			t.Fatalf("Unable to sign TRC: %v", err)
+		}
+	}
+	return trc, keys
+}
+
+func copyTRC(trc *TRC, t *testing.T) *TRC {
+	raw, err := trc.JSON(false)
+	if err != nil {
+		t.Fatalf("Unable to encode TRC: %v", err)
+	}
+	c, err := TRCFromRaw(raw, false)
+	if err != nil {
+		t.Fatalf("Unable to decode TRC: %v", err)
+	}
+	return c
+}
+
 func loadTRC(filename string, t *testing.T) *TRC {
 	trc, err := TRCFromFile(filename, false)
 	if err != nil {
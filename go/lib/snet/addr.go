@@ -15,18 +15,25 @@
 package snet
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/naming"
 	"github.com/scionproto/scion/go/lib/overlay"
 	"github.com/scionproto/scion/go/lib/spath"
 )
 
+// resolveTimeout bounds the time spent resolving host names in Set.
+const resolveTimeout = 5 * time.Second
+
 var _ net.Addr = (*Addr)(nil)
 var _ flag.Value = (*Addr)(nil)
 
@@ -156,6 +163,46 @@ func AddrFromString(s string) (*Addr, error) {
 	return &Addr{IA: ia, Host: &addr.AppAddr{L3: l3, L4: l4}}, nil
 }
 
+// ResolveAddr converts s to a SCION address. s is either an address string
+// accepted by AddrFromString, or a host name with an optional port (e.g.,
+// myhost:4000) that is resolved with r. If r is nil, the default resolver of
+// package naming is used. If the name has multiple addresses, the first one is
+// used.
+func ResolveAddr(ctx context.Context, r naming.Resolver, s string) (*Addr, error) {
+	if addrRegexp.MatchString(s) {
+		return AddrFromString(s)
+	}
+	name, port := s, ""
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		name, port = s[:i], s[i+1:]
+	}
+	if !naming.ValidName(name) {
+		return nil, common.NewBasicError("Invalid address", nil, "addr", s)
+	}
+	var l4 addr.L4Info
+	if port != "" {
+		p, err := strconv.ParseUint(port, 10, 16)
+		if err != nil {
+			return nil, common.NewBasicError("Invalid port string", err, "port", port)
+		}
+		l4 = addr.NewL4UDPInfo(uint16(p))
+	}
+	if r == nil {
+		var err error
+		if r, err = naming.Default(); err != nil {
+			return nil, err
+		}
+	}
+	addrs, err := r.Resolve(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, common.NewBasicError(naming.ErrNotFound, nil, "name", name)
+	}
+	return &Addr{IA: addrs[0].IA, Host: &addr.AppAddr{L3: addrs[0].Host, L4: l4}}, nil
+}
+
 func parseAddr(s string) (map[string]string, error) {
 	result := make(map[string]string)
 	match := addrRegexp.FindStringSubmatch(s)
@@ -171,9 +218,12 @@ func parseAddr(s string) (map[string]string, error) {
 	return result, nil
 }
 
-// This method implements flag.Value interface
+// This method implements flag.Value interface. Besides address strings, host
+// names are accepted, see ResolveAddr.
 func (a *Addr) Set(s string) error {
-	other, err := AddrFromString(s)
+	ctx, cancelF := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancelF()
+	other, err := ResolveAddr(ctx, nil, s)
 	if err != nil {
 		return err
 	}
@@ -15,13 +15,16 @@
 package snet
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"strings"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/naming"
 )
 
 func Test_Addr_String(t *testing.T) {
@@ -118,3 +121,54 @@ func Test_AddrFromString(t *testing.T) {
 		}
 	})
 }
+
+func Test_ResolveAddr(t *testing.T) {
+	hosts, err := naming.ParseHosts(strings.NewReader(
+		"1-ff00:0:300,[1.2.3.4] myhost\n2-ff00:0:222,[::1] other\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		address string
+		isError bool
+		ia      string
+		host    string
+		l4      addr.L4Info
+	}{
+		{address: "unknown", isError: true},
+		{address: "myhost:70000", isError: true},
+		{address: "my host", isError: true},
+		{address: "1-ff00:0:300,[abc]:12", isError: true},
+		{address: "myhost",
+			ia:   "1-ff00:0:300",
+			host: "1.2.3.4",
+		},
+		{address: "MyHost:80",
+			ia:   "1-ff00:0:300",
+			host: "1.2.3.4",
+			l4:   addr.NewL4UDPInfo(80)},
+		{address: "other:4000",
+			ia:   "2-ff00:0:222",
+			host: "::1",
+			l4:   addr.NewL4UDPInfo(4000)},
+		{address: "1-ff00:0:301,[1.2.3.4]:5",
+			ia:   "1-ff00:0:301",
+			host: "1.2.3.4",
+			l4:   addr.NewL4UDPInfo(5)},
+	}
+	Convey("Function ResolveAddr", t, func() {
+		for _, test := range tests {
+			Convey(fmt.Sprintf("given address %q", test.address), func() {
+				a, err := ResolveAddr(context.Background(), hosts, test.address)
+				if test.isError {
+					SoMsg("error", err, ShouldNotBeNil)
+				} else {
+					SoMsg("error", err, ShouldBeNil)
+					SoMsg("ia", a.IA.String(), ShouldResemble, test.ia)
+					SoMsg("host", a.Host.L3.String(), ShouldResemble, test.host)
+					SoMsg("port", a.Host.L4, ShouldResemble, test.l4)
+				}
+			})
+		}
+	})
+}
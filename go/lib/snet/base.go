@@ -18,6 +18,7 @@ import (
 	"net"
 
 	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/snet/pathselect"
 )
 
 type scionConnBase struct {
@@ -29,6 +30,13 @@ type scionConnBase struct {
 	// svc address
 	svc addr.HostSVC
 
+	// selector chooses the paths to remote ASes. If nil, pathselect.Default
+	// is used.
+	selector pathselect.Selector
+
+	// failover enables path failover on SCMP path errors, if not nil.
+	failover *FailoverConfig
+
 	// Reference to SCION networking context
 	scionNet *SCIONNetwork
 
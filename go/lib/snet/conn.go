@@ -22,6 +22,7 @@ import (
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/pathmgr"
 	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/snet/pathselect"
 )
 
 const (
@@ -38,12 +39,19 @@ var _ Error = (*OpError)(nil)
 
 type OpError struct {
 	scmp *scmp.Hdr
+	pld  *scmp.Payload
 }
 
 func (e *OpError) SCMP() *scmp.Hdr {
 	return e.scmp
 }
 
+// SCMPPayload returns the SCMP payload, which quotes the headers of the packet
+// that caused the error. It is nil if the payload is not available.
+func (e *OpError) SCMPPayload() *scmp.Payload {
+	return e.pld
+}
+
 func (e *OpError) Error() string {
 	return e.scmp.String()
 }
@@ -60,11 +68,12 @@ type SCIONConn struct {
 }
 
 func newSCIONConn(base *scionConnBase, pr pathmgr.Resolver, conn PacketConn) *SCIONConn {
+	writer := newScionConnWriter(base, pr, conn)
 	return &SCIONConn{
 		conn:            conn,
 		scionConnBase:   *base,
-		scionConnWriter: *newScionConnWriter(base, pr, conn),
-		scionConnReader: *newScionConnReader(base, conn),
+		scionConnWriter: *writer,
+		scionConnReader: *newScionConnReader(base, writer.resolver.pathResolver, conn),
 	}
 }
 
@@ -87,6 +96,29 @@ func DialSCIONWithBindSVC(network string, laddr, raddr, baddr *Addr,
 	return DefNetwork.DialSCIONWithBindSVC(network, laddr, raddr, baddr, svc, 0)
 }
 
+// DialSCIONWithSelector calls DialSCIONWithSelector with infinite timeout on
+// the default networking context.
+func DialSCIONWithSelector(network string, laddr, raddr, baddr *Addr,
+	svc addr.HostSVC, selector pathselect.Selector) (Conn, error) {
+
+	if DefNetwork == nil {
+		return nil, common.NewBasicError("SCION network not initialized", nil)
+	}
+	return DefNetwork.DialSCIONWithSelector(network, laddr, raddr, baddr, svc, selector, 0)
+}
+
+// DialSCIONWithFailover calls DialSCIONWithFailover with infinite timeout on
+// the default networking context.
+func DialSCIONWithFailover(network string, laddr, raddr, baddr *Addr, svc addr.HostSVC,
+	selector pathselect.Selector, failover *FailoverConfig) (Conn, error) {
+
+	if DefNetwork == nil {
+		return nil, common.NewBasicError("SCION network not initialized", nil)
+	}
+	return DefNetwork.DialSCIONWithFailover(network, laddr, raddr, baddr, svc, selector,
+		failover, 0)
+}
+
 // ListenSCION calls ListenSCION with infinite timeout on the default
 // networking context.
 func ListenSCION(network string, laddr *Addr) (Conn, error) {
@@ -105,6 +137,28 @@ func ListenSCIONWithBindSVC(network string, laddr, baddr *Addr, svc addr.HostSVC
 	return DefNetwork.ListenSCIONWithBindSVC(network, laddr, baddr, svc, 0)
 }
 
+// ListenSCIONWithSelector calls ListenSCIONWithSelector with infinite timeout
+// on the default networking context.
+func ListenSCIONWithSelector(network string, laddr, baddr *Addr, svc addr.HostSVC,
+	selector pathselect.Selector) (Conn, error) {
+
+	if DefNetwork == nil {
+		return nil, common.NewBasicError("SCION network not initialized", nil)
+	}
+	return DefNetwork.ListenSCIONWithSelector(network, laddr, baddr, svc, selector, 0)
+}
+
+// ListenSCIONWithFailover calls ListenSCIONWithFailover with infinite timeout
+// on the default networking context.
+func ListenSCIONWithFailover(network string, laddr, baddr *Addr, svc addr.HostSVC,
+	selector pathselect.Selector, failover *FailoverConfig) (Conn, error) {
+
+	if DefNetwork == nil {
+		return nil, common.NewBasicError("SCION network not initialized", nil)
+	}
+	return DefNetwork.ListenSCIONWithFailover(network, laddr, baddr, svc, selector, failover, 0)
+}
+
 func (c *SCIONConn) SetDeadline(t time.Time) error {
 	if err := c.scionConnReader.SetReadDeadline(t); err != nil {
 		return err
@@ -0,0 +1,92 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/snet/internal/pathsource"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+)
+
+// FailoverConfig enables automatic path failover on a connection. If an SCMP
+// path error is received for the path snet chose to a remote AS, the path is
+// invalidated and subsequent writes to that AS use the next available path.
+// Paths supplied by the application are never invalidated.
+type FailoverConfig struct {
+	// OnFailover, if not nil, is called after a path has been invalidated. It
+	// is called from the goroutine reading the SCMP error, and must neither
+	// block nor read from the connection.
+	OnFailover func(FailoverEvent)
+}
+
+// FailoverEvent describes the invalidation of a path to a remote AS.
+type FailoverEvent struct {
+	// Remote is the AS the invalidated path leads to.
+	Remote addr.IA
+	// SCMP is the header of the SCMP error that caused the failover.
+	SCMP *scmp.Hdr
+	// Old is the invalidated path.
+	Old *spathmeta.AppPath
+	// New is the path used by subsequent writes, or nil if no other path is
+	// available.
+	New *spathmeta.AppPath
+}
+
+// failoverTypes are the SCMP path errors that invalidate a path.
+var failoverTypes = map[scmp.Type]bool{
+	scmp.T_P_BadMac:         true,
+	scmp.T_P_ExpiredHopF:    true,
+	scmp.T_P_BadIF:          true,
+	scmp.T_P_RevokedIF:      true,
+	scmp.T_P_NonRoutingHopF: true,
+	scmp.T_P_BadSegment:     true,
+	scmp.T_P_BadInfoField:   true,
+	scmp.T_P_BadHopField:    true,
+}
+
+// pathFailover invalidates the paths of a connection on SCMP path errors.
+type pathFailover struct {
+	localIA addr.IA
+	config  *FailoverConfig
+	paths   pathsource.PathSource
+}
+
+// handle invalidates the path quoted in pld if hdr is an SCMP path error. The
+// destination of the failed packet is taken from the quoted address header.
+func (f *pathFailover) handle(hdr *scmp.Hdr, pld *scmp.Payload) {
+	if hdr.Class != scmp.C_Path || !failoverTypes[hdr.Type] {
+		return
+	}
+	if len(pld.AddrHdr) < addr.IABytes || len(pld.PathHdr) == 0 {
+		log.Debug("Unable to fail over, SCMP error does not quote path", "header", hdr)
+		return
+	}
+	remote := addr.IAFromRaw(pld.AddrHdr)
+	ctx, cancelF := context.WithTimeout(context.Background(), DefaultPathQueryTimeout)
+	defer cancelF()
+	old, next := f.paths.Invalidate(ctx, f.localIA, remote, pld.PathHdr)
+	if old == nil {
+		return
+	}
+	log.Info("Invalidated path after SCMP error", "remote", remote, "scmp", hdr,
+		"path", old.Entry.Path, "available", next != nil)
+	if f.config.OnFailover != nil {
+		f.config.OnFailover(FailoverEvent{Remote: remote, SCMP: hdr, Old: old, New: next})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/snet/internal/pathsource/mock_pathsource"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestPathFailover(t *testing.T) {
+	Convey("Given a path failover with a callback", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		localIA := xtest.MustParseIA("1-ff00:0:110")
+		remoteIA := xtest.MustParseIA("1-ff00:0:112")
+		paths := mock_pathsource.NewMockPathSource(ctrl)
+		var events []FailoverEvent
+		f := &pathFailover{
+			localIA: localIA,
+			config: &FailoverConfig{
+				OnFailover: func(ev FailoverEvent) { events = append(events, ev) },
+			},
+			paths: paths,
+		}
+		addrHdr := make(common.RawBytes, 16)
+		remoteIA.Write(addrHdr)
+		pld := &scmp.Payload{
+			AddrHdr: addrHdr,
+			PathHdr: common.RawBytes{1, 2, 3, 4, 5, 6, 7, 8},
+		}
+		old, next := &spathmeta.AppPath{}, &spathmeta.AppPath{}
+		Convey("A path error invalidates the quoted path", func() {
+			hdr := scmp.NewHdr(scmp.ClassType{Class: scmp.C_Path, Type: scmp.T_P_ExpiredHopF},
+				0)
+			paths.EXPECT().Invalidate(gomock.Any(), localIA, remoteIA, pld.PathHdr).
+				Return(old, next)
+			f.handle(hdr, pld)
+			SoMsg("events", events, ShouldResemble,
+				[]FailoverEvent{{Remote: remoteIA, SCMP: hdr, Old: old, New: next}})
+		})
+		Convey("No callback is run if the quoted path is not in use", func() {
+			hdr := scmp.NewHdr(scmp.ClassType{Class: scmp.C_Path, Type: scmp.T_P_RevokedIF},
+				0)
+			paths.EXPECT().Invalidate(gomock.Any(), localIA, remoteIA, pld.PathHdr).
+				Return(nil, nil)
+			f.handle(hdr, pld)
+			SoMsg("events", events, ShouldBeEmpty)
+		})
+		Convey("Other SCMP errors are ignored", func() {
+			hdr := scmp.NewHdr(scmp.ClassType{Class: scmp.C_Path,
+				Type: scmp.T_P_DeliveryNonLocal}, 0)
+			f.handle(hdr, pld)
+			SoMsg("events", events, ShouldBeEmpty)
+		})
+	})
+}
@@ -8,8 +8,10 @@ import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
 	addr "github.com/scionproto/scion/go/lib/addr"
+	common "github.com/scionproto/scion/go/lib/common"
 	overlay "github.com/scionproto/scion/go/lib/overlay"
 	spath "github.com/scionproto/scion/go/lib/spath"
+	spathmeta "github.com/scionproto/scion/go/lib/spath/spathmeta"
 	reflect "reflect"
 )
 
@@ -51,3 +53,18 @@ func (mr *MockPathSourceMockRecorder) Get(arg0, arg1, arg2 interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPathSource)(nil).Get), arg0, arg1, arg2)
 }
+
+// Invalidate mocks base method
+func (m *MockPathSource) Invalidate(arg0 context.Context, arg1, arg2 addr.IA, arg3 common.RawBytes) (*spathmeta.AppPath, *spathmeta.AppPath) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Invalidate", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*spathmeta.AppPath)
+	ret1, _ := ret[1].(*spathmeta.AppPath)
+	return ret0, ret1
+}
+
+// Invalidate indicates an expected call of Invalidate
+func (mr *MockPathSourceMockRecorder) Invalidate(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockPathSource)(nil).Invalidate), arg0, arg1, arg2, arg3)
+}
@@ -15,14 +15,18 @@
 package pathsource
 
 import (
+	"bytes"
 	"context"
+	"sync"
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/overlay"
 	"github.com/scionproto/scion/go/lib/pathmgr"
 	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/snet/pathselect"
 	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
 )
 
 const (
@@ -35,17 +39,43 @@ const (
 // PathSource is a source of paths and overlay addresses for snet.
 type PathSource interface {
 	Get(ctx context.Context, src, dst addr.IA) (*overlay.OverlayAddr, *spath.Path, error)
+	// Invalidate excludes the currently selected path to dst from selection,
+	// if its forwarding path is equal to raw. It returns the invalidated path
+	// and the path selected in its place. The returned paths are nil if raw
+	// does not belong to the currently selected path; the replacement is nil
+	// if no other path is available.
+	Invalidate(ctx context.Context, src, dst addr.IA,
+		raw common.RawBytes) (*spathmeta.AppPath, *spathmeta.AppPath)
 }
 
 type pathSource struct {
 	resolver pathmgr.Resolver
+	selector pathselect.Selector
+
+	mtx sync.Mutex
+	// current is the currently selected path per destination.
+	current map[addr.IA]*spathmeta.AppPath
+	// excluded contains the forwarding paths of invalidated paths per
+	// destination, indexed by path key. An invalidated path can be selected
+	// again once it is refreshed, i.e., its forwarding path changes.
+	excluded map[addr.IA]map[spathmeta.PathKey]common.RawBytes
 }
 
 // NewPathSource initializes a source of paths and overlay addresses for snet,
 // with information obtained from resolver. Passing in a nil resolver is
-// allowed, but the source will always return an error when invoked.
-func NewPathSource(resolver pathmgr.Resolver) PathSource {
-	return &pathSource{resolver: resolver}
+// allowed, but the source will always return an error when invoked. Paths are
+// chosen by selector; the selected path to a destination is kept as long as it
+// is ranked by the selector. A nil selector means pathselect.Default.
+func NewPathSource(resolver pathmgr.Resolver, selector pathselect.Selector) PathSource {
+	if selector == nil {
+		selector = pathselect.Default
+	}
+	return &pathSource{
+		resolver: resolver,
+		selector: selector,
+		current:  make(map[addr.IA]*spathmeta.AppPath),
+		excluded: make(map[addr.IA]map[spathmeta.PathKey]common.RawBytes),
+	}
 }
 
 func (ps *pathSource) Get(ctx context.Context,
@@ -55,7 +85,7 @@ func (ps *pathSource) Get(ctx context.Context,
 		return nil, nil, common.NewBasicError(ErrNoResolver, nil)
 	}
 	paths := ps.resolver.Query(ctx, src, dst, sciond.PathReqFlags{})
-	sciondPath := paths.GetAppPath("")
+	sciondPath := ps.selectPath(dst, paths)
 	if sciondPath == nil {
 		return nil, nil, common.NewBasicError(ErrNoPath, nil)
 	}
@@ -69,3 +99,74 @@ func (ps *pathSource) Get(ctx context.Context,
 	}
 	return overlayAddr, path, nil
 }
+
+func (ps *pathSource) Invalidate(ctx context.Context, src, dst addr.IA,
+	raw common.RawBytes) (*spathmeta.AppPath, *spathmeta.AppPath) {
+
+	ps.mtx.Lock()
+	old, ok := ps.current[dst]
+	if !ok || !bytes.Equal(old.Entry.Path.FwdPath, raw) {
+		ps.mtx.Unlock()
+		return nil, nil
+	}
+	delete(ps.current, dst)
+	if _, ok := ps.excluded[dst]; !ok {
+		ps.excluded[dst] = make(map[spathmeta.PathKey]common.RawBytes)
+	}
+	ps.excluded[dst][old.Key()] = old.Entry.Path.FwdPath
+	ps.mtx.Unlock()
+	if ps.resolver == nil {
+		return old, nil
+	}
+	paths := ps.resolver.Query(ctx, src, dst, sciond.PathReqFlags{})
+	return old, ps.selectPath(dst, paths)
+}
+
+// selectPath returns the currently selected path to dst if the selector still
+// ranks it. Otherwise, the best ranked path is selected. Invalidated paths are
+// never selected.
+func (ps *pathSource) selectPath(dst addr.IA, paths spathmeta.AppPathSet) *spathmeta.AppPath {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+	ranked := ps.selector.Rank(dst, ps.usable(dst, paths))
+	if len(ranked) == 0 {
+		delete(ps.current, dst)
+		return nil
+	}
+	if current, ok := ps.current[dst]; ok {
+		for _, path := range ranked {
+			if path.Key() == current.Key() {
+				ps.current[dst] = path
+				return path
+			}
+		}
+	}
+	ps.current[dst] = ranked[0]
+	return ranked[0]
+}
+
+// usable returns the paths that have not been invalidated. Invalidations of
+// paths that are gone or have been refreshed are dropped. The caller must hold
+// the lock.
+func (ps *pathSource) usable(dst addr.IA, paths spathmeta.AppPathSet) spathmeta.AppPathSet {
+	excluded, ok := ps.excluded[dst]
+	if !ok {
+		return paths
+	}
+	for key, raw := range excluded {
+		if path, ok := paths[key]; !ok || !bytes.Equal(path.Entry.Path.FwdPath, raw) {
+			delete(excluded, key)
+		}
+	}
+	if len(excluded) == 0 {
+		delete(ps.excluded, dst)
+		return paths
+	}
+	usable := make(spathmeta.AppPathSet, len(paths))
+	for key, path := range paths {
+		if _, ok := excluded[key]; !ok {
+			usable[key] = path
+		}
+	}
+	return usable
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathsource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/pathmgr/mock_pathmgr"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/snet/pathselect"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestSelectPath(t *testing.T) {
+	Convey("Given a path source with the FewestHops selector", t, func() {
+		dst := xtest.MustParseIA("1-ff00:0:112")
+		ps := NewPathSource(nil, pathselect.FewestHops).(*pathSource)
+		paths := spathmeta.AppPathSet{}
+		long := paths.Add(newEntry("1-ff00:0:110#1", "1-ff00:0:111#2",
+			"1-ff00:0:111#3", "1-ff00:0:112#4"))
+		SoMsg("initial", ps.selectPath(dst, paths), ShouldEqual, long)
+		Convey("The selected path is kept when a better path appears", func() {
+			paths.Add(newEntry("1-ff00:0:110#5", "1-ff00:0:112#6"))
+			SoMsg("sticky", ps.selectPath(dst, paths), ShouldEqual, long)
+		})
+		Convey("The best path is selected when the current path disappears", func() {
+			short := newEntry("1-ff00:0:110#5", "1-ff00:0:112#6")
+			next := spathmeta.AppPathSet{}
+			shortPath := next.Add(short)
+			SoMsg("switched", ps.selectPath(dst, next), ShouldEqual, shortPath)
+		})
+		Convey("No path is selected for an empty set", func() {
+			SoMsg("empty", ps.selectPath(dst, spathmeta.AppPathSet{}), ShouldBeNil)
+		})
+	})
+}
+
+func TestInvalidate(t *testing.T) {
+	Convey("Given a path source with two paths to a destination", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		src := xtest.MustParseIA("1-ff00:0:110")
+		dst := xtest.MustParseIA("1-ff00:0:112")
+		resolver := mock_pathmgr.NewMockResolver(ctrl)
+		ps := NewPathSource(resolver, pathselect.FewestHops).(*pathSource)
+		paths := spathmeta.AppPathSet{}
+		short := paths.Add(withFwdPath(newEntry("1-ff00:0:110#5", "1-ff00:0:112#6"), 1))
+		long := paths.Add(withFwdPath(newEntry("1-ff00:0:110#1", "1-ff00:0:111#2",
+			"1-ff00:0:111#3", "1-ff00:0:112#4"), 2))
+		resolver.EXPECT().Query(gomock.Any(), src, dst, gomock.Any()).Return(paths).AnyTimes()
+		SoMsg("initial", ps.selectPath(dst, paths), ShouldEqual, short)
+		Convey("Invalidating the selected path fails over to the next path", func() {
+			old, next := ps.Invalidate(context.Background(), src, dst,
+				short.Entry.Path.FwdPath)
+			SoMsg("old", old, ShouldEqual, short)
+			SoMsg("next", next, ShouldEqual, long)
+			SoMsg("sticky", ps.selectPath(dst, paths), ShouldEqual, long)
+			Convey("The invalidated path is selectable again once refreshed", func() {
+				refreshed := spathmeta.AppPathSet{}
+				shortRefreshed := refreshed.Add(
+					withFwdPath(newEntry("1-ff00:0:110#5", "1-ff00:0:112#6"), 3))
+				SoMsg("refreshed", ps.selectPath(dst, refreshed), ShouldEqual, shortRefreshed)
+			})
+			Convey("No path is selected once all paths are invalidated", func() {
+				old, next := ps.Invalidate(context.Background(), src, dst,
+					long.Entry.Path.FwdPath)
+				SoMsg("old", old, ShouldEqual, long)
+				SoMsg("next", next, ShouldBeNil)
+			})
+		})
+		Convey("Invalidating a path that is not selected does nothing", func() {
+			old, next := ps.Invalidate(context.Background(), src, dst,
+				long.Entry.Path.FwdPath)
+			SoMsg("old", old, ShouldBeNil)
+			SoMsg("next", next, ShouldBeNil)
+			SoMsg("unchanged", ps.selectPath(dst, paths), ShouldEqual, short)
+		})
+	})
+}
+
+func withFwdPath(entry *sciond.PathReplyEntry, b byte) *sciond.PathReplyEntry {
+	entry.Path.FwdPath = common.RawBytes{b, 0, 0, 0, 0, 0, 0, 0}
+	return entry
+}
+
+func newEntry(ifaces ...string) *sciond.PathReplyEntry {
+	entry := &sciond.PathReplyEntry{Path: &sciond.FwdPathMeta{}}
+	for _, s := range ifaces {
+		iface, err := sciond.NewPathInterface(s)
+		if err != nil {
+			panic(err)
+		}
+		entry.Path.Interfaces = append(entry.Path.Interfaces, iface)
+	}
+	return entry
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pathselect contains strategies to choose the path that snet uses to
+// reach a remote AS.
+//
+// A Selector ranks the paths returned by SCIOND. snet connections created
+// with a selector use the best ranked path and keep using it for as long as it
+// is available and accepted by the selector, i.e., the selection is sticky per
+// remote AS.
+package pathselect
+
+import (
+	"sort"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/pathpol"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+)
+
+// Selector ranks the paths to a remote AS.
+type Selector interface {
+	// Rank returns the acceptable paths to dst, ordered by preference. Paths
+	// that are not part of the result are never used. Implementations must
+	// not modify paths.
+	Rank(dst addr.IA, paths spathmeta.AppPathSet) []*spathmeta.AppPath
+}
+
+// Func is an adapter to use ordinary functions as Selector.
+type Func func(dst addr.IA, paths spathmeta.AppPathSet) []*spathmeta.AppPath
+
+// Rank calls f(dst, paths).
+func (f Func) Rank(dst addr.IA, paths spathmeta.AppPathSet) []*spathmeta.AppPath {
+	return f(dst, paths)
+}
+
+var (
+	// Default accepts all paths and ranks them by their key. This keeps the
+	// selection deterministic, but otherwise arbitrary.
+	Default Selector = Func(func(_ addr.IA, paths spathmeta.AppPathSet) []*spathmeta.AppPath {
+		return sorted(paths, func(a, b *spathmeta.AppPath) bool { return false })
+	})
+	// FewestHops prefers paths that traverse the fewest AS interfaces.
+	FewestHops Selector = Func(func(_ addr.IA, paths spathmeta.AppPathSet) []*spathmeta.AppPath {
+		return sorted(paths, fewerHops)
+	})
+	// HighestMTU prefers paths with the largest MTU. Ties are broken by the
+	// number of hops.
+	HighestMTU Selector = Func(func(_ addr.IA, paths spathmeta.AppPathSet) []*spathmeta.AppPath {
+		return sorted(paths, func(a, b *spathmeta.AppPath) bool {
+			if a.Entry.Path.Mtu != b.Entry.Path.Mtu {
+				return a.Entry.Path.Mtu > b.Entry.Path.Mtu
+			}
+			return fewerHops(a, b)
+		})
+	})
+)
+
+// Policy returns a selector that only accepts paths that conform to policy.
+// The accepted paths are ranked by next. If next is nil, Default is used.
+func Policy(policy *pathpol.Policy, next Selector) Selector {
+	if next == nil {
+		next = Default
+	}
+	return Func(func(dst addr.IA, paths spathmeta.AppPathSet) []*spathmeta.AppPath {
+		return next.Rank(dst, policy.Act(paths).(spathmeta.AppPathSet))
+	})
+}
+
+func fewerHops(a, b *spathmeta.AppPath) bool {
+	return len(a.Entry.Path.Interfaces) < len(b.Entry.Path.Interfaces)
+}
+
+// sorted returns the paths ordered by less. Paths that are equal according to
+// less are ordered by their key.
+func sorted(paths spathmeta.AppPathSet,
+	less func(a, b *spathmeta.AppPath) bool) []*spathmeta.AppPath {
+
+	keys := make([]spathmeta.PathKey, 0, len(paths))
+	for key := range paths {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := paths[keys[i]], paths[keys[j]]
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return keys[i] < keys[j]
+	})
+	ranked := make([]*spathmeta.AppPath, 0, len(keys))
+	for _, key := range keys {
+		ranked = append(ranked, paths[key])
+	}
+	return ranked
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathselect
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/pathpol"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+var dst = xtest.MustParseIA("1-ff00:0:112")
+
+func TestSelectors(t *testing.T) {
+	Convey("Given a set of paths", t, func() {
+		paths := spathmeta.AppPathSet{}
+		short := paths.Add(newEntry(1280, "1-ff00:0:110#1", "1-ff00:0:112#2"))
+		long := paths.Add(newEntry(1472, "1-ff00:0:110#3", "1-ff00:0:111#4",
+			"1-ff00:0:111#5", "1-ff00:0:112#6"))
+		Convey("FewestHops prefers the shortest path", func() {
+			ranked := FewestHops.Rank(dst, paths)
+			SoMsg("ranked", ranked, ShouldResemble, []*spathmeta.AppPath{short, long})
+		})
+		Convey("HighestMTU prefers the largest MTU", func() {
+			ranked := HighestMTU.Rank(dst, paths)
+			SoMsg("ranked", ranked, ShouldResemble, []*spathmeta.AppPath{long, short})
+		})
+		Convey("Default ranks all paths deterministically", func() {
+			ranked := Default.Rank(dst, paths)
+			SoMsg("len", len(ranked), ShouldEqual, 2)
+			SoMsg("stable", Default.Rank(dst, paths), ShouldResemble, ranked)
+		})
+		Convey("Policy only accepts conforming paths", func() {
+			deny := &pathpol.ACLEntry{}
+			xtest.FailOnErr(t, deny.LoadFromString("- 1-ff00:0:111#0"))
+			allow := &pathpol.ACLEntry{}
+			xtest.FailOnErr(t, allow.LoadFromString("+ 0-0#0"))
+			acl, err := pathpol.NewACL(deny, allow)
+			xtest.FailOnErr(t, err)
+			policy := pathpol.NewPolicy("no-111", acl, nil, nil)
+			ranked := Policy(policy, HighestMTU).Rank(dst, paths)
+			SoMsg("ranked", ranked, ShouldResemble, []*spathmeta.AppPath{short})
+		})
+	})
+}
+
+func newEntry(mtu uint16, ifaces ...string) *sciond.PathReplyEntry {
+	entry := &sciond.PathReplyEntry{Path: &sciond.FwdPathMeta{Mtu: mtu}}
+	for _, s := range ifaces {
+		iface, err := sciond.NewPathInterface(s)
+		if err != nil {
+			panic(err)
+		}
+		entry.Path.Interfaces = append(entry.Path.Interfaces, iface)
+	}
+	return entry
+}
+
+// Interface assertion
+var _ Selector = Func(func(addr.IA, spathmeta.AppPathSet) []*spathmeta.AppPath { return nil })
@@ -26,22 +26,36 @@ import (
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/overlay"
 	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/snet/internal/pathsource"
 )
 
 type scionConnReader struct {
 	base *scionConnBase
 	conn PacketConn
+	// failover invalidates paths on SCMP path errors. It is nil if failover is
+	// disabled.
+	failover *pathFailover
 
 	mtx    sync.Mutex
 	buffer common.RawBytes
 }
 
-func newScionConnReader(base *scionConnBase, conn PacketConn) *scionConnReader {
-	return &scionConnReader{
+func newScionConnReader(base *scionConnBase, paths pathsource.PathSource,
+	conn PacketConn) *scionConnReader {
+
+	c := &scionConnReader{
 		base:   base,
 		conn:   conn,
 		buffer: make(common.RawBytes, common.MaxMTU),
 	}
+	if base.failover != nil {
+		c.failover = &pathFailover{
+			localIA: base.laddr.IA,
+			config:  base.failover,
+			paths:   paths,
+		}
+	}
+	return c
 }
 
 // ReadFromSCION reads data into b, returning the length of copied data and the
@@ -114,7 +128,8 @@ func (c *scionConnReader) read(b []byte) (int, *Addr, error) {
 		case *scmp.Hdr:
 			l4i = addr.NewL4SCMPInfo()
 			c.handleSCMP(hdr, &pkt)
-			err = &OpError{scmp: hdr}
+			pld, _ := pkt.Payload.(*scmp.Payload)
+			err = &OpError{scmp: hdr, pld: pld}
 		default:
 			err = common.NewBasicError("Unexpected SCION L4 protocol", nil,
 				"expected", "UDP or SCMP", "actual", pkt.L4Header.L4Type())
@@ -128,22 +143,29 @@ func (c *scionConnReader) read(b []byte) (int, *Addr, error) {
 }
 
 func (c *scionConnReader) handleSCMP(hdr *scmp.Hdr, pkt *SCIONPacket) {
-	// Only handle revocations for now
-	if hdr.Class == scmp.C_Path && hdr.Type == scmp.T_P_RevokedIF {
-		c.handleSCMPRev(hdr, pkt)
+	if hdr.Class != scmp.C_Path {
+		return
 	}
-}
-
-func (c *scionConnReader) handleSCMPRev(hdr *scmp.Hdr, pkt *SCIONPacket) {
 	scmpPayload, ok := pkt.Payload.(*scmp.Payload)
 	if !ok {
 		log.Error("Unable to type assert payload to SCMP payload",
 			"type", common.TypeOf(pkt.Payload))
+		return
 	}
+	if hdr.Type == scmp.T_P_RevokedIF {
+		c.handleSCMPRev(hdr, scmpPayload)
+	}
+	if c.failover != nil {
+		c.failover.handle(hdr, scmpPayload)
+	}
+}
+
+func (c *scionConnReader) handleSCMPRev(hdr *scmp.Hdr, scmpPayload *scmp.Payload) {
 	info, ok := scmpPayload.Info.(*scmp.InfoRevocation)
 	if !ok {
 		log.Error("Unable to type assert SCMP Info to SCMP Revocation Info",
 			"type", common.TypeOf(scmpPayload.Info))
+		return
 	}
 	log.Info("Received SCMP revocation", "header", hdr.String(), "payload", scmpPayload.String())
 	if c.base.scionNet.pathResolver != nil {
@@ -44,6 +44,18 @@
 // to shutdown the socket (see https://github.com/scionproto/scion/pull/1356).
 // To prevent this on a Conn object with only Write calls, run a separate
 // goroutine that continuously calls Read on the Conn.
+//
+// If the application does not supply a path, snet chooses one of the paths
+// returned by SCIOND. DialSCIONWithSelector and ListenSCIONWithSelector take a
+// pathselect.Selector that decides which path is used, e.g., the one with the
+// fewest hops, the highest MTU, or only paths conforming to a path policy. The
+// chosen path to a remote AS is kept for as long as it is available.
+//
+// DialSCIONWithFailover and ListenSCIONWithFailover additionally enable
+// automatic path failover: SCMP path errors (e.g., revoked interfaces, bad or
+// expired hop fields) for a path chosen by snet invalidate that path, and
+// subsequent writes to the remote AS use the next available path. Path
+// failover relies on the application reading SCMP errors from the Conn.
 package snet
 
 import (
@@ -56,6 +68,7 @@ import (
 	"github.com/scionproto/scion/go/lib/overlay"
 	"github.com/scionproto/scion/go/lib/pathmgr"
 	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/snet/pathselect"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
 )
 
@@ -179,10 +192,33 @@ func (n *SCIONNetwork) DialSCION(network string, laddr, raddr *Addr,
 func (n *SCIONNetwork) DialSCIONWithBindSVC(network string, laddr, raddr, baddr *Addr,
 	svc addr.HostSVC, timeout time.Duration) (Conn, error) {
 
+	return n.DialSCIONWithSelector(network, laddr, raddr, baddr, svc, nil, timeout)
+}
+
+// DialSCIONWithSelector is similar to DialSCIONWithBindSVC. Paths to raddr
+// are chosen by selector. A nil selector means pathselect.Default.
+//
+// A timeout of 0 means infinite timeout.
+func (n *SCIONNetwork) DialSCIONWithSelector(network string, laddr, raddr, baddr *Addr,
+	svc addr.HostSVC, selector pathselect.Selector, timeout time.Duration) (Conn, error) {
+
+	return n.DialSCIONWithFailover(network, laddr, raddr, baddr, svc, selector, nil, timeout)
+}
+
+// DialSCIONWithFailover is similar to DialSCIONWithSelector. If failover is
+// not nil, SCMP path errors invalidate the path to raddr and subsequent writes
+// use the next path chosen by selector.
+//
+// A timeout of 0 means infinite timeout.
+func (n *SCIONNetwork) DialSCIONWithFailover(network string, laddr, raddr, baddr *Addr,
+	svc addr.HostSVC, selector pathselect.Selector, failover *FailoverConfig,
+	timeout time.Duration) (Conn, error) {
+
 	if raddr == nil {
 		return nil, common.NewBasicError("Unable to dial to nil remote", nil)
 	}
-	conn, err := n.ListenSCIONWithBindSVC(network, laddr, baddr, svc, timeout)
+	conn, err := n.ListenSCIONWithFailover(network, laddr, baddr, svc, selector, failover,
+		timeout)
 	if err != nil {
 		return nil, err
 	}
@@ -212,6 +248,30 @@ func (n *SCIONNetwork) ListenSCION(network string, laddr *Addr,
 func (n *SCIONNetwork) ListenSCIONWithBindSVC(network string, laddr, baddr *Addr,
 	svc addr.HostSVC, timeout time.Duration) (Conn, error) {
 
+	return n.ListenSCIONWithSelector(network, laddr, baddr, svc, nil, timeout)
+}
+
+// ListenSCIONWithSelector is similar to ListenSCIONWithBindSVC. Paths to the
+// destinations of WriteTo and WriteToSCION are chosen by selector. A nil
+// selector means pathselect.Default.
+//
+// A timeout of 0 means infinite timeout.
+func (n *SCIONNetwork) ListenSCIONWithSelector(network string, laddr, baddr *Addr,
+	svc addr.HostSVC, selector pathselect.Selector, timeout time.Duration) (Conn, error) {
+
+	return n.ListenSCIONWithFailover(network, laddr, baddr, svc, selector, nil, timeout)
+}
+
+// ListenSCIONWithFailover is similar to ListenSCIONWithSelector. If failover
+// is not nil, SCMP path errors invalidate the path to the remote AS of the
+// failed packet and subsequent writes to that AS use the next path chosen by
+// selector.
+//
+// A timeout of 0 means infinite timeout.
+func (n *SCIONNetwork) ListenSCIONWithFailover(network string, laddr, baddr *Addr,
+	svc addr.HostSVC, selector pathselect.Selector, failover *FailoverConfig,
+	timeout time.Duration) (Conn, error) {
+
 	// FIXME(scrye): If no local address is specified, we want to
 	// bind to the address of the outbound interface on a random
 	// free port. However, the current dispatcher version cannot
@@ -258,6 +318,8 @@ func (n *SCIONNetwork) ListenSCIONWithBindSVC(network string, laddr, baddr *Addr
 		net:      network,
 		scionNet: n,
 		svc:      svc,
+		selector: selector,
+		failover: failover,
 	}
 	// Initialize local bind address
 	// NOTE: keep nil address logic for now, even though we do not support it yet
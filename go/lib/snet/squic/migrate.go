@@ -0,0 +1,357 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package squic
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/lucas-clemente/quic-go"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/pathmgr"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/pathselect"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+)
+
+const (
+	ErrNoPath     = "no path to remote"
+	ErrInitPath   = "unable to initialize path"
+	ErrBadOverlay = "unable to extract next hop from path"
+)
+
+const (
+	// DefaultLossTimeout is the default duration after which a path on which
+	// packets are sent, but none are received, is considered broken.
+	DefaultLossTimeout = 3 * time.Second
+	// scmpHoldoff is the duration after a migration during which SCMP path
+	// errors are ignored. Packets that were in flight on the broken path
+	// cause SCMP errors that must not break the new path.
+	scmpHoldoff = time.Second
+)
+
+// MigrationConfig configures the path migration of a session.
+type MigrationConfig struct {
+	// Selector ranks the paths to the remote. If nil, pathselect.Default is
+	// used.
+	Selector pathselect.Selector
+	// LossTimeout is the duration after which a path on which packets are
+	// sent, but none are received, is considered broken. If 0,
+	// DefaultLossTimeout is used.
+	LossTimeout time.Duration
+	// OnMigrate, if not nil, is called after the session migrated from path
+	// old to path next. Either can be nil, if no path was available.
+	OnMigrate func(old, next *spathmeta.AppPath)
+}
+
+// Session is a QUIC session that tracks the SCION path to the remote. If the
+// path fails, i.e., SCMP path errors are received or packets are persistently
+// lost, the session migrates to the next path without tearing down the QUIC
+// connection.
+type Session struct {
+	quic.Session
+	conn *pathConn
+}
+
+// DialSCIONWithMigration is similar to DialSCIONWithBindSVC, but the returned
+// session migrates to another path if the path to raddr fails. A nil
+// migConfig means the default configuration.
+func DialSCIONWithMigration(network *snet.SCIONNetwork, laddr, raddr, baddr *snet.Addr,
+	svc addr.HostSVC, quicConfig *quic.Config, migConfig *MigrationConfig) (*Session, error) {
+
+	if network == nil {
+		network = snet.DefNetwork
+	}
+	sconn, err := sListen(network, laddr, baddr, svc)
+	if err != nil {
+		return nil, err
+	}
+	conn := newPathConn(sconn, network.IA(), raddr.IA, network.PathResolver(), migConfig)
+	// Use dummy hostname, as it's used for SNI, and we're not doing cert verification.
+	qsess, err := quic.Dial(conn, raddr, "host:0", cliTlsCfg, quicConfig)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &Session{Session: qsess, conn: conn}, nil
+}
+
+// Path returns the path currently used to reach the remote. It returns nil if
+// the remote is in the local AS, or if no path has been selected yet.
+func (s *Session) Path() *spathmeta.AppPath {
+	return s.conn.path()
+}
+
+// Paths returns the available paths to the remote, best ranked first.
+func (s *Session) Paths() []*spathmeta.AppPath {
+	return s.conn.paths()
+}
+
+// PinPath makes the session use path, which should be one of the paths
+// returned by Paths. A pinned path is never migrated away from. Passing nil
+// unpins the path and re-enables migration.
+func (s *Session) PinPath(path *spathmeta.AppPath) {
+	s.conn.pin(path)
+}
+
+// pathConn is a SCION conn that chooses the path for packets to a single
+// remote AS and switches paths on failure. Packets to other ASes are passed
+// through unchanged. SCMP errors are not returned from ReadFrom, as they
+// would otherwise tear down the QUIC session.
+type pathConn struct {
+	snet.Conn
+	localIA  addr.IA
+	remote   addr.IA
+	resolver pathmgr.Resolver
+	config   MigrationConfig
+
+	mtx     sync.Mutex
+	current *spathmeta.AppPath
+	pinned  bool
+	// failed contains the keys of the paths that failed since the last time
+	// all paths failed.
+	failed map[spathmeta.PathKey]struct{}
+	// migrated is the time of the last migration.
+	migrated time.Time
+	// silentSince is the time of the first write since the last packet was
+	// received from the remote. It is zero if no such write happened.
+	silentSince time.Time
+	// lastWrite is the time of the last write to the remote.
+	lastWrite time.Time
+}
+
+func newPathConn(conn snet.Conn, localIA, remote addr.IA, resolver pathmgr.Resolver,
+	config *MigrationConfig) *pathConn {
+
+	c := &pathConn{
+		Conn:     conn,
+		localIA:  localIA,
+		remote:   remote,
+		resolver: resolver,
+		failed:   make(map[spathmeta.PathKey]struct{}),
+	}
+	if config != nil {
+		c.config = *config
+	}
+	if c.config.Selector == nil {
+		c.config.Selector = pathselect.Default
+	}
+	if c.config.LossTimeout == 0 {
+		c.config.LossTimeout = DefaultLossTimeout
+	}
+	return c
+}
+
+func (c *pathConn) WriteTo(b []byte, address net.Addr) (int, error) {
+	raddr, ok := address.(*snet.Addr)
+	if !ok || !raddr.IA.Equal(c.remote) || c.remote.Equal(c.localIA) {
+		return c.Conn.WriteTo(b, address)
+	}
+	path, err := c.writePath(time.Now())
+	if err != nil {
+		return 0, err
+	}
+	fwdPath := &spath.Path{Raw: path.Entry.Path.FwdPath}
+	if err := fwdPath.InitOffsets(); err != nil {
+		return 0, common.NewBasicError(ErrInitPath, err)
+	}
+	nextHop, err := path.Entry.HostInfo.Overlay()
+	if err != nil {
+		return 0, common.NewBasicError(ErrBadOverlay, err)
+	}
+	return c.Conn.WriteTo(b, &snet.Addr{
+		IA:      raddr.IA,
+		Host:    raddr.Host,
+		Path:    fwdPath,
+		NextHop: nextHop,
+	})
+}
+
+// writePath returns the path for a write at time now. If packets have been
+// sent without response for longer than the loss timeout, the session
+// migrates to the next path.
+func (c *pathConn) writePath(now time.Time) (*spathmeta.AppPath, error) {
+	var old *spathmeta.AppPath
+	var migrated bool
+	c.mtx.Lock()
+	if c.silentSince.IsZero() || now.Sub(c.lastWrite) > c.config.LossTimeout {
+		c.silentSince = now
+	} else if !c.pinned && now.Sub(c.silentSince) > c.config.LossTimeout {
+		log.Info("squic: Persistent packet loss on path", "remote", c.remote,
+			"since", c.silentSince)
+		old, migrated = c.current, true
+		c.migrate(now)
+		c.silentSince = now
+	}
+	c.lastWrite = now
+	path := c.current
+	c.mtx.Unlock()
+	if path == nil {
+		path = c.selectPath()
+	}
+	if migrated {
+		c.notify(old, path)
+	}
+	if path == nil {
+		return nil, common.NewBasicError(ErrNoPath, nil, "remote", c.remote)
+	}
+	return path, nil
+}
+
+func (c *pathConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		n, address, err := c.Conn.ReadFrom(b)
+		if opErr, ok := err.(snet.Error); ok {
+			var pld *scmp.Payload
+			if p, ok := opErr.(scmpPayloader); ok {
+				pld = p.SCMPPayload()
+			}
+			c.handleSCMP(opErr.SCMP(), pld, time.Now())
+			continue
+		}
+		if err == nil {
+			if raddr, ok := address.(*snet.Addr); ok && raddr.IA.Equal(c.remote) {
+				c.mtx.Lock()
+				c.silentSince = time.Time{}
+				c.mtx.Unlock()
+			}
+		}
+		return n, address, err
+	}
+}
+
+// scmpPayloader is implemented by errors that carry the SCMP payload, such as
+// snet.OpError.
+type scmpPayloader interface {
+	SCMPPayload() *scmp.Payload
+}
+
+// handleSCMP migrates to the next path if hdr is an SCMP path error for a
+// packet that was sent to the remote on the current path. The failed packet is
+// identified by the address and path header quoted in pld.
+func (c *pathConn) handleSCMP(hdr *scmp.Hdr, pld *scmp.Payload, now time.Time) {
+	log.Debug("squic: Received SCMP error", "remote", c.remote, "header", hdr)
+	if hdr.Class != scmp.C_Path {
+		return
+	}
+	if pld == nil || len(pld.AddrHdr) < addr.IABytes || len(pld.PathHdr) == 0 {
+		log.Debug("squic: Unable to migrate, SCMP error does not quote path",
+			"remote", c.remote, "header", hdr)
+		return
+	}
+	if !addr.IAFromRaw(pld.AddrHdr).Equal(c.remote) {
+		return
+	}
+	c.mtx.Lock()
+	if c.pinned || c.current == nil || now.Sub(c.migrated) < scmpHoldoff ||
+		!bytes.Equal(c.current.Entry.Path.FwdPath, pld.PathHdr) {
+		c.mtx.Unlock()
+		return
+	}
+	old := c.current
+	c.migrate(now)
+	c.mtx.Unlock()
+	c.notify(old, c.selectPath())
+}
+
+// migrate marks the current path as failed and clears it, such that the next
+// call to selectPath selects another path. The caller must hold the lock.
+func (c *pathConn) migrate(now time.Time) {
+	if c.current != nil {
+		c.failed[c.current.Key()] = struct{}{}
+	}
+	c.current = nil
+	c.migrated = now
+}
+
+// selectPath selects the best ranked path that has not failed, and returns
+// the current path. If all paths failed, the failed paths are forgotten. The
+// paths are queried without holding the lock, as the query might block. If a
+// path was selected concurrently in the meantime, it is kept. The caller must
+// not hold the lock.
+func (c *pathConn) selectPath() *spathmeta.AppPath {
+	paths := c.query()
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.current != nil {
+		return c.current
+	}
+	candidates := make(spathmeta.AppPathSet, len(paths))
+	for key, path := range paths {
+		if _, ok := c.failed[key]; !ok {
+			candidates[key] = path
+		}
+	}
+	if len(candidates) == 0 {
+		c.failed = make(map[spathmeta.PathKey]struct{})
+		candidates = paths
+	}
+	if ranked := c.config.Selector.Rank(c.remote, candidates); len(ranked) > 0 {
+		c.current = ranked[0]
+	}
+	return c.current
+}
+
+func (c *pathConn) query() spathmeta.AppPathSet {
+	if c.resolver == nil {
+		return nil
+	}
+	ctx, cancelF := context.WithTimeout(context.Background(), snet.DefaultPathQueryTimeout)
+	defer cancelF()
+	return c.resolver.Query(ctx, c.localIA, c.remote, sciond.PathReqFlags{})
+}
+
+func (c *pathConn) notify(old, next *spathmeta.AppPath) {
+	log.Info("squic: Migrated path", "remote", c.remote, "old", pathString(old),
+		"new", pathString(next))
+	if c.config.OnMigrate != nil {
+		c.config.OnMigrate(old, next)
+	}
+}
+
+func (c *pathConn) path() *spathmeta.AppPath {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.current
+}
+
+func (c *pathConn) paths() []*spathmeta.AppPath {
+	return c.config.Selector.Rank(c.remote, c.query())
+}
+
+func (c *pathConn) pin(path *spathmeta.AppPath) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if path != nil {
+		c.current = path
+	}
+	c.pinned = path != nil
+}
+
+func pathString(path *spathmeta.AppPath) string {
+	if path == nil {
+		return "<nil>"
+	}
+	return path.Entry.Path.String()
+}
@@ -0,0 +1,244 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package squic
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/pathmgr/mock_pathmgr"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/mock_snet"
+	"github.com/scionproto/scion/go/lib/snet/pathselect"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+var _ snet.Error = (*scmpError)(nil)
+
+type scmpError struct {
+	hdr *scmp.Hdr
+	pld *scmp.Payload
+}
+
+func (e *scmpError) SCMP() *scmp.Hdr {
+	return e.hdr
+}
+
+func (e *scmpError) SCMPPayload() *scmp.Payload {
+	return e.pld
+}
+
+func (e *scmpError) Error() string {
+	return e.hdr.String()
+}
+
+func TestPathConn(t *testing.T) {
+	Convey("Given a path conn with two paths to the remote", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		localIA := xtest.MustParseIA("1-ff00:0:110")
+		raddr, err := snet.AddrFromString("1-ff00:0:112,[127.0.0.1]:80")
+		xtest.FailOnErr(t, err)
+		paths := spathmeta.AppPathSet{}
+		short := paths.Add(newEntry(1, "1-ff00:0:110#5", "1-ff00:0:112#6"))
+		long := paths.Add(newEntry(2, "1-ff00:0:110#1", "1-ff00:0:111#2",
+			"1-ff00:0:111#3", "1-ff00:0:112#4"))
+		resolver := mock_pathmgr.NewMockResolver(ctrl)
+		resolver.EXPECT().Query(gomock.Any(), localIA, raddr.IA, gomock.Any()).
+			Return(paths).AnyTimes()
+		sconn := mock_snet.NewMockConn(ctrl)
+		var migrations [][2]*spathmeta.AppPath
+		conn := newPathConn(sconn, localIA, raddr.IA, resolver, &MigrationConfig{
+			Selector:    pathselect.FewestHops,
+			LossTimeout: 2 * time.Second,
+			OnMigrate: func(old, next *spathmeta.AppPath) {
+				migrations = append(migrations, [2]*spathmeta.AppPath{old, next})
+			},
+		})
+		Convey("Writes use the best ranked path", func() {
+			sconn.EXPECT().WriteTo(gomock.Any(), usesPath(short)).Return(3, nil)
+			_, err := conn.WriteTo([]byte{1, 2, 3}, raddr)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("path", conn.path(), ShouldEqual, short)
+		})
+		Convey("An SCMP path error is not returned and migrates the path", func() {
+			_, err := conn.writePath(time.Now())
+			xtest.FailOnErr(t, err)
+			hdr := scmp.NewHdr(scmp.ClassType{Class: scmp.C_Path, Type: scmp.T_P_RevokedIF}, 0)
+			pld := quote(raddr.IA, short)
+			gomock.InOrder(
+				sconn.EXPECT().ReadFrom(gomock.Any()).Return(0, raddr,
+					&scmpError{hdr: hdr, pld: pld}),
+				sconn.EXPECT().ReadFrom(gomock.Any()).Return(3, raddr, nil),
+			)
+			n, _, err := conn.ReadFrom(make([]byte, 10))
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("n", n, ShouldEqual, 3)
+			SoMsg("path", conn.path(), ShouldEqual, long)
+			SoMsg("migrations", migrations, ShouldResemble,
+				[][2]*spathmeta.AppPath{{short, long}})
+			Convey("Further SCMP path errors right after the migration are ignored", func() {
+				conn.handleSCMP(hdr, quote(raddr.IA, long), time.Now())
+				SoMsg("path", conn.path(), ShouldEqual, long)
+			})
+		})
+		Convey("An SCMP path error only migrates if it quotes the current path", func() {
+			_, err := conn.writePath(time.Now())
+			xtest.FailOnErr(t, err)
+			hdr := scmp.NewHdr(scmp.ClassType{Class: scmp.C_Path, Type: scmp.T_P_RevokedIF}, 0)
+			conn.handleSCMP(hdr, nil, time.Now())
+			SoMsg("no payload", conn.path(), ShouldEqual, short)
+			conn.handleSCMP(hdr, quote(raddr.IA, long), time.Now())
+			SoMsg("other path", conn.path(), ShouldEqual, short)
+			conn.handleSCMP(hdr, quote(localIA, short), time.Now())
+			SoMsg("other destination", conn.path(), ShouldEqual, short)
+			SoMsg("migrations", migrations, ShouldBeEmpty)
+			conn.handleSCMP(hdr, quote(raddr.IA, short), time.Now())
+			SoMsg("current path", conn.path(), ShouldEqual, long)
+		})
+		Convey("A pinned path is not migrated", func() {
+			conn.pin(long)
+			hdr := scmp.NewHdr(scmp.ClassType{Class: scmp.C_Path, Type: scmp.T_P_RevokedIF}, 0)
+			conn.handleSCMP(hdr, quote(raddr.IA, long), time.Now())
+			SoMsg("path", conn.path(), ShouldEqual, long)
+			SoMsg("migrations", migrations, ShouldBeEmpty)
+		})
+		Convey("Persistent loss migrates the path", func() {
+			now := time.Now()
+			for _, d := range []time.Duration{0, time.Second, 2 * time.Second} {
+				path, err := conn.writePath(now.Add(d))
+				SoMsg("err", err, ShouldBeNil)
+				SoMsg("path", path, ShouldEqual, short)
+			}
+			path, err := conn.writePath(now.Add(3 * time.Second))
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("migrated", path, ShouldEqual, long)
+		})
+		Convey("Writes after an idle period do not migrate the path", func() {
+			now := time.Now()
+			conn.writePath(now)
+			path, err := conn.writePath(now.Add(time.Minute))
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("path", path, ShouldEqual, short)
+		})
+		Convey("Paths are ranked by the selector", func() {
+			SoMsg("paths", conn.paths(), ShouldResemble, []*spathmeta.AppPath{short, long})
+		})
+	})
+}
+
+func TestPathConnSlowQuery(t *testing.T) {
+	Convey("Reads are not blocked while a write waits for the path query", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		localIA := xtest.MustParseIA("1-ff00:0:110")
+		raddr, err := snet.AddrFromString("1-ff00:0:112,[127.0.0.1]:80")
+		xtest.FailOnErr(t, err)
+		paths := spathmeta.AppPathSet{}
+		short := paths.Add(newEntry(1, "1-ff00:0:110#5", "1-ff00:0:112#6"))
+		querying := make(chan struct{})
+		unblock := make(chan struct{})
+		resolver := mock_pathmgr.NewMockResolver(ctrl)
+		resolver.EXPECT().Query(gomock.Any(), localIA, raddr.IA, gomock.Any()).DoAndReturn(
+			func(context.Context, addr.IA, addr.IA, sciond.PathReqFlags) spathmeta.AppPathSet {
+				close(querying)
+				<-unblock
+				return paths
+			},
+		)
+		sconn := mock_snet.NewMockConn(ctrl)
+		sconn.EXPECT().ReadFrom(gomock.Any()).Return(3, raddr, nil)
+		conn := newPathConn(sconn, localIA, raddr.IA, resolver, nil)
+
+		written := make(chan *spathmeta.AppPath, 1)
+		go func() {
+			path, _ := conn.writePath(time.Now())
+			written <- path
+		}()
+		<-querying
+		read := make(chan error, 1)
+		go func() {
+			_, _, err := conn.ReadFrom(make([]byte, 10))
+			read <- err
+		}()
+		var blocked bool
+		select {
+		case err = <-read:
+		case <-time.After(time.Second):
+			blocked = true
+		}
+		close(unblock)
+		SoMsg("read blocked", blocked, ShouldBeFalse)
+		SoMsg("read err", err, ShouldBeNil)
+		SoMsg("path", <-written, ShouldEqual, short)
+		if blocked {
+			<-read
+		}
+	})
+}
+
+// usesPath matches snet addresses with the forwarding path of path.
+func usesPath(path *spathmeta.AppPath) gomock.Matcher {
+	return &pathMatcher{path: path}
+}
+
+type pathMatcher struct {
+	path *spathmeta.AppPath
+}
+
+func (m *pathMatcher) Matches(x interface{}) bool {
+	a, ok := x.(*snet.Addr)
+	return ok && a.Path != nil && bytes.Equal(a.Path.Raw, m.path.Entry.Path.FwdPath)
+}
+
+func (m *pathMatcher) String() string {
+	return fmt.Sprintf("uses path %v", m.path.Entry.Path)
+}
+
+// quote returns the SCMP payload quoting a packet to dst on path.
+func quote(dst addr.IA, path *spathmeta.AppPath) *scmp.Payload {
+	addrHdr := make(common.RawBytes, 16)
+	dst.Write(addrHdr)
+	return &scmp.Payload{AddrHdr: addrHdr, PathHdr: path.Entry.Path.FwdPath}
+}
+
+func newEntry(ts uint32, ifaces ...string) *sciond.PathReplyEntry {
+	raw := make(common.RawBytes, spath.InfoFieldLength+2*spath.HopFieldLength)
+	(&spath.InfoField{TsInt: ts, ISD: 1, Hops: 2}).Write(raw)
+	entry := &sciond.PathReplyEntry{Path: &sciond.FwdPathMeta{FwdPath: raw}}
+	entry.HostInfo.Addrs.Ipv4 = net.IPv4(127, 0, 0, 1).To4()
+	entry.HostInfo.Port = 30041
+	for _, s := range ifaces {
+		iface, err := sciond.NewPathInterface(s)
+		if err != nil {
+			panic(err)
+		}
+		entry.Path.Interfaces = append(entry.Path.Interfaces, iface)
+	}
+	return entry
+}
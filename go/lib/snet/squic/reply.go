@@ -0,0 +1,100 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package squic
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/snet"
+)
+
+// replyTimeout is the duration after which the reply path to a remote that
+// has not sent any packets is forgotten.
+const replyTimeout = 10 * time.Minute
+
+// replyConn is a SCION conn that replies to a remote on the path of the most
+// recent packet received from it. This way, servers follow the path
+// migrations of clients. SCMP errors are not returned from ReadFrom, as they
+// would otherwise tear down the QUIC listener.
+type replyConn struct {
+	snet.Conn
+
+	mtx       sync.Mutex
+	replies   map[string]*reply
+	lastSweep time.Time
+}
+
+type reply struct {
+	addr     *snet.Addr
+	lastSeen time.Time
+}
+
+func newReplyConn(conn snet.Conn) *replyConn {
+	return &replyConn{
+		Conn:      conn,
+		replies:   make(map[string]*reply),
+		lastSweep: time.Now(),
+	}
+}
+
+func (c *replyConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		n, address, err := c.Conn.ReadFrom(b)
+		if opErr, ok := err.(snet.Error); ok {
+			log.Debug("squic: Received SCMP error", "header", opErr.SCMP())
+			continue
+		}
+		if raddr, ok := address.(*snet.Addr); ok && err == nil {
+			c.update(raddr, time.Now())
+		}
+		return n, address, err
+	}
+}
+
+func (c *replyConn) WriteTo(b []byte, address net.Addr) (int, error) {
+	if raddr, ok := address.(*snet.Addr); ok {
+		c.mtx.Lock()
+		if r, ok := c.replies[replyKey(raddr)]; ok {
+			address = r.addr
+		}
+		c.mtx.Unlock()
+	}
+	return c.Conn.WriteTo(b, address)
+}
+
+// update records raddr as the reply address of the remote. Reply addresses of
+// remotes that have been silent for longer than replyTimeout are dropped.
+func (c *replyConn) update(raddr *snet.Addr, now time.Time) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	// The path of raddr references the read buffer, copy it.
+	c.replies[replyKey(raddr)] = &reply{addr: raddr.Copy(), lastSeen: now}
+	if now.Sub(c.lastSweep) < replyTimeout {
+		return
+	}
+	for key, r := range c.replies {
+		if now.Sub(r.lastSeen) > replyTimeout {
+			delete(c.replies, key)
+		}
+	}
+	c.lastSweep = now
+}
+
+func replyKey(a *snet.Addr) string {
+	return a.IA.String() + "," + a.Host.String()
+}
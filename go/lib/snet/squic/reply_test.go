@@ -0,0 +1,75 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package squic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/scmp"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/mock_snet"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/spath/spathmeta"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestReplyConn(t *testing.T) {
+	Convey("Given a reply conn", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		sconn := mock_snet.NewMockConn(ctrl)
+		conn := newReplyConn(sconn)
+		first := newEntry(1, "1-ff00:0:110#5", "1-ff00:0:112#6")
+		second := newEntry(2, "1-ff00:0:110#1", "1-ff00:0:112#4")
+		remote, err := snet.AddrFromString("1-ff00:0:112,[127.0.0.1]:80")
+		xtest.FailOnErr(t, err)
+		withPath := func(entry *spathmeta.AppPath) *snet.Addr {
+			a := remote.Copy()
+			a.Path = &spath.Path{Raw: entry.Entry.Path.FwdPath}
+			return a
+		}
+		paths := spathmeta.AppPathSet{}
+		firstPath, secondPath := paths.Add(first), paths.Add(second)
+		Convey("Replies use the path of the most recent packet", func() {
+			hdr := scmp.NewHdr(scmp.ClassType{Class: scmp.C_Path, Type: scmp.T_P_RevokedIF}, 0)
+			gomock.InOrder(
+				sconn.EXPECT().ReadFrom(gomock.Any()).Return(3, withPath(firstPath), nil),
+				sconn.EXPECT().ReadFrom(gomock.Any()).Return(0, remote, &scmpError{hdr: hdr}),
+				sconn.EXPECT().ReadFrom(gomock.Any()).Return(3, withPath(secondPath), nil),
+			)
+			n, _, err := conn.ReadFrom(make([]byte, 10))
+			SoMsg("first err", err, ShouldBeNil)
+			SoMsg("first n", n, ShouldEqual, 3)
+			_, _, err = conn.ReadFrom(make([]byte, 10))
+			SoMsg("second err", err, ShouldBeNil)
+			sconn.EXPECT().WriteTo(gomock.Any(), usesPath(secondPath)).Return(3, nil)
+			_, err = conn.WriteTo([]byte{1, 2, 3}, withPath(firstPath))
+			SoMsg("write err", err, ShouldBeNil)
+		})
+		Convey("Silent remotes are forgotten", func() {
+			now := time.Now()
+			conn.update(withPath(firstPath), now)
+			other, err := snet.AddrFromString("1-ff00:0:113,[127.0.0.1]:80")
+			xtest.FailOnErr(t, err)
+			conn.update(other, now.Add(2*replyTimeout))
+			SoMsg("replies", len(conn.replies), ShouldEqual, 1)
+			SoMsg("other", conn.replies, ShouldContainKey, replyKey(other))
+		})
+	})
+}
@@ -13,6 +13,12 @@
 // limitations under the License.
 
 // QUIC/SCION implementation.
+//
+// Sessions created by DialSCIONWithMigration track the SCION path to the
+// remote, and migrate to another path if SCMP path errors are received or
+// packets are persistently lost. The QUIC connection is kept across
+// migrations. Listeners reply on the path of the most recent packet received
+// from a client, such that they follow the client's migrations.
 package squic
 
 import (
@@ -84,7 +90,7 @@ func ListenSCIONWithBindSVC(network *snet.SCIONNetwork, laddr, baddr *snet.Addr,
 	if err != nil {
 		return nil, err
 	}
-	return quic.Listen(sconn, srvTlsCfg, quicConfig)
+	return quic.Listen(newReplyConn(sconn), srvTlsCfg, quicConfig)
 }
 
 func sListen(network *snet.SCIONNetwork, laddr, baddr *snet.Addr,
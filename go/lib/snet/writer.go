@@ -62,7 +62,7 @@ func newScionConnWriter(base *scionConnBase, pr pathmgr.Resolver,
 		conn: conn,
 		resolver: &remoteAddressResolver{
 			localIA:      base.laddr.IA,
-			pathResolver: pathsource.NewPathSource(pr),
+			pathResolver: pathsource.NewPathSource(pr, base.selector),
 			monitor:      ctxmonitor.NewMonitor(),
 		},
 		buffer: make(common.RawBytes, common.MaxMTU),
@@ -0,0 +1,237 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+const (
+	// DefaultBatchSize is the maximum number of spans sent to the collector
+	// in one request.
+	DefaultBatchSize = 100
+	// DefaultFlushInterval is the maximum time a span is buffered before it
+	// is sent to the collector.
+	DefaultFlushInterval = time.Second
+	// queueSize is the number of spans that are buffered for the collector.
+	// Spans that do not fit are dropped.
+	queueSize = 1000
+)
+
+// Reporter exports finished spans.
+type Reporter interface {
+	// Report exports the span. It must not block.
+	Report(span *SpanData)
+	// Close flushes all buffered spans and releases the resources.
+	Close() error
+}
+
+// zipkinSpan is the Zipkin v2 JSON representation of a span. It is
+// understood by Zipkin and Jaeger collectors.
+type zipkinSpan struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Name          string            `json:"name"`
+	Kind          Kind              `json:"kind,omitempty"`
+	Timestamp     int64             `json:"timestamp"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint zipkinEndpoint    `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+func newZipkinSpan(s *SpanData) *zipkinSpan {
+	zs := &zipkinSpan{
+		TraceID:       s.TraceID.String(),
+		ID:            s.SpanID.String(),
+		Name:          s.Name,
+		Kind:          s.Kind,
+		Timestamp:     s.Start.UnixNano() / int64(time.Microsecond),
+		Duration:      int64(s.Duration / time.Microsecond),
+		LocalEndpoint: zipkinEndpoint{ServiceName: s.Service},
+		Tags:          s.Tags,
+	}
+	if s.ParentID != (SpanID{}) {
+		zs.ParentID = s.ParentID.String()
+	}
+	return zs
+}
+
+var _ Reporter = (*FileReporter)(nil)
+
+// FileReporter writes each span as a JSON line (Zipkin v2 format) to a
+// writer.
+type FileReporter struct {
+	mtx sync.Mutex
+	w   io.WriteCloser
+	enc *json.Encoder
+}
+
+// NewFileReporter creates a reporter that appends spans to the file.
+func NewFileReporter(path string) (*FileReporter, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, common.NewBasicError("Unable to open trace file", err, "path", path)
+	}
+	return NewWriterReporter(f), nil
+}
+
+// NewWriterReporter creates a reporter that writes spans to w.
+func NewWriterReporter(w io.WriteCloser) *FileReporter {
+	return &FileReporter{w: w, enc: json.NewEncoder(w)}
+}
+
+func (r *FileReporter) Report(span *SpanData) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if err := r.enc.Encode(newZipkinSpan(span)); err != nil {
+		log.Warn("[tracing] Unable to write span", "err", err)
+	}
+}
+
+func (r *FileReporter) Close() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.w.Close()
+}
+
+var _ Reporter = (*CollectorReporter)(nil)
+
+// CollectorReporter sends spans in batches to a collector that accepts the
+// Zipkin v2 JSON format over HTTP, e.g. Jaeger with the Zipkin endpoint
+// enabled (http://localhost:9411/api/v2/spans).
+type CollectorReporter struct {
+	url    string
+	client *http.Client
+	queue  chan *zipkinSpan
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewCollectorReporter creates a reporter that sends spans to url and starts
+// its background goroutine.
+func NewCollectorReporter(url string) *CollectorReporter {
+	r := &CollectorReporter{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+		queue:  make(chan *zipkinSpan, queueSize),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer log.LogPanicAndExit()
+		r.run()
+	}()
+	return r
+}
+
+func (r *CollectorReporter) Report(span *SpanData) {
+	select {
+	case r.queue <- newZipkinSpan(span):
+	default:
+		log.Debug("[tracing] Queue full, dropping span", "trace", span.TraceID)
+	}
+}
+
+// Close sends the buffered spans and stops the background goroutine.
+func (r *CollectorReporter) Close() error {
+	r.once.Do(func() {
+		close(r.queue)
+		<-r.done
+	})
+	return nil
+}
+
+func (r *CollectorReporter) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(DefaultFlushInterval)
+	defer ticker.Stop()
+	batch := make([]*zipkinSpan, 0, DefaultBatchSize)
+	for {
+		select {
+		case span, ok := <-r.queue:
+			if !ok {
+				r.send(batch)
+				return
+			}
+			batch = append(batch, span)
+			if len(batch) >= DefaultBatchSize {
+				r.send(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			r.send(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (r *CollectorReporter) send(batch []*zipkinSpan) {
+	if len(batch) == 0 {
+		return
+	}
+	raw, err := json.Marshal(batch)
+	if err != nil {
+		log.Warn("[tracing] Unable to encode spans", "err", err)
+		return
+	}
+	resp, err := r.client.Post(r.url, "application/json", bytes.NewReader(raw))
+	if err != nil {
+		log.Warn("[tracing] Unable to send spans", "url", r.url, "err", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Warn("[tracing] Collector rejected spans", "url", r.url, "status", resp.Status)
+	}
+}
+
+var _ Reporter = multiReporter(nil)
+
+// multiReporter passes spans to all contained reporters.
+type multiReporter []Reporter
+
+// NewMultiReporter returns a reporter that passes spans to all reporters.
+func NewMultiReporter(reporters ...Reporter) Reporter {
+	return multiReporter(reporters)
+}
+
+func (m multiReporter) Report(span *SpanData) {
+	for _, r := range m {
+		r.Report(span)
+	}
+}
+
+func (m multiReporter) Close() error {
+	var firstErr error
+	for _, r := range m {
+		if err := r.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
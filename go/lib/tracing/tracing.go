@@ -0,0 +1,347 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing implements distributed request tracing for the SCION
+// control plane.
+//
+// A trace consists of spans, each span describing one operation, e.g. the
+// handling of a request or an outgoing request. Spans are stored in the
+// context. The span context (trace ID, span ID and sampling decision) is
+// carried across services in the traceId field of control payloads, such that
+// the spans of all involved services can be combined into one trace.
+//
+// Usage:
+//  span, ctx := tracing.StartSpan(ctx, "fetch_paths", tracing.KindInternal)
+//  defer span.Finish()
+//  span.SetTag("dst", dst)
+//
+// Finished spans of sampled traces are passed to the Reporter of the global
+// tracer, which exports them to a file or a Zipkin compatible collector (e.g.
+// Jaeger). If no global tracer is set, tracing is disabled and all spans are
+// nil, which is a valid no-op span.
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/common"
+)
+
+// SpanContextLen is the length of a packed span context.
+const SpanContextLen = 25
+
+const flagSampled = 0x01
+
+// Kind describes the role of a span in a request.
+type Kind string
+
+const (
+	// KindInternal is a span that describes a local operation.
+	KindInternal Kind = ""
+	// KindServer is a span that describes the handling of an incoming request.
+	KindServer Kind = "SERVER"
+	// KindClient is a span that describes an outgoing request.
+	KindClient Kind = "CLIENT"
+)
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext is the part of a span that is propagated to other services.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// ParseSpanContext parses a packed span context.
+func ParseSpanContext(b common.RawBytes) (SpanContext, error) {
+	var sc SpanContext
+	if len(b) != SpanContextLen {
+		return sc, common.NewBasicError("Invalid span context length", nil,
+			"expected", SpanContextLen, "actual", len(b))
+	}
+	copy(sc.TraceID[:], b[:16])
+	copy(sc.SpanID[:], b[16:24])
+	sc.Sampled = b[24]&flagSampled != 0
+	if !sc.IsValid() {
+		return sc, common.NewBasicError("Invalid span context, trace ID is zero", nil)
+	}
+	return sc, nil
+}
+
+// Pack returns the packed span context.
+func (sc SpanContext) Pack() common.RawBytes {
+	b := make(common.RawBytes, SpanContextLen)
+	copy(b[:16], sc.TraceID[:])
+	copy(b[16:24], sc.SpanID[:])
+	if sc.Sampled {
+		b[24] |= flagSampled
+	}
+	return b
+}
+
+// IsValid returns whether the span context belongs to a trace.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{}
+}
+
+func (sc SpanContext) String() string {
+	return fmt.Sprintf("%s:%s:%t", sc.TraceID, sc.SpanID, sc.Sampled)
+}
+
+// Span is a single operation within a trace. All methods can be called on a
+// nil span, in which case they are no-ops.
+type Span struct {
+	tracer   *Tracer
+	name     string
+	kind     Kind
+	sc       SpanContext
+	parentID SpanID
+	start    time.Time
+
+	mtx      sync.Mutex
+	tags     map[string]string
+	finished bool
+}
+
+// Context returns the span context. For a nil span, the zero value is
+// returned.
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+// SetTag attaches a key/value pair to the span. The value is converted to a
+// string using fmt.Sprint.
+func (s *Span) SetTag(key string, value interface{}) {
+	if s == nil || !s.sc.Sampled {
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.finished {
+		return
+	}
+	if s.tags == nil {
+		s.tags = make(map[string]string)
+	}
+	s.tags[key] = fmt.Sprint(value)
+}
+
+// SetError marks the span as failed if err is not nil.
+func (s *Span) SetError(err error) {
+	if err != nil {
+		s.SetTag("error", err)
+	}
+}
+
+// Finish ends the span and reports it if it is sampled. Calls after the first
+// one are ignored.
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+	s.mtx.Lock()
+	if s.finished {
+		s.mtx.Unlock()
+		return
+	}
+	s.finished = true
+	data := &SpanData{
+		Service:  s.tracer.service,
+		Name:     s.name,
+		Kind:     s.kind,
+		TraceID:  s.sc.TraceID,
+		SpanID:   s.sc.SpanID,
+		ParentID: s.parentID,
+		Start:    s.start,
+		Duration: time.Since(s.start),
+		Tags:     s.tags,
+	}
+	s.mtx.Unlock()
+	if s.sc.Sampled {
+		s.tracer.reporter.Report(data)
+	}
+}
+
+// SpanData is a finished span as it is passed to the reporter.
+type SpanData struct {
+	Service  string
+	Name     string
+	Kind     Kind
+	TraceID  TraceID
+	SpanID   SpanID
+	ParentID SpanID
+	Start    time.Time
+	Duration time.Duration
+	Tags     map[string]string
+}
+
+// Tracer creates spans and reports them once they are finished.
+type Tracer struct {
+	service    string
+	sampleRate float64
+	reporter   Reporter
+
+	randMtx sync.Mutex
+	rand    *rand.Rand
+}
+
+// NewTracer creates a new tracer for the service. New traces are sampled with
+// probability sampleRate, traces started in other services keep the sampling
+// decision of the remote. If reporter is nil, the tracer creates no spans.
+func NewTracer(service string, sampleRate float64, reporter Reporter) *Tracer {
+	return &Tracer{
+		service:    service,
+		sampleRate: sampleRate,
+		reporter:   reporter,
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// StartSpan starts a new span. If ctx contains a span, the new span is its
+// child, otherwise a new trace is started. The returned context contains the
+// new span.
+func (t *Tracer) StartSpan(ctx context.Context, name string,
+	kind Kind) (*Span, context.Context) {
+
+	return t.StartSpanWithParent(ctx, name, kind, SpanFromCtx(ctx).Context())
+}
+
+// StartSpanWithParent starts a new span that is a child of parent, which
+// usually is the span context of a remote service. If parent is not valid, a
+// new trace is started. The returned context contains the new span.
+func (t *Tracer) StartSpanWithParent(ctx context.Context, name string, kind Kind,
+	parent SpanContext) (*Span, context.Context) {
+
+	if t == nil || t.reporter == nil {
+		return nil, ctx
+	}
+	span := &Span{
+		tracer: t,
+		name:   name,
+		kind:   kind,
+		start:  time.Now(),
+	}
+	t.randMtx.Lock()
+	if parent.IsValid() {
+		span.sc.TraceID = parent.TraceID
+		span.sc.Sampled = parent.Sampled
+		span.parentID = parent.SpanID
+	} else {
+		t.rand.Read(span.sc.TraceID[:])
+		span.sc.Sampled = t.rand.Float64() < t.sampleRate
+	}
+	t.rand.Read(span.sc.SpanID[:])
+	t.randMtx.Unlock()
+	return span, CtxWith(ctx, span)
+}
+
+// Close closes the reporter of the tracer.
+func (t *Tracer) Close() error {
+	if t == nil || t.reporter == nil {
+		return nil
+	}
+	return t.reporter.Close()
+}
+
+type spanContextKey struct{}
+
+// CtxWith returns a new context, based on ctx, that embeds span.
+func CtxWith(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromCtx returns the span embedded in ctx if one exists, or nil
+// otherwise.
+func SpanFromCtx(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// Inject returns the packed span context of the span in ctx. If ctx contains
+// no span, nil is returned.
+func Inject(ctx context.Context) common.RawBytes {
+	span := SpanFromCtx(ctx)
+	if span == nil {
+		return nil
+	}
+	return span.Context().Pack()
+}
+
+var (
+	globalMtx    sync.RWMutex
+	globalTracer *Tracer
+)
+
+// SetGlobal sets the tracer that is used by the package level functions.
+func SetGlobal(t *Tracer) {
+	globalMtx.Lock()
+	defer globalMtx.Unlock()
+	globalTracer = t
+}
+
+// Global returns the global tracer. If none is set, nil is returned, which
+// is a valid tracer that does not create any spans.
+func Global() *Tracer {
+	globalMtx.RLock()
+	defer globalMtx.RUnlock()
+	return globalTracer
+}
+
+// StartSpan starts a new span using the global tracer. See
+// Tracer.StartSpan.
+func StartSpan(ctx context.Context, name string, kind Kind) (*Span, context.Context) {
+	return Global().StartSpan(ctx, name, kind)
+}
+
+// StartSpanFromRemote starts a new span using the global tracer. The span is
+// the child of the packed span context raw, if it can be parsed. Otherwise,
+// it is the child of the span in ctx or the start of a new trace.
+func StartSpanFromRemote(ctx context.Context, name string, kind Kind,
+	raw common.RawBytes) (*Span, context.Context) {
+
+	parent, err := ParseSpanContext(raw)
+	if err != nil {
+		return StartSpan(ctx, name, kind)
+	}
+	return Global().StartSpanWithParent(ctx, name, kind, parent)
+}
+
+// Close closes the global tracer.
+func Close() error {
+	return Global().Close()
+}
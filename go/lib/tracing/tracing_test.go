@@ -0,0 +1,130 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/common"
+)
+
+type testReporter struct {
+	spans []*SpanData
+}
+
+func (r *testReporter) Report(span *SpanData) {
+	r.spans = append(r.spans, span)
+}
+
+func (r *testReporter) Close() error {
+	return nil
+}
+
+type nopCloser struct {
+	*bytes.Buffer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func TestSpanContextPack(t *testing.T) {
+	Convey("Packed span context can be parsed", t, func() {
+		sc := SpanContext{
+			TraceID: TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			SpanID:  SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+			Sampled: true,
+		}
+		parsed, err := ParseSpanContext(sc.Pack())
+		SoMsg("err", err, ShouldBeNil)
+		SoMsg("parsed", parsed, ShouldResemble, sc)
+	})
+	Convey("Parsing fails for", t, func() {
+		Convey("empty input", func() {
+			_, err := ParseSpanContext(nil)
+			SoMsg("err", err, ShouldNotBeNil)
+		})
+		Convey("zero trace ID", func() {
+			_, err := ParseSpanContext(make(common.RawBytes, SpanContextLen))
+			SoMsg("err", err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestTracer(t *testing.T) {
+	Convey("Given a tracer that samples everything", t, func() {
+		reporter := &testReporter{}
+		tracer := NewTracer("ps1-ff00_0_110-1", 1, reporter)
+		root, ctx := tracer.StartSpan(context.Background(), "root", KindServer)
+		SoMsg("root in ctx", SpanFromCtx(ctx), ShouldEqual, root)
+		SoMsg("root sampled", root.Context().Sampled, ShouldBeTrue)
+		Convey("Child spans share the trace", func() {
+			child, _ := tracer.StartSpan(ctx, "child", KindClient)
+			child.SetTag("key", 1)
+			child.Finish()
+			root.Finish()
+			root.Finish()
+			SoMsg("reported", len(reporter.spans), ShouldEqual, 2)
+			SoMsg("trace", reporter.spans[0].TraceID, ShouldResemble, root.Context().TraceID)
+			SoMsg("parent", reporter.spans[0].ParentID, ShouldResemble, root.Context().SpanID)
+			SoMsg("tags", reporter.spans[0].Tags, ShouldResemble, map[string]string{"key": "1"})
+		})
+		Convey("Remote span context is used as parent", func() {
+			remote := root.Context()
+			span, _ := tracer.StartSpanWithParent(context.Background(), "remote",
+				KindServer, remote)
+			SoMsg("trace", span.Context().TraceID, ShouldResemble, remote.TraceID)
+			SoMsg("span", span.Context().SpanID, ShouldNotResemble, remote.SpanID)
+		})
+	})
+	Convey("Remote sampling decision is respected", t, func() {
+		reporter := &testReporter{}
+		tracer := NewTracer("ps", 1, reporter)
+		remote := SpanContext{TraceID: TraceID{1}, SpanID: SpanID{1}, Sampled: false}
+		span, _ := tracer.StartSpanWithParent(context.Background(), "remote", KindServer,
+			remote)
+		span.Finish()
+		SoMsg("not reported", reporter.spans, ShouldBeEmpty)
+	})
+	Convey("Tracer without reporter creates no spans", t, func() {
+		var tracer *Tracer
+		span, ctx := tracer.StartSpan(context.Background(), "root", KindServer)
+		SoMsg("span", span, ShouldBeNil)
+		SoMsg("inject", Inject(ctx), ShouldBeNil)
+		span.SetTag("key", "value")
+		span.Finish()
+	})
+}
+
+func TestFileReporter(t *testing.T) {
+	Convey("FileReporter writes Zipkin JSON lines", t, func() {
+		buf := &bytes.Buffer{}
+		tracer := NewTracer("bs", 1, NewWriterReporter(nopCloser{buf}))
+		span, _ := tracer.StartSpan(context.Background(), "root", KindServer)
+		span.Finish()
+		var zs zipkinSpan
+		err := json.Unmarshal(buf.Bytes(), &zs)
+		SoMsg("err", err, ShouldBeNil)
+		SoMsg("trace", zs.TraceID, ShouldEqual, span.Context().TraceID.String())
+		SoMsg("name", zs.Name, ShouldEqual, "root")
+		SoMsg("service", zs.LocalEndpoint.ServiceName, ShouldEqual, "bs")
+		SoMsg("no parent", zs.ParentID, ShouldBeEmpty)
+	})
+}
@@ -19,6 +19,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/env"
@@ -31,6 +32,10 @@ import (
 var (
 	DefaultQueryInterval      = 5 * time.Minute
 	DefaultCryptoSyncInterval = 30 * time.Second
+	DefaultRegLimitInterval   = time.Minute
+	DefaultPrefetchInterval   = 10 * time.Second
+	DefaultPrefetchLeadTime   = time.Minute
+	DefaultPopularityDecay    = 10 * time.Minute
 )
 
 var _ config.Config = (*Config)(nil)
@@ -40,6 +45,8 @@ type Config struct {
 	Logging   env.Logging
 	Metrics   env.Metrics
 	QUIC      env.QUIC `toml:"quic"`
+	Admission env.Admission
+	Tracing   env.Tracing
 	TrustDB   truststorage.TrustDBConf
 	Discovery idiscovery.Config
 	PS        PSConfig
@@ -50,6 +57,7 @@ func (cfg *Config) InitDefaults() {
 		&cfg.General,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.Tracing,
 		&cfg.TrustDB,
 		&cfg.Discovery,
 		&cfg.PS,
@@ -61,6 +69,8 @@ func (cfg *Config) Validate() error {
 		&cfg.General,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.Admission,
+		&cfg.Tracing,
 		&cfg.TrustDB,
 		&cfg.Discovery,
 		&cfg.PS,
@@ -72,6 +82,8 @@ func (cfg *Config) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 		&cfg.General,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.Admission,
+		&cfg.Tracing,
 		&cfg.TrustDB,
 		&cfg.Discovery,
 		&cfg.PS,
@@ -96,6 +108,10 @@ type PSConfig struct {
 	// CryptoSyncInterval specifies the interval of crypto pushes towards
 	// the local CS.
 	CryptoSyncInterval util.DurWrap
+	// Registration is the segment registration authorization policy.
+	Registration RegistrationConfig
+	// Prefetch configures the refreshing of segments for popular destinations.
+	Prefetch PrefetchConfig
 }
 
 func (cfg *PSConfig) InitDefaults() {
@@ -105,21 +121,123 @@ func (cfg *PSConfig) InitDefaults() {
 	if cfg.CryptoSyncInterval.Duration == 0 {
 		cfg.CryptoSyncInterval.Duration = DefaultCryptoSyncInterval
 	}
-	config.InitAll(&cfg.PathDB, &cfg.RevCache)
+	config.InitAll(&cfg.PathDB, &cfg.RevCache, &cfg.Registration, &cfg.Prefetch)
 }
 
 func (cfg *PSConfig) Validate() error {
 	if cfg.QueryInterval.Duration == 0 {
 		return common.NewBasicError("QueryInterval must not be zero", nil)
 	}
-	return config.ValidateAll(&cfg.PathDB, &cfg.RevCache)
+	return config.ValidateAll(&cfg.PathDB, &cfg.RevCache, &cfg.Registration, &cfg.Prefetch)
 }
 
 func (cfg *PSConfig) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
 	config.WriteString(dst, psSample)
-	config.WriteSample(dst, path, ctx, &cfg.PathDB, &cfg.RevCache)
+	config.WriteSample(dst, path, ctx, &cfg.PathDB, &cfg.RevCache, &cfg.Registration,
+		&cfg.Prefetch)
 }
 
 func (cfg *PSConfig) ConfigName() string {
 	return "ps"
 }
+
+var _ config.Config = (*RegistrationConfig)(nil)
+
+// RegistrationConfig is the authorization policy for segment registrations.
+type RegistrationConfig struct {
+	// AllowForeignSender disables the check that the last AS entry of a
+	// registered segment matches the sending AS.
+	AllowForeignSender bool
+	// UpOrigins contains the IAs that are allowed to register up segments.
+	// Wildcard IAs (e.g., 1-0) match all ASes of the ISD. If empty, all IAs
+	// are allowed.
+	UpOrigins []addr.IA
+	// DownOrigins contains the IAs that are allowed to register down segments.
+	// If empty, all IAs are allowed.
+	DownOrigins []addr.IA
+	// CoreOrigins contains the IAs that are allowed to register core segments.
+	// If empty, all IAs are allowed.
+	CoreOrigins []addr.IA
+	// MaxSegsPerIA is the maximum number of segments an IA can register per
+	// LimitInterval. If zero, the registrations are not limited.
+	MaxSegsPerIA int
+	// LimitInterval is the interval the registration limit applies to.
+	LimitInterval util.DurWrap
+}
+
+func (cfg *RegistrationConfig) InitDefaults() {
+	if cfg.LimitInterval.Duration == 0 {
+		cfg.LimitInterval.Duration = DefaultRegLimitInterval
+	}
+}
+
+func (cfg *RegistrationConfig) Validate() error {
+	if cfg.MaxSegsPerIA < 0 {
+		return common.NewBasicError("MaxSegsPerIA must not be negative", nil,
+			"value", cfg.MaxSegsPerIA)
+	}
+	if cfg.LimitInterval.Duration == 0 {
+		return common.NewBasicError("LimitInterval must not be zero", nil)
+	}
+	return nil
+}
+
+func (cfg *RegistrationConfig) Sample(dst io.Writer, _ config.Path, _ config.CtxMap) {
+	config.WriteString(dst, registrationSample)
+}
+
+func (cfg *RegistrationConfig) ConfigName() string {
+	return "registration"
+}
+
+var _ config.Config = (*PrefetchConfig)(nil)
+
+// PrefetchConfig configures the prefetching of down segments for the most
+// popular destinations. Only non-core path servers prefetch segments.
+type PrefetchConfig struct {
+	// MaxDestinations is the number of most popular destinations for which
+	// segments are kept fresh. If zero, prefetching is disabled.
+	MaxDestinations int
+	// Interval is the interval at which the prefetcher runs.
+	Interval util.DurWrap
+	// LeadTime specifies how long before the next query time, or before the
+	// expiry of the cached segments, the segments are refreshed.
+	LeadTime util.DurWrap
+	// PopularityDecay is the interval after which the request counts of all
+	// destinations are halved.
+	PopularityDecay util.DurWrap
+}
+
+func (cfg *PrefetchConfig) InitDefaults() {
+	if cfg.Interval.Duration == 0 {
+		cfg.Interval.Duration = DefaultPrefetchInterval
+	}
+	if cfg.LeadTime.Duration == 0 {
+		cfg.LeadTime.Duration = DefaultPrefetchLeadTime
+	}
+	if cfg.PopularityDecay.Duration == 0 {
+		cfg.PopularityDecay.Duration = DefaultPopularityDecay
+	}
+}
+
+func (cfg *PrefetchConfig) Validate() error {
+	if cfg.MaxDestinations < 0 {
+		return common.NewBasicError("MaxDestinations must not be negative", nil,
+			"value", cfg.MaxDestinations)
+	}
+	if cfg.Interval.Duration == 0 {
+		return common.NewBasicError("Interval must not be zero", nil)
+	}
+	if cfg.PopularityDecay.Duration == 0 {
+		return common.NewBasicError("PopularityDecay must not be zero", nil)
+	}
+	return nil
+}
+
+func (cfg *PrefetchConfig) Sample(dst io.Writer, _ config.Path, _ config.CtxMap) {
+	config.WriteString(dst, prefetchSample)
+}
+
+func (cfg *PrefetchConfig) ConfigName() string {
+	return "prefetch"
+}
@@ -21,10 +21,12 @@ import (
 	"github.com/BurntSushi/toml"
 	. "github.com/smartystreets/goconvey/convey"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/env/envtest"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery/idiscoverytest"
 	"github.com/scionproto/scion/go/lib/pathstorage/pathstoragetest"
 	"github.com/scionproto/scion/go/lib/truststorage/truststoragetest"
+	"github.com/scionproto/scion/go/lib/xtest"
 )
 
 func TestConfigSample(t *testing.T) {
@@ -52,6 +54,18 @@ func InitTestPSConfig(cfg *PSConfig) {
 	cfg.SegSync = true
 	pathstoragetest.InitTestPathDBConf(&cfg.PathDB)
 	pathstoragetest.InitTestRevCacheConf(&cfg.RevCache)
+	InitTestRegistrationConfig(&cfg.Registration)
+	InitTestPrefetchConfig(&cfg.Prefetch)
+}
+
+func InitTestRegistrationConfig(cfg *RegistrationConfig) {
+	cfg.AllowForeignSender = true
+	cfg.UpOrigins = []addr.IA{xtest.MustParseIA("1-ff00:0:110")}
+	cfg.MaxSegsPerIA = 10
+}
+
+func InitTestPrefetchConfig(cfg *PrefetchConfig) {
+	cfg.MaxDestinations = 20
 }
 
 func CheckTestConfig(cfg *Config, id string) {
@@ -68,4 +82,22 @@ func CheckTestPSConfig(cfg *PSConfig, id string) {
 	SoMsg("QueryInterval correct", cfg.QueryInterval.Duration, ShouldEqual, DefaultQueryInterval)
 	SoMsg("CryptoSyncInterval correct", cfg.CryptoSyncInterval.Duration,
 		ShouldEqual, DefaultCryptoSyncInterval)
+	CheckTestRegistrationConfig(&cfg.Registration)
+	CheckTestPrefetchConfig(&cfg.Prefetch)
+}
+
+func CheckTestRegistrationConfig(cfg *RegistrationConfig) {
+	SoMsg("AllowForeignSender", cfg.AllowForeignSender, ShouldBeFalse)
+	SoMsg("UpOrigins", cfg.UpOrigins, ShouldBeEmpty)
+	SoMsg("DownOrigins", cfg.DownOrigins, ShouldBeEmpty)
+	SoMsg("CoreOrigins", cfg.CoreOrigins, ShouldBeEmpty)
+	SoMsg("MaxSegsPerIA", cfg.MaxSegsPerIA, ShouldEqual, 0)
+	SoMsg("LimitInterval", cfg.LimitInterval.Duration, ShouldEqual, DefaultRegLimitInterval)
+}
+
+func CheckTestPrefetchConfig(cfg *PrefetchConfig) {
+	SoMsg("MaxDestinations", cfg.MaxDestinations, ShouldEqual, 0)
+	SoMsg("Interval", cfg.Interval.Duration, ShouldEqual, DefaultPrefetchInterval)
+	SoMsg("LeadTime", cfg.LeadTime.Duration, ShouldEqual, DefaultPrefetchLeadTime)
+	SoMsg("PopularityDecay", cfg.PopularityDecay.Duration, ShouldEqual, DefaultPopularityDecay)
 }
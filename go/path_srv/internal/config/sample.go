@@ -27,3 +27,46 @@ QueryInterval = "5m"
 # The interval of crypto pushes towards the local CS. (default 30s)
 CryptoSyncInterval = "30s"
 `
+
+const registrationSample = `
+# Allow segments whose last AS entry does not match the sending AS.
+# (default false)
+AllowForeignSender = false
+
+# The IAs that are allowed to register up segments. Wildcard IAs (e.g. "1-0")
+# match all ASes in the ISD. If empty, all IAs are allowed. (default [])
+UpOrigins = []
+
+# The IAs that are allowed to register down segments. If empty, all IAs are
+# allowed. (default [])
+DownOrigins = []
+
+# The IAs that are allowed to register core segments. If empty, all IAs are
+# allowed. (default [])
+CoreOrigins = []
+
+# The maximum number of segments an IA can register per LimitInterval. If 0,
+# the registrations are not limited. (default 0)
+MaxSegsPerIA = 0
+
+# The interval the registration limit applies to. (default 1m)
+LimitInterval = "1m"
+`
+
+const prefetchSample = `
+# The number of most popular destinations for which down segments are
+# refreshed before they become stale. If 0, prefetching is disabled. Only
+# non-core path servers prefetch segments. (default 0)
+MaxDestinations = 0
+
+# The interval at which the prefetcher runs. (default 10s)
+Interval = "10s"
+
+# How long before the next query time, or before the expiry of the cached
+# segments, the segments are refreshed. (default 1m)
+LeadTime = "1m"
+
+# The interval after which the request counts of all destinations are halved.
+# (default 10m)
+PopularityDecay = "10m"
+`
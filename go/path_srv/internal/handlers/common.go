@@ -34,6 +34,8 @@ import (
 	"github.com/scionproto/scion/go/lib/revcache"
 	"github.com/scionproto/scion/go/lib/topology"
 	"github.com/scionproto/scion/go/path_srv/internal/config"
+	"github.com/scionproto/scion/go/path_srv/internal/popularity"
+	"github.com/scionproto/scion/go/path_srv/internal/regpolicy"
 	"github.com/scionproto/scion/go/path_srv/internal/segutil"
 )
 
@@ -48,6 +50,12 @@ type HandlerArgs struct {
 	TrustStore infra.TrustStore
 	Config     config.PSConfig
 	IA         addr.IA
+	// RegPolicy authorizes segment registrations. If nil, all registrations
+	// are authorized.
+	RegPolicy *regpolicy.Policy
+	// Popularity tracks the requested destinations. If nil, requests are not
+	// tracked.
+	Popularity *popularity.Tracker
 }
 
 type baseHandler struct {
@@ -58,6 +66,7 @@ type baseHandler struct {
 	topology   *topology.Topo
 	retryInt   time.Duration
 	config     config.PSConfig
+	popularity *popularity.Tracker
 }
 
 func newBaseHandler(request *infra.Request, args HandlerArgs) *baseHandler {
@@ -69,6 +78,7 @@ func newBaseHandler(request *infra.Request, args HandlerArgs) *baseHandler {
 		retryInt:   time.Second,
 		config:     args.Config,
 		topology:   itopo.Get(),
+		popularity: args.Popularity,
 	}
 }
 
@@ -124,11 +134,16 @@ func (h *baseHandler) fetchSegsFromDBRetry(ctx context.Context,
 
 func (h *baseHandler) verifyAndStore(ctx context.Context, src net.Addr,
 	recs []*seg.Meta, revInfos []*path_mgmt.SignedRevInfo) {
-	// TODO(lukedirtwalker): collect the verified segs/revoc and return them.
+
+	h.storeSegs(ctx, h.verify(ctx, src, recs, revInfos))
+}
+
+// verify verifies the segments and the revocations. The verified revocations
+// are inserted into the revocation cache, the verified segments are returned.
+func (h *baseHandler) verify(ctx context.Context, src net.Addr,
+	recs []*seg.Meta, revInfos []*path_mgmt.SignedRevInfo) []*seg.Meta {
 
 	logger := log.FromCtx(ctx)
-	// verify and store the segments
-	var insertedSegmentIDs []string
 	var mtx sync.Mutex
 	verifiedSegs := make([]*seg.Meta, 0, len(recs))
 	verifiedSeg := func(ctx context.Context, s *seg.Meta) {
@@ -149,7 +164,33 @@ func (h *baseHandler) verifyAndStore(ctx context.Context, src net.Addr,
 	}
 	segverifier.Verify(ctx, h.trustStore.NewVerifier(), src, recs,
 		revInfos, verifiedSeg, verifiedRev, segErr, revErr)
+	mtx.Lock()
+	defer mtx.Unlock()
+	return verifiedSegs
+}
 
+// verifyRevs verifies the revocations individually and inserts the verified
+// ones into the revocation cache.
+func (h *baseHandler) verifyRevs(ctx context.Context, src net.Addr,
+	revInfos []*path_mgmt.SignedRevInfo) {
+
+	logger := log.FromCtx(ctx)
+	for _, rev := range revInfos {
+		err := segverifier.VerifyRevInfo(ctx, h.trustStore.NewVerifier(), src, rev)
+		if err != nil {
+			logger.Warn("Revocation verification failed", "revocation", rev, "err", err)
+			continue
+		}
+		if _, err := h.revCache.Insert(ctx, rev); err != nil {
+			logger.Error("Unable to insert revocation into revcache", "rev", rev, "err", err)
+		}
+	}
+}
+
+// storeSegs stores the verified segments in the path database.
+func (h *baseHandler) storeSegs(ctx context.Context, verifiedSegs []*seg.Meta) {
+	logger := log.FromCtx(ctx)
+	var insertedSegmentIDs []string
 	tx, err := h.pathDB.BeginTransaction(ctx, nil)
 	if err != nil {
 		logger.Error("Failed to create transaction", "err", err)
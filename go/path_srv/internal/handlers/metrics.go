@@ -0,0 +1,47 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
+const (
+	lookupHit  = "hit"
+	lookupMiss = "miss"
+
+	prefetchOk  = "ok"
+	prefetchErr = "err"
+)
+
+var metrics = newMetrics()
+
+type handlerMetrics struct {
+	lookups    *prometheus.CounterVec
+	prefetches *prometheus.CounterVec
+}
+
+func newMetrics() handlerMetrics {
+	return handlerMetrics{
+		lookups: prom.NewCounterVec("path_srv", "segreq", "down_lookups_total",
+			"Number of down segment lookups, by whether they were served from the cache.",
+			[]string{"result"}),
+		prefetches: prom.NewCounterVec("path_srv", "prefetch", "refreshes_total",
+			"Number of down segment refreshes for popular destinations.",
+			[]string{"result"}),
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/infra/dedupe"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/pathdb/query"
+	"github.com/scionproto/scion/go/lib/periodic"
+	"github.com/scionproto/scion/go/proto"
+)
+
+var _ periodic.Task = (*Prefetcher)(nil)
+
+// Prefetcher is a periodic task of the non-core path server that refreshes
+// the down segments of the most popular destinations before they become
+// stale, such that requests for these destinations can be answered from the
+// cache.
+type Prefetcher struct {
+	args        HandlerArgs
+	segsDeduper dedupe.Deduper
+}
+
+// NewPrefetcher creates a prefetcher for the destinations tracked in
+// args.Popularity.
+func NewPrefetcher(args HandlerArgs, segsDeduper dedupe.Deduper) *Prefetcher {
+	return &Prefetcher{
+		args:        args,
+		segsDeduper: segsDeduper,
+	}
+}
+
+// Run refreshes the down segments of the most popular destinations whose
+// segments become stale within the configured lead time.
+func (p *Prefetcher) Run(ctx context.Context) {
+	cfg := p.args.Config.Prefetch
+	dsts := p.args.Popularity.Top(cfg.MaxDestinations)
+	if len(dsts) == 0 {
+		return
+	}
+	logger := log.FromCtx(ctx)
+	h := &segReqNonCoreHandler{
+		segReqHandler: segReqHandler{
+			baseHandler: newBaseHandler(nil, p.args),
+			localIA:     p.args.IA,
+			segsDeduper: p.segsDeduper,
+		},
+	}
+	coreASes, err := h.coreASes(ctx)
+	if err != nil {
+		logger.Error("[Prefetcher] Failed to find local core ASes", "err", err)
+		return
+	}
+	deadline := time.Now().Add(cfg.LeadTime.Duration)
+	for _, dst := range dsts {
+		refresh, err := h.needsPrefetch(ctx, dst, deadline)
+		if err != nil {
+			logger.Warn("[Prefetcher] Failed to check cached segments", "dst", dst, "err", err)
+		}
+		if !refresh {
+			continue
+		}
+		if err := h.prefetchDownSegs(ctx, dst, coreASes.ASList()); err != nil {
+			logger.Warn("[Prefetcher] Failed to refresh down segments", "dst", dst, "err", err)
+			metrics.prefetches.WithLabelValues(prefetchErr).Inc()
+			continue
+		}
+		metrics.prefetches.WithLabelValues(prefetchOk).Inc()
+	}
+}
+
+// needsPrefetch returns true if the down segments for dst have to be
+// refetched before the deadline, i.e., if the next query time is before the
+// deadline or if no cached segment is valid until the deadline. Returns true
+// on error, so the value can be used anyway.
+func (h *segReqNonCoreHandler) needsPrefetch(ctx context.Context, dst addr.IA,
+	deadline time.Time) (bool, error) {
+
+	refetch, err := h.shouldRefetchSegsForDst(ctx, dst, deadline)
+	if err != nil || refetch {
+		return true, err
+	}
+	segs, err := h.fetchSegsFromDB(ctx, &query.Params{
+		SegTypes: []proto.PathSegType{proto.PathSegType_down},
+		EndsAt:   []addr.IA{dst},
+	})
+	if err != nil {
+		return true, err
+	}
+	for _, s := range segs {
+		if s.MaxExpiry().After(deadline) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (h *segReqNonCoreHandler) prefetchDownSegs(ctx context.Context, dst addr.IA,
+	coreASes []addr.IA) error {
+
+	cPS, err := h.corePSAddr(ctx, coreASes)
+	if err != nil {
+		return common.NewBasicError("Failed to find core PS", err)
+	}
+	log.FromCtx(ctx).Debug("[Prefetcher] Refresh down segments", "dst", dst, "remote", cPS)
+	return h.fetchAndSaveSegs(ctx, addr.IA{}, dst, cPS)
+}
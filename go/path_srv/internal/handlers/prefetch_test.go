@@ -0,0 +1,78 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/revcache/memrevcache"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestNeedsPrefetch(t *testing.T) {
+	Convey("Given a cached down segment to 2-ff00:0:211", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		tg := newTestGraph(ctrl)
+		db := setupDB(t, testCase{Downs: []*seg.PathSegment{tg.seg210_211}})
+		h := &segReqNonCoreHandler{
+			segReqHandler: segReqHandler{
+				baseHandler: &baseHandler{
+					pathDB:   db,
+					revCache: memrevcache.New(),
+				},
+				localIA: as1_132,
+			},
+		}
+		ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+		defer cancelF()
+		now := time.Now()
+		Convey("Without a next query time, the segments are refreshed", func() {
+			refresh, err := h.needsPrefetch(ctx, as2_211, now)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("refresh", refresh, ShouldBeTrue)
+		})
+		Convey("Segments are refreshed if the next query is before the deadline", func() {
+			_, err := db.InsertNextQuery(ctx, as2_211, now.Add(time.Minute))
+			xtest.FailOnErr(t, err)
+			refresh, err := h.needsPrefetch(ctx, as2_211, now)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("not yet", refresh, ShouldBeFalse)
+			refresh, err = h.needsPrefetch(ctx, as2_211, now.Add(2*time.Minute))
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("refresh", refresh, ShouldBeTrue)
+		})
+		Convey("Segments are refreshed if they expire before the deadline", func() {
+			_, err := db.InsertNextQuery(ctx, as2_211, now.Add(72*time.Hour))
+			xtest.FailOnErr(t, err)
+			refresh, err := h.needsPrefetch(ctx, as2_211, now.Add(48*time.Hour))
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("refresh", refresh, ShouldBeTrue)
+		})
+		Convey("Destinations without cached segments are refreshed", func() {
+			_, err := db.InsertNextQuery(ctx, as2_222, now.Add(time.Hour))
+			xtest.FailOnErr(t, err)
+			refresh, err := h.needsPrefetch(ctx, as2_222, now)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("refresh", refresh, ShouldBeTrue)
+		})
+	})
+}
@@ -20,16 +20,19 @@ import (
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
 	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/infra/messenger"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/path_srv/internal/regpolicy"
 	"github.com/scionproto/scion/go/proto"
 )
 
 type segRegHandler struct {
 	*baseHandler
-	localIA addr.IA
+	localIA   addr.IA
+	regPolicy *regpolicy.Policy
 }
 
 func NewSegRegHandler(args HandlerArgs) infra.Handler {
@@ -37,6 +40,7 @@ func NewSegRegHandler(args HandlerArgs) infra.Handler {
 		handler := &segRegHandler{
 			baseHandler: newBaseHandler(r, args),
 			localIA:     args.IA,
+			regPolicy:   args.RegPolicy,
 		}
 		return handler.Handle()
 	}
@@ -67,6 +71,11 @@ func (h *segRegHandler) Handle() *infra.HandlerResult {
 	logSegRecs(logger, "[segRegHandler]", h.request.Peer, segReg.SegRecs)
 
 	snetPeer := h.request.Peer.(*snet.Addr)
+	recs, rejected := h.regPolicy.Filter(snetPeer.IA, segReg.Recs)
+	for _, err := range rejected {
+		logger.Info("[segRegHandler] Registration rejected by policy", "src", snetPeer.IA,
+			"err", err)
+	}
 	peerPath, err := snetPeer.GetPath()
 	if err != nil {
 		logger.Error("[syncHandler] Failed to initialize path", "err", err)
@@ -80,8 +89,53 @@ func (h *segRegHandler) Handle() *infra.HandlerResult {
 		Host:    addr.NewSVCUDPAppAddr(addr.SvcBS),
 	}
 
-	h.verifyAndStore(subCtx, svcToQuery, segReg.Recs, segReg.SRevInfos)
+	// The revocations are only verified together with the segments they
+	// apply to. The ones of rejected segments are verified on their own.
+	h.verifyRevs(subCtx, svcToQuery, rejectedRevs(segReg.Recs, recs, segReg.SRevInfos))
+	if len(recs) == 0 && len(rejected) > 0 {
+		sendAck(proto.Ack_ErrCode_reject, messenger.AckRejectPolicyError)
+		return infra.MetricsErrInvalid
+	}
+	verified := h.verify(subCtx, svcToQuery, recs, segReg.SRevInfos)
+	// Only verified segments count towards the registration limit.
+	verified, limited := h.regPolicy.Limit(verified)
+	for _, err := range limited {
+		logger.Info("[segRegHandler] Registration rejected by policy", "src", snetPeer.IA,
+			"err", err)
+	}
+	h.storeSegs(subCtx, verified)
 	// TODO(lukedirtwalker): If all segments failed to verify the ack should also be negative here.
 	sendAck(proto.Ack_ErrCode_ok, "")
 	return infra.MetricsResultOk
 }
+
+// rejectedRevs returns the revocations that apply to a segment in all, but
+// to none of the allowed segments.
+func rejectedRevs(all, allowed []*seg.Meta,
+	revInfos []*path_mgmt.SignedRevInfo) []*path_mgmt.SignedRevInfo {
+
+	if len(all) == len(allowed) {
+		return nil
+	}
+	var revs []*path_mgmt.SignedRevInfo
+	for _, rev := range revInfos {
+		info, err := rev.RevInfo()
+		if err != nil {
+			continue
+		}
+		ia, ifid := info.IA(), common.IFIDType(info.IfID)
+		if containsInterface(all, ia, ifid) && !containsInterface(allowed, ia, ifid) {
+			revs = append(revs, rev)
+		}
+	}
+	return revs
+}
+
+func containsInterface(recs []*seg.Meta, ia addr.IA, ifid common.IFIDType) bool {
+	for _, rec := range recs {
+		if rec.Segment.ContainsInterface(ia, ifid) {
+			return true
+		}
+	}
+	return false
+}
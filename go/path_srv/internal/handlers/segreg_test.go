@@ -0,0 +1,72 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestRejectedRevs(t *testing.T) {
+	Convey("Given allowed and rejected segments", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		g := newTestGraph(ctrl)
+		allowed := seg.NewMeta(g.seg130_132, proto.PathSegType_down)
+		rejected := seg.NewMeta(g.seg110_130, proto.PathSegType_down)
+		revAllowed := testSignedRev(t, "1-ff00:0:130", graph.If_130_A_131_X)
+		revRejected := testSignedRev(t, "1-ff00:0:110", graph.If_110_X_130_A)
+		revNone := testSignedRev(t, "1-ff00:0:220", graph.If_220_X_221_X)
+		revs := []*path_mgmt.SignedRevInfo{revAllowed, revRejected, revNone}
+		Convey("Only the revocations of rejected segments are returned", func() {
+			res := rejectedRevs([]*seg.Meta{allowed, rejected}, []*seg.Meta{allowed}, revs)
+			SoMsg("revs", res, ShouldResemble, []*path_mgmt.SignedRevInfo{revRejected})
+		})
+		Convey("All revocations of segments are returned if all are rejected", func() {
+			res := rejectedRevs([]*seg.Meta{allowed, rejected}, nil, revs)
+			SoMsg("revs", res, ShouldResemble,
+				[]*path_mgmt.SignedRevInfo{revAllowed, revRejected})
+		})
+		Convey("No revocations are returned if no segment is rejected", func() {
+			res := rejectedRevs([]*seg.Meta{allowed}, []*seg.Meta{allowed}, revs)
+			SoMsg("revs", res, ShouldBeEmpty)
+		})
+	})
+}
+
+func testSignedRev(t *testing.T, ia string, ifid common.IFIDType) *path_mgmt.SignedRevInfo {
+	info := &path_mgmt.RevInfo{
+		IfID:         ifid,
+		RawIsdas:     xtest.MustParseIA(ia).IAInt(),
+		LinkType:     proto.LinkType_child,
+		RawTimestamp: util.TimeToSecs(time.Now()),
+		RawTTL:       10,
+	}
+	rev, err := path_mgmt.NewSignedRevInfo(info, infra.NullSigner)
+	xtest.FailOnErr(t, err)
+	return rev
+}
@@ -95,9 +95,13 @@ func (h *segReqHandler) fetchDownSegs(ctx context.Context, dst addr.IA,
 			}
 		}
 		if !refetch {
+			if !dbOnly {
+				metrics.lookups.WithLabelValues(lookupHit).Inc()
+			}
 			return segs, nil
 		}
 	}
+	metrics.lookups.WithLabelValues(lookupMiss).Inc()
 	cAddr, err := cPSAddr()
 	if err != nil {
 		return nil, err
@@ -158,6 +158,7 @@ func (h *segReqNonCoreHandler) handleNonCoreDst(ctx context.Context, segReq *pat
 	rw infra.ResponseWriter, dstIA addr.IA, coreASes []addr.IA) {
 
 	logger := log.FromCtx(ctx)
+	h.popularity.Record(dstIA)
 	cPSResolve := func() (net.Addr, error) {
 		return h.corePSAddr(ctx, coreASes)
 	}
@@ -0,0 +1,108 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package popularity tracks how often path requests for a destination IA are
+// received. Counts decay over time, such that the tracker reflects the recent
+// popularity of destinations.
+package popularity
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+)
+
+// Tracker counts requests per destination IA. Every decay interval, all counts
+// are halved and destinations that are no longer requested are dropped. A nil
+// tracker is valid and records nothing.
+type Tracker struct {
+	decayInterval time.Duration
+
+	mtx       sync.Mutex
+	counts    map[addr.IA]uint64
+	lastDecay time.Time
+}
+
+// New creates a tracker whose counts are halved every decayInterval.
+func New(decayInterval time.Duration) *Tracker {
+	return &Tracker{
+		decayInterval: decayInterval,
+		counts:        make(map[addr.IA]uint64),
+		lastDecay:     time.Now(),
+	}
+}
+
+// Record records a request for dst.
+func (t *Tracker) Record(dst addr.IA) {
+	if t == nil {
+		return
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.decay(time.Now())
+	t.counts[dst]++
+	metrics.tracked.Set(float64(len(t.counts)))
+}
+
+// Top returns up to n destinations ordered by decreasing popularity.
+func (t *Tracker) Top(n int) []addr.IA {
+	if t == nil || n <= 0 {
+		return nil
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.decay(time.Now())
+	dsts := make([]addr.IA, 0, len(t.counts))
+	for ia := range t.counts {
+		dsts = append(dsts, ia)
+	}
+	sort.Slice(dsts, func(i, j int) bool {
+		if t.counts[dsts[i]] != t.counts[dsts[j]] {
+			return t.counts[dsts[i]] > t.counts[dsts[j]]
+		}
+		return dsts[i].IAInt() < dsts[j].IAInt()
+	})
+	if len(dsts) > n {
+		dsts = dsts[:n]
+	}
+	return dsts
+}
+
+// decay halves the counts once for every decay interval that passed since the
+// last decay. The caller must hold the lock.
+func (t *Tracker) decay(now time.Time) {
+	if t.decayInterval <= 0 {
+		return
+	}
+	periods := now.Sub(t.lastDecay) / t.decayInterval
+	if periods <= 0 {
+		return
+	}
+	t.lastDecay = t.lastDecay.Add(periods * t.decayInterval)
+	for ia, cnt := range t.counts {
+		if periods >= 64 {
+			cnt = 0
+		} else {
+			cnt >>= uint(periods)
+		}
+		if cnt == 0 {
+			delete(t.counts, ia)
+			continue
+		}
+		t.counts[ia] = cnt
+	}
+	metrics.tracked.Set(float64(len(t.counts)))
+}
@@ -0,0 +1,74 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package popularity
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+var (
+	ia110 = xtest.MustParseIA("1-ff00:0:110")
+	ia111 = xtest.MustParseIA("1-ff00:0:111")
+	ia112 = xtest.MustParseIA("1-ff00:0:112")
+)
+
+func TestTrackerTop(t *testing.T) {
+	Convey("Given a tracker with recorded requests", t, func() {
+		tr := New(time.Hour)
+		for i := 0; i < 3; i++ {
+			tr.Record(ia111)
+		}
+		tr.Record(ia112)
+		tr.Record(ia110)
+		tr.Record(ia110)
+		Convey("Top returns the destinations by decreasing popularity", func() {
+			SoMsg("top", tr.Top(5), ShouldResemble, []addr.IA{ia111, ia110, ia112})
+		})
+		Convey("Top limits the number of destinations", func() {
+			SoMsg("top", tr.Top(2), ShouldResemble, []addr.IA{ia111, ia110})
+			SoMsg("none", tr.Top(0), ShouldBeEmpty)
+		})
+	})
+	Convey("A nil tracker records nothing", t, func() {
+		var tr *Tracker
+		tr.Record(ia111)
+		SoMsg("top", tr.Top(1), ShouldBeEmpty)
+	})
+}
+
+func TestTrackerDecay(t *testing.T) {
+	Convey("Given a tracker with recorded requests", t, func() {
+		tr := New(time.Minute)
+		for i := 0; i < 4; i++ {
+			tr.Record(ia111)
+		}
+		tr.Record(ia112)
+		Convey("After one interval the counts are halved", func() {
+			tr.lastDecay = tr.lastDecay.Add(-time.Minute)
+			SoMsg("top", tr.Top(5), ShouldResemble, []addr.IA{ia111})
+			SoMsg("count", tr.counts[ia111], ShouldEqual, 2)
+		})
+		Convey("After many intervals all destinations are dropped", func() {
+			tr.lastDecay = tr.lastDecay.Add(-100 * time.Minute)
+			SoMsg("top", tr.Top(5), ShouldBeEmpty)
+		})
+	})
+}
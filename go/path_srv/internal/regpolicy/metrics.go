@@ -0,0 +1,34 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package regpolicy
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
+var metrics = newMetrics()
+
+type regMetrics struct {
+	rejected *prometheus.CounterVec
+}
+
+func newMetrics() regMetrics {
+	return regMetrics{
+		rejected: prom.NewCounterVec("path_srv", "registration", "rejected_total",
+			"Number of rejected segment registrations.", []string{"type", "reason"}),
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package regpolicy implements the authorization policy for segment
+// registrations at the path server.
+//
+// A registered segment is rejected if
+//  - its last AS entry does not match the sending AS,
+//  - the registering AS is not allowed to register segments of its type, or
+//  - the registering AS exceeded its registration limit.
+//
+// The first two checks are done by Filter before the segments are verified.
+// The limit is enforced by Limit on the verified segments only, such that
+// unverified registrations cannot use up the limit of another AS.
+package regpolicy
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/path_srv/internal/config"
+	"github.com/scionproto/scion/go/proto"
+)
+
+// Rejection reasons.
+const (
+	ReasonForeignSender = "foreign_sender"
+	ReasonNotAllowed    = "not_allowed"
+	ReasonLimitExceeded = "limit_exceeded"
+	ReasonInvalid       = "invalid"
+)
+
+// RejectError is the error that indicates why a registration was rejected.
+type RejectError struct {
+	// Reason is the rejection reason.
+	Reason string
+	err    error
+}
+
+func (e *RejectError) Error() string {
+	return e.err.Error()
+}
+
+// Policy authorizes segment registrations.
+type Policy struct {
+	cfg config.RegistrationConfig
+
+	mu        sync.Mutex
+	windows   map[addr.IA]*window
+	lastEvict time.Time
+}
+
+type window struct {
+	start time.Time
+	count int
+}
+
+// New creates a new registration policy from the configuration.
+func New(cfg config.RegistrationConfig) *Policy {
+	return &Policy{
+		cfg:     cfg,
+		windows: make(map[addr.IA]*window),
+	}
+}
+
+// Filter returns the segments that the sender is authorized to register. For
+// each rejected segment, the rejection is logged in the metrics and the
+// rejection error is returned. The segments do not count towards the
+// registration limit, see Limit.
+func (p *Policy) Filter(sender addr.IA, recs []*seg.Meta) ([]*seg.Meta, []error) {
+	if p == nil {
+		return recs, nil
+	}
+	return p.filter(recs, func(rec *seg.Meta) *RejectError {
+		return p.Authorize(sender, rec)
+	})
+}
+
+// Limit returns the segments that are within the registration limit of their
+// origin, and counts them towards the limit. It must only be called with
+// verified segments. For each rejected segment, the rejection is logged in the
+// metrics and the rejection error is returned.
+func (p *Policy) Limit(recs []*seg.Meta) ([]*seg.Meta, []error) {
+	if p == nil {
+		return recs, nil
+	}
+	now := time.Now()
+	return p.filter(recs, func(rec *seg.Meta) *RejectError {
+		origin := rec.Segment.LastIA()
+		if !p.count(origin, now) {
+			return &RejectError{
+				Reason: ReasonLimitExceeded,
+				err: common.NewBasicError("Registration limit exceeded", nil,
+					"origin", origin, "limit", p.cfg.MaxSegsPerIA,
+					"interval", p.cfg.LimitInterval),
+			}
+		}
+		return nil
+	})
+}
+
+func (p *Policy) filter(recs []*seg.Meta,
+	check func(*seg.Meta) *RejectError) ([]*seg.Meta, []error) {
+
+	allowed := make([]*seg.Meta, 0, len(recs))
+	var errs []error
+	for _, rec := range recs {
+		if err := check(rec); err != nil {
+			metrics.rejected.WithLabelValues(rec.Type.String(), err.Reason).Inc()
+			errs = append(errs, err)
+			continue
+		}
+		allowed = append(allowed, rec)
+	}
+	return allowed, errs
+}
+
+// Authorize checks that the sender is allowed to register the segment. The
+// registration does not count towards the registration limit.
+func (p *Policy) Authorize(sender addr.IA, rec *seg.Meta) *RejectError {
+	if rec.Segment == nil || len(rec.Segment.ASEntries) == 0 {
+		return &RejectError{
+			Reason: ReasonInvalid,
+			err:    common.NewBasicError("Segment without AS entries", nil),
+		}
+	}
+	origin := rec.Segment.LastIA()
+	if !p.cfg.AllowForeignSender && !origin.Equal(sender) {
+		return &RejectError{
+			Reason: ReasonForeignSender,
+			err: common.NewBasicError("Last AS entry does not match sender", nil,
+				"last", origin, "sender", sender),
+		}
+	}
+	if !matchesAny(p.origins(rec.Type), origin) {
+		return &RejectError{
+			Reason: ReasonNotAllowed,
+			err: common.NewBasicError("Origin not allowed to register segment type", nil,
+				"origin", origin, "type", rec.Type),
+		}
+	}
+	return nil
+}
+
+func (p *Policy) origins(t proto.PathSegType) []addr.IA {
+	switch t {
+	case proto.PathSegType_up:
+		return p.cfg.UpOrigins
+	case proto.PathSegType_down:
+		return p.cfg.DownOrigins
+	case proto.PathSegType_core:
+		return p.cfg.CoreOrigins
+	}
+	return nil
+}
+
+// count counts the registration towards the limit of the origin. The return
+// value indicates whether the registration is within the limit.
+func (p *Policy) count(origin addr.IA, now time.Time) bool {
+	if p.cfg.MaxSegsPerIA == 0 {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.evict(now)
+	w, ok := p.windows[origin]
+	if !ok || now.Sub(w.start) >= p.cfg.LimitInterval.Duration {
+		w = &window{start: now}
+		p.windows[origin] = w
+	}
+	if w.count >= p.cfg.MaxSegsPerIA {
+		return false
+	}
+	w.count++
+	return true
+}
+
+// evict removes the windows that have ended. It runs at most once per
+// interval, which keeps the windows bounded by the origins that registered
+// in the last two intervals. The caller must hold the lock.
+func (p *Policy) evict(now time.Time) {
+	interval := p.cfg.LimitInterval.Duration
+	if now.Sub(p.lastEvict) < interval {
+		return
+	}
+	for ia, w := range p.windows {
+		if now.Sub(w.start) >= interval {
+			delete(p.windows, ia)
+		}
+	}
+	p.lastEvict = now
+}
+
+// matchesAny returns true if the list is empty, or ia matches an entry of the
+// list. The ISD and AS number of the entries can be wildcards.
+func matchesAny(list []addr.IA, ia addr.IA) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, entry := range list {
+		if (entry.I == 0 || entry.I == ia.I) && (entry.A == 0 || entry.A == ia.A) {
+			return true
+		}
+	}
+	return false
+}
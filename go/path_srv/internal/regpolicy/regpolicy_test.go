@@ -0,0 +1,113 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package regpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+	"github.com/scionproto/scion/go/path_srv/internal/config"
+	"github.com/scionproto/scion/go/proto"
+)
+
+var (
+	ia111 = xtest.MustParseIA("1-ff00:0:111")
+	ia112 = xtest.MustParseIA("1-ff00:0:112")
+)
+
+func TestPolicyAuthorize(t *testing.T) {
+	Convey("Given segments registered by 1-ff00:0:111", t, func() {
+		mctrl := gomock.NewController(t)
+		defer mctrl.Finish()
+		g := graph.NewDefaultGraph(mctrl)
+		pseg := g.Beacon([]common.IFIDType{graph.If_120_X_111_B})
+		up := seg.NewMeta(pseg, proto.PathSegType_up)
+		down := seg.NewMeta(pseg, proto.PathSegType_down)
+		cfg := config.RegistrationConfig{}
+		cfg.InitDefaults()
+		Convey("The default policy only checks the sender", func() {
+			p := New(cfg)
+			SoMsg("up", p.Authorize(ia111, up), ShouldBeNil)
+			SoMsg("down", p.Authorize(ia111, down), ShouldBeNil)
+			err := p.Authorize(ia112, up)
+			SoMsg("foreign", err, ShouldNotBeNil)
+			SoMsg("reason", err.Reason, ShouldEqual, ReasonForeignSender)
+		})
+		Convey("Foreign senders can be allowed", func() {
+			cfg.AllowForeignSender = true
+			SoMsg("foreign", New(cfg).Authorize(ia112, up), ShouldBeNil)
+		})
+		Convey("Only allowed origins can register", func() {
+			cfg.UpOrigins = []addr.IA{ia112}
+			cfg.DownOrigins = []addr.IA{{I: 1, A: 0}}
+			p := New(cfg)
+			err := p.Authorize(ia111, up)
+			SoMsg("up", err, ShouldNotBeNil)
+			SoMsg("reason", err.Reason, ShouldEqual, ReasonNotAllowed)
+			SoMsg("down wildcard", p.Authorize(ia111, down), ShouldBeNil)
+		})
+		Convey("The number of verified registrations is limited", func() {
+			cfg.MaxSegsPerIA = 2
+			p := New(cfg)
+			// Authorizing does not count towards the limit.
+			for i := 0; i < 3; i++ {
+				SoMsg("authorize", p.Authorize(ia111, up), ShouldBeNil)
+			}
+			allowed, errs := p.Limit([]*seg.Meta{up, down, up})
+			SoMsg("allowed", allowed, ShouldResemble, []*seg.Meta{up, down})
+			SoMsg("errs", len(errs), ShouldEqual, 1)
+			SoMsg("reason", errs[0].(*RejectError).Reason, ShouldEqual, ReasonLimitExceeded)
+			// After the interval, the limit is reset.
+			SoMsg("next interval", p.count(ia111, time.Now().Add(cfg.LimitInterval.Duration)),
+				ShouldBeTrue)
+		})
+		Convey("Ended windows are evicted", func() {
+			cfg.MaxSegsPerIA = 2
+			p := New(cfg)
+			now := time.Now()
+			SoMsg("111", p.count(ia111, now), ShouldBeTrue)
+			SoMsg("112", p.count(ia112, now.Add(cfg.LimitInterval.Duration/2)), ShouldBeTrue)
+			SoMsg("111 later", p.count(ia111, now.Add(cfg.LimitInterval.Duration)),
+				ShouldBeTrue)
+			SoMsg("windows", len(p.windows), ShouldEqual, 2)
+			p.count(ia111, now.Add(2*cfg.LimitInterval.Duration))
+			SoMsg("112 evicted", p.windows, ShouldNotContainKey, ia112)
+			SoMsg("windows evicted", len(p.windows), ShouldEqual, 1)
+		})
+		Convey("Filter returns the authorized segments", func() {
+			cfg.UpOrigins = []addr.IA{ia112}
+			allowed, errs := New(cfg).Filter(ia111, []*seg.Meta{up, down})
+			SoMsg("allowed", allowed, ShouldResemble, []*seg.Meta{down})
+			SoMsg("errs", len(errs), ShouldEqual, 1)
+		})
+		Convey("A nil policy allows everything", func() {
+			var p *Policy
+			allowed, errs := p.Filter(ia112, []*seg.Meta{up, down})
+			SoMsg("allowed", len(allowed), ShouldEqual, 2)
+			SoMsg("errs", errs, ShouldBeEmpty)
+			allowed, errs = p.Limit([]*seg.Meta{up, down})
+			SoMsg("limit allowed", len(allowed), ShouldEqual, 2)
+			SoMsg("limit errs", errs, ShouldBeEmpty)
+		})
+	})
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/scionproto/scion/go/lib/env"
 	"github.com/scionproto/scion/go/lib/fatal"
 	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/infra/dedupe"
 	"github.com/scionproto/scion/go/lib/infra/infraenv"
 	"github.com/scionproto/scion/go/lib/infra/messenger"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery"
@@ -43,10 +44,13 @@ import (
 	"github.com/scionproto/scion/go/lib/periodic"
 	"github.com/scionproto/scion/go/lib/revcache"
 	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/tracing"
 	"github.com/scionproto/scion/go/path_srv/internal/config"
 	"github.com/scionproto/scion/go/path_srv/internal/cryptosyncer"
 	"github.com/scionproto/scion/go/path_srv/internal/handlers"
 	"github.com/scionproto/scion/go/path_srv/internal/metrics"
+	"github.com/scionproto/scion/go/path_srv/internal/popularity"
+	"github.com/scionproto/scion/go/path_srv/internal/regpolicy"
 	"github.com/scionproto/scion/go/path_srv/internal/segsyncer"
 	"github.com/scionproto/scion/go/proto"
 )
@@ -85,6 +89,11 @@ func realMain() int {
 		log.Crit("Setup failed", "err", err)
 		return 1
 	}
+	if err := cfg.Tracing.InitGlobalTracer(cfg.General.ID); err != nil {
+		log.Crit("Unable to initialize tracing", "err", err)
+		return 1
+	}
+	defer tracing.Close()
 	pathDB, revCache, err := pathstorage.NewPathStorage(cfg.PS.PathDB, cfg.PS.RevCache)
 	if err != nil {
 		log.Crit("Unable to initialize path storage", "err", err)
@@ -118,6 +127,11 @@ func realMain() int {
 		log.Crit("Unable to find topo address")
 		return 1
 	}
+	admission, err := infraenv.NewAdmissionConfig(&cfg.Admission)
+	if err != nil {
+		log.Crit("Unable to create admission config", "err", err)
+		return 1
+	}
 	nc := infraenv.NetworkConfig{
 		IA:                    topo.ISD_AS,
 		Public:                env.GetPublicSnetAddress(topo.ISD_AS, topoAddress),
@@ -132,6 +146,7 @@ func realMain() int {
 		SVCResolutionFraction: cfg.QUIC.ResolutionFraction,
 		TrustStore:            trustStore,
 		SVCRouter:             messenger.NewSVCRouter(itopo.Provider()),
+		Admission:             admission,
 	}
 	msger, err := nc.Messenger()
 	if err != nil {
@@ -148,8 +163,12 @@ func realMain() int {
 		TrustStore: trustStore,
 		Config:     cfg.PS,
 		IA:         topo.ISD_AS,
+		RegPolicy:  regpolicy.New(cfg.PS.Registration),
 	}
 	core := topo.Core
+	if !core && cfg.PS.Prefetch.MaxDestinations > 0 {
+		args.Popularity = popularity.New(cfg.PS.Prefetch.PopularityDecay.Duration)
+	}
 	var segReqHandler infra.Handler
 	deduper := handlers.NewGetSegsDeduper(msger)
 	if core {
@@ -175,6 +194,7 @@ func realMain() int {
 		args:    args,
 		msger:   msger,
 		trustDB: trustDB,
+		deduper: deduper,
 	}
 	tasks.Start()
 	defer tasks.Kill()
@@ -191,12 +211,15 @@ type periodicTasks struct {
 	args          handlers.HandlerArgs
 	msger         infra.Messenger
 	trustDB       trustdb.TrustDB
+	deduper       dedupe.Deduper
 	mtx           sync.Mutex
 	running       bool
 	segSyncers    []*periodic.Runner
 	pathDBCleaner *periodic.Runner
 	cryptosyncer  *periodic.Runner
 	rcCleaner     *periodic.Runner
+	revSharing    []*periodic.Runner
+	prefetcher    *periodic.Runner
 	discovery     idiscovery.Runners
 }
 
@@ -229,6 +252,13 @@ func (t *periodicTasks) Start() {
 	}, periodic.NewTicker(cfg.PS.CryptoSyncInterval.Duration), cfg.PS.CryptoSyncInterval.Duration)
 	t.rcCleaner = periodic.StartPeriodicTask(revcache.NewCleaner(t.args.RevCache),
 		periodic.NewTicker(10*time.Second), 10*time.Second)
+	t.revSharing = pathstorage.StartRevCacheSharing(cfg.PS.RevCache, t.args.RevCache)
+	if t.args.Popularity != nil {
+		t.prefetcher = periodic.StartPeriodicTask(
+			handlers.NewPrefetcher(t.args, t.deduper),
+			periodic.NewTicker(cfg.PS.Prefetch.Interval.Duration),
+			cfg.PS.Prefetch.Interval.Duration)
+	}
 	t.running = true
 }
 
@@ -247,6 +277,13 @@ func (t *periodicTasks) Kill() {
 	t.pathDBCleaner.Kill()
 	t.cryptosyncer.Kill()
 	t.rcCleaner.Kill()
+	for _, r := range t.revSharing {
+		r.Kill()
+	}
+	if t.prefetcher != nil {
+		t.prefetcher.Kill()
+		t.prefetcher = nil
+	}
 	t.running = false
 }
 
@@ -17,8 +17,11 @@ package config
 
 import (
 	"io"
+	"net"
+	"path/filepath"
 	"time"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/env"
@@ -41,6 +44,7 @@ type Config struct {
 	Logging   env.Logging
 	Metrics   env.Metrics
 	QUIC      env.QUIC `toml:"quic"`
+	Tracing   env.Tracing
 	TrustDB   truststorage.TrustDBConf
 	Discovery idiscovery.Config
 	SD        SDConfig
@@ -51,6 +55,7 @@ func (cfg *Config) InitDefaults() {
 		&cfg.General,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.Tracing,
 		&cfg.TrustDB,
 		&cfg.Discovery,
 		&cfg.SD,
@@ -62,6 +67,7 @@ func (cfg *Config) Validate() error {
 		&cfg.General,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.Tracing,
 		&cfg.TrustDB,
 		&cfg.Discovery,
 		&cfg.SD,
@@ -73,6 +79,7 @@ func (cfg *Config) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 		&cfg.General,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.Tracing,
 		&cfg.TrustDB,
 		&cfg.Discovery,
 		&cfg.SD,
@@ -92,6 +99,10 @@ type SDConfig struct {
 	// Address to listen on for normal unixgram messages. If empty, a
 	// unixgram server on the default socket is started.
 	Unix string
+	// HTTP is the address to serve the HTTP/JSON API on. It is either the
+	// absolute path of a unix socket, or a loopback TCP address. If empty,
+	// the HTTP/JSON API is disabled.
+	HTTP string
 	// If set to True, the socket is removed before being created
 	DeleteSocket bool
 	// Public is the local address to listen on for SCION messages (if Bind is
@@ -107,6 +118,11 @@ type SDConfig struct {
 	// QueryInterval specifies after how much time segments
 	// for a destination should be refetched.
 	QueryInterval util.DurWrap
+	// Prefetch lists the destinations whose segments are fetched at startup
+	// and kept fresh in the background. Destinations are ISD-AS identifiers
+	// or ISD wildcards (e.g., "1-0"), which prefetch the segments to the core
+	// ASes of the ISD.
+	Prefetch []addr.IA
 }
 
 func (cfg *SDConfig) InitDefaults() {
@@ -132,9 +148,38 @@ func (cfg *SDConfig) Validate() error {
 	if cfg.QueryInterval.Duration == 0 {
 		return common.NewBasicError("QueryInterval must not be zero", nil)
 	}
+	if err := cfg.validateHTTP(); err != nil {
+		return err
+	}
+	for _, ia := range cfg.Prefetch {
+		if ia.I == 0 {
+			return common.NewBasicError("Prefetch destination must have an ISD", nil,
+				"ia", ia)
+		}
+	}
 	return config.ValidateAll(&cfg.PathDB, &cfg.RevCache)
 }
 
+// validateHTTP checks that the HTTP/JSON API is not reachable from remote
+// hosts.
+func (cfg *SDConfig) validateHTTP() error {
+	if cfg.HTTP == "" || filepath.IsAbs(cfg.HTTP) {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(cfg.HTTP)
+	if err != nil {
+		return common.NewBasicError("Invalid HTTP address", err, "addr", cfg.HTTP)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return common.NewBasicError("HTTP address must be a unix socket or loopback address",
+			nil, "addr", cfg.HTTP)
+	}
+	return nil
+}
+
 func (cfg *SDConfig) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
 	config.WriteString(dst, sdSample)
 	config.WriteSample(dst, path, ctx, &cfg.PathDB, &cfg.RevCache)
@@ -151,5 +196,10 @@ func (cfg *SDConfig) CreateSocketDirs() error {
 	if err := util.CreateParentDirs(cfg.Unix); err != nil {
 		return common.NewBasicError("Cannot create unix socket dir", err)
 	}
+	if filepath.IsAbs(cfg.HTTP) {
+		if err := util.CreateParentDirs(cfg.HTTP); err != nil {
+			return common.NewBasicError("Cannot create HTTP socket dir", err)
+		}
+	}
 	return nil
 }
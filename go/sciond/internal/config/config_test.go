@@ -21,11 +21,13 @@ import (
 	"github.com/BurntSushi/toml"
 	. "github.com/smartystreets/goconvey/convey"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/env/envtest"
 	"github.com/scionproto/scion/go/lib/infra/modules/idiscovery/idiscoverytest"
 	"github.com/scionproto/scion/go/lib/pathstorage/pathstoragetest"
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/truststorage/truststoragetest"
+	"github.com/scionproto/scion/go/lib/xtest"
 )
 
 func TestConfigSample(t *testing.T) {
@@ -42,6 +44,28 @@ func TestConfigSample(t *testing.T) {
 	})
 }
 
+func TestSDConfigValidateHTTP(t *testing.T) {
+	testCases := []struct {
+		HTTP  string
+		Valid bool
+	}{
+		{HTTP: "", Valid: true},
+		{HTTP: "/run/shm/sciond/default-http.sock", Valid: true},
+		{HTTP: "127.0.0.1:30255", Valid: true},
+		{HTTP: "[::1]:30255", Valid: true},
+		{HTTP: "localhost:30255", Valid: true},
+		{HTTP: "0.0.0.0:30255", Valid: false},
+		{HTTP: "192.0.2.1:30255", Valid: false},
+		{HTTP: "relative.sock", Valid: false},
+	}
+	Convey("Only local HTTP addresses are valid", t, func() {
+		for _, tc := range testCases {
+			cfg := SDConfig{HTTP: tc.HTTP}
+			SoMsg(tc.HTTP, cfg.validateHTTP() == nil, ShouldEqual, tc.Valid)
+		}
+	})
+}
+
 func InitTestConfig(cfg *Config) {
 	envtest.InitTest(&cfg.General, &cfg.Logging, &cfg.Metrics, nil)
 	truststoragetest.InitTestConfig(&cfg.TrustDB)
@@ -67,8 +91,13 @@ func CheckTestSDConfig(cfg *SDConfig, id string) {
 	pathstoragetest.CheckTestRevCacheConf(&cfg.RevCache)
 	SoMsg("Reliable correct", cfg.Reliable, ShouldEqual, sciond.DefaultSCIONDPath)
 	SoMsg("Unix correct", cfg.Unix, ShouldEqual, "/run/shm/sciond/default-unix.sock")
+	SoMsg("HTTP disabled", cfg.HTTP, ShouldBeEmpty)
 	SoMsg("Public correct", cfg.Public.String(), ShouldEqual,
 		"1-ff00:0:110,[127.0.0.1]:0 (UDP)")
 	SoMsg("QueryInterval correct", cfg.QueryInterval.Duration, ShouldEqual, DefaultQueryInterval)
 	SoMsg("DeleteSocket set", cfg.DeleteSocket, ShouldBeFalse)
+	SoMsg("Prefetch correct", cfg.Prefetch, ShouldResemble, []addr.IA{
+		xtest.MustParseIA("1-ff00:0:111"),
+		xtest.MustParseIA("2-0"),
+	})
 }
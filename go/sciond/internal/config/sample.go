@@ -25,6 +25,10 @@ Reliable = "/run/shm/sciond/default.sock"
 # unixgram server on the default socket is started.
 Unix = "/run/shm/sciond/default-unix.sock"
 
+# Address to serve the HTTP/JSON API on. Either the absolute path of a unix
+# socket, or a loopback TCP address. If empty, the HTTP/JSON API is disabled.
+# HTTP = "/run/shm/sciond/default-http.sock"
+
 # If set to True, the socket is removed before being created. (default false)
 DeleteSocket = false
 
@@ -38,4 +42,9 @@ Public = "1-ff00:0:110,[127.0.0.1]:0"
 
 # The time after which segments for a destination are refetched. (default 5m)
 QueryInterval = "5m"
+
+# Destinations whose segments are fetched at startup and kept fresh in the
+# background. Destinations are ISD-AS identifiers or ISD wildcards (e.g.,
+# "1-0"), which prefetch the segments to the core ASes of the ISD.
+Prefetch = ["1-ff00:0:111", "2-0"]
 `
@@ -56,6 +56,7 @@ type Fetcher struct {
 	trustStore      infra.TrustStore
 	revocationCache revcache.RevCache
 	config          config.SDConfig
+	onInsert        func()
 }
 
 func NewFetcher(messenger infra.Messenger, pathDB pathdb.PathDB, trustStore infra.TrustStore,
@@ -70,6 +71,13 @@ func NewFetcher(messenger infra.Messenger, pathDB pathdb.PathDB, trustStore infr
 	}
 }
 
+// OnSegmentsInserted registers fn to be called whenever segments fetched from
+// the network are inserted or updated in the path database. It must be called
+// before the fetcher is used.
+func (f *Fetcher) OnSegmentsInserted(fn func()) {
+	f.onInsert = fn
+}
+
 func (f *Fetcher) GetPaths(ctx context.Context, req *sciond.PathReq,
 	earlyReplyInterval time.Duration, logger log.Logger) (*sciond.PathReply, error) {
 
@@ -422,15 +430,16 @@ func (f *fetcherHandler) shouldRefetchSegs(ctx context.Context,
 
 // fetchAndVerify downloads path segments from the network. Segments that are
 // successfully verified are added to the pathDB. Revocations that are
-// successfully verified are added to the revocation cache.
+// successfully verified are added to the revocation cache. An error is
+// returned if the segments could not be downloaded.
 func (f *fetcherHandler) fetchAndVerify(ctx context.Context, cancelF context.CancelFunc,
-	req *sciond.PathReq, earlyTrigger *util.Trigger, ps *snet.Addr) {
+	req *sciond.PathReq, earlyTrigger *util.Trigger, ps *snet.Addr) error {
 
 	defer cancelF()
 	reply, err := f.getSegmentsFromNetwork(ctx, req, ps)
 	if err != nil {
 		f.logger.Error("Unable to retrieve paths from network", "err", err)
-		return
+		return err
 	}
 	timer := earlyTrigger.Arm()
 	// Cleanup early reply goroutine if function exits early
@@ -471,7 +480,11 @@ func (f *fetcherHandler) fetchAndVerify(ctx context.Context, cancelF context.Can
 		verifiedSeg, verifiedRev, segErr, revErr)
 	if len(insertedSegmentIDs) > 0 {
 		f.logger.Debug("Segments inserted in DB", "segments", insertedSegmentIDs)
+		if f.onInsert != nil {
+			f.onInsert()
+		}
 	}
+	return nil
 }
 
 func (f *fetcherHandler) getSegmentsFromNetwork(ctx context.Context,
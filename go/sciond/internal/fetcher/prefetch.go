@@ -0,0 +1,148 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/infra/modules/combinator"
+	"github.com/scionproto/scion/go/lib/infra/modules/itopo"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/periodic"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/sciond/internal/metrics"
+)
+
+const (
+	// DefaultPrefetchInterval is the interval at which the freshness of the
+	// prefetched destinations is checked.
+	DefaultPrefetchInterval = 10 * time.Second
+	// DefaultPrefetchLead is how long before the earliest cached path to a
+	// prefetched destination expires its segments are fetched again.
+	DefaultPrefetchLead = 10 * time.Minute
+	// DefaultPrefetchTimeout is the time allocated to fetching the segments
+	// of a single destination.
+	DefaultPrefetchTimeout = 10 * time.Second
+	// minPrefetchInterval rate limits the fetches for a single destination,
+	// e.g., if the path server has no fresher segments.
+	minPrefetchInterval = time.Minute
+)
+
+var _ periodic.Task = (*Prefetcher)(nil)
+
+// Prefetcher fetches the segments to a set of destinations, and fetches them
+// again whenever the next query is due or the earliest cached path is about
+// to expire. Path requests to these destinations are thus answered from the
+// path database, without waiting for network lookups.
+type Prefetcher struct {
+	fetcher   *Fetcher
+	dsts      []addr.IA
+	metrics   *metrics.Prefetch
+	lastFetch map[addr.IA]time.Time
+}
+
+// NewPrefetcher creates a prefetcher for dsts. Destinations can be ISD
+// wildcards, in which case the segments to the core ASes of the ISD are
+// fetched. The prefetcher is meant to be run periodically, e.g., every
+// DefaultPrefetchInterval.
+func NewPrefetcher(f *Fetcher, dsts []addr.IA) *Prefetcher {
+	return &Prefetcher{
+		fetcher:   f,
+		dsts:      dsts,
+		metrics:   metrics.InitPrefetch(),
+		lastFetch: make(map[addr.IA]time.Time),
+	}
+}
+
+// Run fetches the segments of all destinations that are not fresh.
+func (p *Prefetcher) Run(ctx context.Context) {
+	handler := &fetcherHandler{
+		Fetcher:  p.fetcher,
+		topology: itopo.Get(),
+		logger:   log.FromCtx(ctx),
+	}
+	for _, dst := range p.dsts {
+		if ctx.Err() != nil {
+			return
+		}
+		p.prefetch(ctx, handler, dst)
+	}
+}
+
+func (p *Prefetcher) prefetch(ctx context.Context, handler *fetcherHandler, dst addr.IA) {
+	req := &sciond.PathReq{
+		Src: handler.topology.ISD_AS.IAInt(),
+		Dst: dst.IAInt(),
+	}
+	if p.fresh(ctx, handler, req) {
+		return
+	}
+	if time.Since(p.lastFetch[dst]) < minPrefetchInterval {
+		return
+	}
+	p.lastFetch[dst] = time.Now()
+	handler.logger.Debug("[Prefetcher] Fetching segments", "dst", dst)
+	fetchCtx, cancelF := context.WithTimeout(ctx, DefaultPrefetchTimeout)
+	ps := &snet.Addr{IA: handler.topology.ISD_AS, Host: addr.NewSVCUDPAppAddr(addr.SvcPS)}
+	err := handler.fetchAndVerify(fetchCtx, cancelF, req, util.NewTrigger(0), ps)
+	if err != nil {
+		p.metrics.IncFetches(dst, metrics.FetchErr)
+		return
+	}
+	p.metrics.IncFetches(dst, metrics.Success)
+	_, err = p.fetcher.pathDB.InsertNextQuery(ctx, dst,
+		time.Now().Add(p.fetcher.config.QueryInterval.Duration))
+	if err != nil {
+		handler.logger.Warn("[Prefetcher] Failed to update nextQuery", "err", err)
+	}
+	p.fresh(ctx, handler, req)
+}
+
+// fresh returns true if the cached paths for req can be used to answer path
+// requests without fetching segments. It updates the freshness metrics of the
+// destination.
+func (p *Prefetcher) fresh(ctx context.Context, handler *fetcherHandler,
+	req *sciond.PathReq) bool {
+
+	refetch, err := handler.shouldRefetchSegs(ctx, req)
+	if err != nil {
+		handler.logger.Warn("[Prefetcher] Failed to check if refetch is required", "err", err)
+	}
+	paths, err := handler.buildPathsFromDB(ctx, req)
+	if err != nil {
+		// Expected before the first fetch, e.g., if the TRC of the
+		// destination is not known yet.
+		handler.logger.Debug("[Prefetcher] Unable to build paths", "dst", req.Dst, "err", err)
+	}
+	expiry := earliestExpiry(paths)
+	p.metrics.SetPaths(req.Dst.IA(), len(paths), expiry)
+	return !refetch && len(paths) > 0 && time.Until(expiry) > DefaultPrefetchLead
+}
+
+// earliestExpiry returns the expiration time of the path that expires first,
+// or the zero time if paths is empty.
+func earliestExpiry(paths []*combinator.Path) time.Time {
+	var earliest time.Time
+	for _, path := range paths {
+		if expiry := path.ComputeExpTime(); earliest.IsZero() || expiry.Before(earliest) {
+			earliest = expiry
+		}
+	}
+	return earliest
+}
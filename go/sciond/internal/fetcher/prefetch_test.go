@@ -0,0 +1,223 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/ctrl/seg/mock_seg"
+	"github.com/scionproto/scion/go/lib/infra/mock_infra"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/pathdb/mock_pathdb"
+	"github.com/scionproto/scion/go/lib/pathdb/query"
+	"github.com/scionproto/scion/go/lib/revcache/mock_revcache"
+	"github.com/scionproto/scion/go/lib/scrypto/trc"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+	"github.com/scionproto/scion/go/proto"
+	"github.com/scionproto/scion/go/sciond/internal/config"
+	"github.com/scionproto/scion/go/sciond/internal/metrics"
+)
+
+// staleAge is the age of a segment that expires within DefaultPrefetchLead.
+var staleAge = spath.DefaultHopFExpiry.ToDuration() - DefaultPrefetchLead/2
+
+func TestPrefetcher(t *testing.T) {
+	localIA := xtest.MustParseIA("1-ff00:0:130")
+	coreDst := xtest.MustParseIA("1-ff00:0:120")
+	wildcardDst := xtest.MustParseIA("1-0")
+	Convey("Given a prefetcher in a core AS", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		g := graph.NewDefaultGraph(ctrl)
+		msger := mock_infra.NewMockMessenger(ctrl)
+		pathDB := mock_pathdb.NewMockPathDB(ctrl)
+		revCache := mock_revcache.NewMockRevCache(ctrl)
+		revCache.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+		trustStore := mock_infra.NewMockTrustStore(ctrl)
+		isd1 := &trc.TRC{CoreASes: trc.CoreASMap{
+			xtest.MustParseIA("1-ff00:0:110"): &trc.CoreAS{},
+			coreDst:                           &trc.CoreAS{},
+			localIA:                           &trc.CoreAS{},
+		}}
+		trustStore.EXPECT().GetValidCachedTRC(gomock.Any(), gomock.Any()).
+			Return(isd1, nil).AnyTimes()
+		trustStore.EXPECT().GetValidTRC(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(isd1, nil).AnyTimes()
+		trustStore.EXPECT().NewVerifier().Return(nil).AnyTimes()
+		cfg := config.SDConfig{QueryInterval: util.DurWrap{Duration: time.Minute}}
+		f := NewFetcher(msger, pathDB, trustStore, revCache, cfg, log.Root())
+		topo := topology.NewTopo()
+		topo.ISD_AS = localIA
+		handler := &fetcherHandler{Fetcher: f, topology: topo, logger: log.Root()}
+		ctx := context.Background()
+		nextQuery := time.Now().Add(time.Minute)
+
+		// expectPaths makes the path DB return the given next query time and
+		// the core segment from coreDst to localIA, which was created at ts.
+		expectPaths := func(dst addr.IA, nq *time.Time, ts time.Time, times int) {
+			pathDB.EXPECT().GetNextQuery(gomock.Any(), dst).Return(nq, nil).Times(times)
+			pathDB.EXPECT().Get(gomock.Any(), gomock.Any()).Return(
+				query.Results{{Seg: coreSeg(ctrl, g, ts)}}, nil,
+			).Times(times)
+		}
+		// expectFetch makes the path server reply to a request for the
+		// segments to dst with err.
+		expectFetch := func(dst addr.IA, err error) {
+			req := &path_mgmt.SegReq{RawSrcIA: localIA.IAInt(), RawDstIA: dst.IAInt()}
+			var reply *path_mgmt.SegReply
+			if err == nil {
+				reply = &path_mgmt.SegReply{Recs: &path_mgmt.SegRecs{}}
+			}
+			msger.EXPECT().GetSegs(gomock.Any(), req, gomock.Any(), gomock.Any()).
+				Return(reply, err)
+		}
+
+		Convey("a destination with fresh paths is not fetched", func() {
+			p := NewPrefetcher(f, []addr.IA{coreDst})
+			expectPaths(coreDst, &nextQuery, time.Now(), 1)
+			p.prefetch(ctx, handler, coreDst)
+		})
+		Convey("a destination without paths is fetched", func() {
+			p := NewPrefetcher(f, []addr.IA{coreDst})
+			pathDB.EXPECT().GetNextQuery(gomock.Any(), coreDst).Return(nil, nil)
+			pathDB.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, nil)
+			expectFetch(coreDst, nil)
+			pathDB.EXPECT().InsertNextQuery(gomock.Any(), coreDst, gomock.Any()).
+				Return(true, nil)
+			expectPaths(coreDst, &nextQuery, time.Now(), 1)
+			p.prefetch(ctx, handler, coreDst)
+		})
+		Convey("a destination whose next query is due is fetched", func() {
+			p := NewPrefetcher(f, []addr.IA{coreDst})
+			due := time.Now().Add(-time.Second)
+			expectPaths(coreDst, &due, time.Now(), 1)
+			expectFetch(coreDst, nil)
+			pathDB.EXPECT().InsertNextQuery(gomock.Any(), coreDst, gomock.Any()).
+				Return(true, nil)
+			expectPaths(coreDst, &nextQuery, time.Now(), 1)
+			p.prefetch(ctx, handler, coreDst)
+		})
+		Convey("a destination whose paths expire within the lead time is fetched", func() {
+			p := NewPrefetcher(f, []addr.IA{coreDst})
+			expectPaths(coreDst, &nextQuery, time.Now().Add(-staleAge), 2)
+			expectFetch(coreDst, nil)
+			var inserted time.Time
+			pathDB.EXPECT().InsertNextQuery(gomock.Any(), coreDst, gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ addr.IA, nq time.Time) (bool, error) {
+					inserted = nq
+					return true, nil
+				},
+			)
+			before := fetches(t, coreDst, metrics.Success)
+			p.prefetch(ctx, handler, coreDst)
+			SoMsg("fetches", fetches(t, coreDst, metrics.Success)-before, ShouldEqual, 1)
+			SoMsg("next query", inserted, ShouldHappenWithin,
+				time.Second, time.Now().Add(cfg.QueryInterval.Duration))
+			Convey("but not again within minPrefetchInterval", func() {
+				expectPaths(coreDst, &nextQuery, time.Now().Add(-staleAge), 1)
+				p.prefetch(ctx, handler, coreDst)
+			})
+			Convey("and again after minPrefetchInterval", func() {
+				p.lastFetch[coreDst] = time.Now().Add(-minPrefetchInterval)
+				expectPaths(coreDst, &nextQuery, time.Now().Add(-staleAge), 2)
+				expectFetch(coreDst, nil)
+				pathDB.EXPECT().InsertNextQuery(gomock.Any(), coreDst, gomock.Any()).
+					Return(true, nil)
+				p.prefetch(ctx, handler, coreDst)
+			})
+		})
+		Convey("a failed fetch is counted and does not update the next query", func() {
+			p := NewPrefetcher(f, []addr.IA{coreDst})
+			expectPaths(coreDst, &nextQuery, time.Now().Add(-staleAge), 1)
+			expectFetch(coreDst, errors.New("no path server"))
+			before := fetches(t, coreDst, metrics.FetchErr)
+			p.prefetch(ctx, handler, coreDst)
+			SoMsg("failures", fetches(t, coreDst, metrics.FetchErr)-before, ShouldEqual, 1)
+			Convey("and is not retried within minPrefetchInterval", func() {
+				expectPaths(coreDst, &nextQuery, time.Now().Add(-staleAge), 1)
+				p.prefetch(ctx, handler, coreDst)
+			})
+		})
+		Convey("an ISD wildcard destination with fresh paths to its cores is not fetched",
+			func() {
+				p := NewPrefetcher(f, []addr.IA{wildcardDst})
+				expectPaths(wildcardDst, &nextQuery, time.Now(), 1)
+				p.prefetch(ctx, handler, wildcardDst)
+			},
+		)
+		Convey("an ISD wildcard destination is fetched with the wildcard", func() {
+			p := NewPrefetcher(f, []addr.IA{wildcardDst})
+			expectPaths(wildcardDst, &nextQuery, time.Now().Add(-staleAge), 2)
+			expectFetch(wildcardDst, nil)
+			pathDB.EXPECT().InsertNextQuery(gomock.Any(), wildcardDst, gomock.Any()).
+				Return(true, nil)
+			p.prefetch(ctx, handler, wildcardDst)
+		})
+	})
+}
+
+// coreSeg returns the core segment from 1-ff00:0:120 to 1-ff00:0:130, with
+// the info field timestamp set to ts.
+func coreSeg(ctrl *gomock.Controller, g *graph.Graph, ts time.Time) *seg.PathSegment {
+	beacon := g.Beacon([]common.IFIDType{graph.If_120_A_130_B})
+	s, err := seg.NewSeg(&spath.InfoField{ISD: 1, TsInt: util.TimeToSecs(ts)})
+	if err != nil {
+		panic(err)
+	}
+	signer := mock_seg.NewMockSigner(ctrl)
+	signer.EXPECT().Sign(gomock.Any()).Return(&proto.SignS{}, nil).AnyTimes()
+	for _, entry := range beacon.ASEntries {
+		if err := s.AddASEntry(entry, signer); err != nil {
+			panic(err)
+		}
+	}
+	return s
+}
+
+// fetches returns the value of the prefetch fetch counter for dst and res.
+func fetches(t *testing.T, dst addr.IA, res metrics.Result) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	xtest.FailOnErr(t, err)
+	for _, family := range families {
+		if family.GetName() != "sciond_prefetch_fetches_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["dst"] == dst.String() && labels["result"] == string(res) {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
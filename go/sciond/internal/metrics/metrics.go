@@ -15,6 +15,12 @@
 package metrics
 
 import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/prom"
 )
 
@@ -22,7 +28,78 @@ const (
 	namespace = "sciond"
 )
 
+// Result is the result label of a metric.
+type Result string
+
+const (
+	// Success indicates a successful result.
+	Success Result = "success"
+	// FetchErr indicates an error while fetching segments.
+	FetchErr Result = "fetch_err"
+)
+
+var (
+	prefetchOnce sync.Once
+	prefetch     *Prefetch
+)
+
 // Init initializes the metrics for sciond.
 func Init(elem string) {
 	prom.UseDefaultRegWithElem(elem)
 }
+
+// Prefetch holds the metrics about the freshness of prefetched destinations.
+type Prefetch struct {
+	fetches   prometheus.CounterVec
+	paths     prometheus.GaugeVec
+	expiry    prometheus.GaugeVec
+	lastFetch prometheus.GaugeVec
+}
+
+// InitPrefetch initializes the prefetch metrics and returns a handle.
+func InitPrefetch() *Prefetch {
+	prefetchOnce.Do(func() {
+		prefetch = newPrefetch()
+	})
+	return prefetch
+}
+
+func newPrefetch() *Prefetch {
+	sub := "prefetch"
+	return &Prefetch{
+		fetches: *prom.NewCounterVec(namespace, sub, "fetches_total",
+			"Number of segment fetches for prefetched destinations", []string{"dst", "result"}),
+		paths: *prom.NewGaugeVec(namespace, sub, "paths",
+			"Number of cached paths to the destination", []string{"dst"}),
+		expiry: *prom.NewGaugeVec(namespace, sub, "expiry_seconds",
+			"Time until the earliest cached path to the destination expires", []string{"dst"}),
+		lastFetch: *prom.NewGaugeVec(namespace, sub, "last_fetch_timestamp_seconds",
+			"Time of the last successful segment fetch for the destination", []string{"dst"}),
+	}
+}
+
+// IncFetches increments the fetch count for dst.
+func (m *Prefetch) IncFetches(dst addr.IA, res Result) {
+	if m == nil {
+		return
+	}
+	m.fetches.With(prometheus.Labels{"dst": dst.String(), "result": string(res)}).Inc()
+	if res == Success {
+		m.lastFetch.With(prometheus.Labels{"dst": dst.String()}).SetToCurrentTime()
+	}
+}
+
+// SetPaths sets the number of cached paths to dst, and the expiration time of
+// the earliest of them.
+func (m *Prefetch) SetPaths(dst addr.IA, paths int, expiry time.Time) {
+	if m == nil {
+		return
+	}
+	labels := prometheus.Labels{"dst": dst.String()}
+	m.paths.With(labels).Set(float64(paths))
+	var remaining float64
+	if paths > 0 {
+		remaining = time.Until(expiry).Seconds()
+	}
+	m.expiry.With(labels).Set(remaining)
+}
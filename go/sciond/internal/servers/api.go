@@ -25,6 +25,7 @@ import (
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/tracing"
 	"github.com/scionproto/scion/go/lib/util"
 	"github.com/scionproto/scion/go/proto"
 )
@@ -49,7 +50,14 @@ func NewConnHandler(conn net.PacketConn,
 	}
 }
 
+// connCloser is implemented by handlers that keep per-connection state, which
+// they must drop once the client disconnects.
+type connCloser interface {
+	ConnClosed(conn net.PacketConn)
+}
+
 func (srv *ConnHandler) Serve() error {
+	defer srv.connClosed()
 	for {
 		b := make(common.RawBytes, common.MaxMTU)
 		n, address, err := srv.Conn.ReadFrom(b)
@@ -74,8 +82,21 @@ func (srv *ConnHandler) Handle(b common.RawBytes, address net.Addr) {
 		log.Error("handler not found for capnp message", "which", p.Which)
 		return
 	}
-	ctx := log.CtxWith(context.Background(), srv.Logger.New("debug_id", util.GetDebugID()))
-	handler.Handle(ctx, srv.Conn, address, p)
+	span, ctx := tracing.StartSpan(context.Background(), p.Which.String(), tracing.KindServer)
+	defer span.Finish()
+	logger := srv.Logger.New("debug_id", util.GetDebugID())
+	if span != nil {
+		logger = logger.New("trace_id", span.Context().TraceID)
+	}
+	handler.Handle(log.CtxWith(ctx, logger), srv.Conn, address, p)
+}
+
+func (srv *ConnHandler) connClosed() {
+	for _, handler := range srv.Handlers {
+		if closer, ok := handler.(connCloser); ok {
+			closer.ConnClosed(srv.Conn)
+		}
+	}
 }
 
 func (srv *ConnHandler) Close() error {
@@ -267,6 +267,9 @@ func makeHostInfos(topo *topology.Topo, t proto.ServiceType) []hostinfo.HostInfo
 type RevNotificationHandler struct {
 	RevCache   revcache.RevCache
 	TrustStore infra.TrustStore
+	// Subscriptions, if set, is updated whenever a new revocation is
+	// inserted.
+	Subscriptions *Subscriptions
 }
 
 func (h *RevNotificationHandler) Handle(ctx context.Context, conn net.PacketConn,
@@ -281,10 +284,14 @@ func (h *RevNotificationHandler) Handle(ctx context.Context, conn net.PacketConn
 	revReply := &sciond.RevReply{}
 	revInfo, err := h.verifySRevInfo(workCtx, revNotification.SRevInfo)
 	if err == nil {
-		_, err = h.RevCache.Insert(workCtx, revNotification.SRevInfo)
+		var inserted bool
+		inserted, err = h.RevCache.Insert(workCtx, revNotification.SRevInfo)
 		if err != nil {
 			logger.Error("Failed to insert revocations", "err", err)
 		}
+		if inserted && h.Subscriptions != nil {
+			h.Subscriptions.Update()
+		}
 	}
 	switch {
 	case isValid(err):
@@ -0,0 +1,405 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/hostinfo"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/proto"
+)
+
+const (
+	// HTTPPathsPath is the path of the HTTP endpoint for path requests.
+	HTTPPathsPath = "/paths"
+	// HTTPASInfoPath is the path of the HTTP endpoint for AS info requests.
+	HTTPASInfoPath = "/asinfo"
+	// HTTPIFInfoPath is the path of the HTTP endpoint for interface info
+	// requests.
+	HTTPIFInfoPath = "/ifinfo"
+	// HTTPSVCInfoPath is the path of the HTTP endpoint for service info
+	// requests.
+	HTTPSVCInfoPath = "/svcinfo"
+	// HTTPRevocationPath is the path of the HTTP endpoint for revocation
+	// notifications.
+	HTTPRevocationPath = "/revocation"
+
+	// maxRevocationSize bounds the size of a revocation notification body.
+	maxRevocationSize = 1 << 16
+)
+
+// HTTPHandler serves the SCIOND API over HTTP, with JSON encoded replies.
+// Requests are passed to the same handlers that serve the capnp API, so both
+// APIs answer identically. It serves the following requests:
+//  GET  /paths?dst=1-ff00:0:110[&src=1-ff00:0:111][&max=10][&refresh=true]
+//  GET  /asinfo[?ia=1-ff00:0:110]
+//  GET  /ifinfo[?ifid=1&ifid=2]
+//  GET  /svcinfo?svc=bs[&svc=ps]
+//  POST /revocation     with the packed signed revocation as body.
+type HTTPHandler struct {
+	Handlers HandlerMap
+	Logger   log.Logger
+}
+
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := http.MethodGet
+	if r.URL.Path == HTTPRevocationPath {
+		method = http.MethodPost
+	}
+	if r.Method != method {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var pld *sciond.Pld
+	var err error
+	switch r.URL.Path {
+	case HTTPPathsPath:
+		pld, err = parsePathReq(r)
+	case HTTPASInfoPath:
+		pld, err = parseASInfoReq(r)
+	case HTTPIFInfoPath:
+		pld, err = parseIFInfoReq(r)
+	case HTTPSVCInfoPath:
+		pld, err = parseSVCInfoReq(r)
+	case HTTPRevocationPath:
+		pld, err = parseRevNotification(r)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reply, err := h.handle(r.Context(), pld)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, jsonReply(reply))
+}
+
+// handle passes pld to its handler, and returns the reply the handler wrote.
+func (h *HTTPHandler) handle(ctx context.Context, pld *sciond.Pld) (*sciond.Pld, error) {
+	handler, ok := h.Handlers[pld.Which]
+	if !ok {
+		return nil, common.NewBasicError("Handler not found", nil, "which", pld.Which)
+	}
+	logger := h.Logger.New("debug_id", util.GetDebugID())
+	conn := &replyRecorder{}
+	handler.Handle(log.CtxWith(ctx, logger), conn, nil, pld)
+	if conn.reply == nil {
+		return nil, common.NewBasicError("Handler did not reply", nil, "which", pld.Which)
+	}
+	return sciond.NewPldFromRaw(conn.reply)
+}
+
+func parsePathReq(r *http.Request) (*sciond.Pld, error) {
+	q := r.URL.Query()
+	dst, err := addr.IAFromString(q.Get("dst"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid dst: %q", q.Get("dst"))
+	}
+	var src addr.IA
+	if rawSrc := q.Get("src"); rawSrc != "" {
+		if src, err = addr.IAFromString(rawSrc); err != nil {
+			return nil, fmt.Errorf("invalid src: %q", rawSrc)
+		}
+	}
+	var max uint64
+	if rawMax := q.Get("max"); rawMax != "" {
+		if max, err = strconv.ParseUint(rawMax, 10, 16); err != nil {
+			return nil, fmt.Errorf("invalid max: %q", rawMax)
+		}
+	}
+	var refresh bool
+	if rawRefresh := q.Get("refresh"); rawRefresh != "" {
+		if refresh, err = strconv.ParseBool(rawRefresh); err != nil {
+			return nil, fmt.Errorf("invalid refresh: %q", rawRefresh)
+		}
+	}
+	return &sciond.Pld{
+		Which: proto.SCIONDMsg_Which_pathReq,
+		PathReq: &sciond.PathReq{
+			Dst:      dst.IAInt(),
+			Src:      src.IAInt(),
+			MaxPaths: uint16(max),
+			Flags:    sciond.PathReqFlags{Refresh: refresh},
+		},
+	}, nil
+}
+
+func parseASInfoReq(r *http.Request) (*sciond.Pld, error) {
+	var ia addr.IA
+	if rawIA := r.URL.Query().Get("ia"); rawIA != "" {
+		var err error
+		if ia, err = addr.IAFromString(rawIA); err != nil {
+			return nil, fmt.Errorf("invalid ia: %q", rawIA)
+		}
+	}
+	return &sciond.Pld{
+		Which:     proto.SCIONDMsg_Which_asInfoReq,
+		AsInfoReq: &sciond.ASInfoReq{Isdas: ia.IAInt()},
+	}, nil
+}
+
+func parseIFInfoReq(r *http.Request) (*sciond.Pld, error) {
+	req := &sciond.IFInfoRequest{}
+	for _, rawIfid := range r.URL.Query()["ifid"] {
+		ifid, err := strconv.ParseUint(rawIfid, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ifid: %q", rawIfid)
+		}
+		req.IfIDs = append(req.IfIDs, common.IFIDType(ifid))
+	}
+	return &sciond.Pld{
+		Which:         proto.SCIONDMsg_Which_ifInfoRequest,
+		IfInfoRequest: req,
+	}, nil
+}
+
+func parseSVCInfoReq(r *http.Request) (*sciond.Pld, error) {
+	req := &sciond.ServiceInfoRequest{}
+	for _, rawSvc := range r.URL.Query()["svc"] {
+		svc := proto.ServiceTypeFromString(strings.ToLower(rawSvc))
+		if svc == proto.ServiceType_unset {
+			return nil, fmt.Errorf("invalid svc: %q", rawSvc)
+		}
+		req.ServiceTypes = append(req.ServiceTypes, svc)
+	}
+	if len(req.ServiceTypes) == 0 {
+		return nil, fmt.Errorf("missing svc")
+	}
+	return &sciond.Pld{
+		Which:              proto.SCIONDMsg_Which_serviceInfoRequest,
+		ServiceInfoRequest: req,
+	}, nil
+}
+
+func parseRevNotification(r *http.Request) (*sciond.Pld, error) {
+	b, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, maxRevocationSize))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read revocation: %v", err)
+	}
+	sRevInfo, err := path_mgmt.NewSignedRevInfoFromRaw(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid revocation: %v", err)
+	}
+	return &sciond.Pld{
+		Which:           proto.SCIONDMsg_Which_revNotification,
+		RevNotification: &sciond.RevNotification{SRevInfo: sRevInfo},
+	}, nil
+}
+
+// PathsJSON is the JSON representation of a path reply.
+type PathsJSON struct {
+	ErrorCode string
+	Paths     []PathJSON
+}
+
+// PathJSON is the JSON representation of a path reply entry.
+type PathJSON struct {
+	Hops   []HopJSON
+	MTU    uint16
+	Expiry time.Time
+	// NextHop is the address of the first-hop border router.
+	NextHop string
+	// FwdPath is the raw forwarding path.
+	FwdPath []byte
+}
+
+// HopJSON is an interface on a path.
+type HopJSON struct {
+	IA   addr.IA
+	IfID common.IFIDType
+}
+
+// ASInfoJSON is the JSON representation of an AS info reply entry.
+type ASInfoJSON struct {
+	IA     addr.IA
+	MTU    uint16
+	IsCore bool
+}
+
+// IFInfoJSON is the JSON representation of an interface info reply entry.
+type IFInfoJSON struct {
+	IfID    common.IFIDType
+	Address string
+}
+
+// SVCInfoJSON is the JSON representation of a service info reply entry.
+type SVCInfoJSON struct {
+	Service   string
+	TTL       uint32
+	Addresses []string
+}
+
+// RevocationJSON is the JSON representation of a revocation reply.
+type RevocationJSON struct {
+	Result string
+}
+
+// jsonReply converts the reply of a SCIOND handler to its JSON
+// representation.
+func jsonReply(pld *sciond.Pld) interface{} {
+	switch pld.Which {
+	case proto.SCIONDMsg_Which_pathReply:
+		return pathsJSON(pld.PathReply)
+	case proto.SCIONDMsg_Which_asInfoReply:
+		entries := make([]ASInfoJSON, 0, len(pld.AsInfoReply.Entries))
+		for _, entry := range pld.AsInfoReply.Entries {
+			entries = append(entries, ASInfoJSON{
+				IA:     entry.ISD_AS(),
+				MTU:    entry.Mtu,
+				IsCore: entry.IsCore,
+			})
+		}
+		return entries
+	case proto.SCIONDMsg_Which_ifInfoReply:
+		entries := make([]IFInfoJSON, 0, len(pld.IfInfoReply.RawEntries))
+		for _, entry := range pld.IfInfoReply.RawEntries {
+			entries = append(entries, IFInfoJSON{
+				IfID:    entry.IfID,
+				Address: hostAddr(entry.HostInfo),
+			})
+		}
+		return entries
+	case proto.SCIONDMsg_Which_serviceInfoReply:
+		entries := make([]SVCInfoJSON, 0, len(pld.ServiceInfoReply.Entries))
+		for _, entry := range pld.ServiceInfoReply.Entries {
+			addrs := make([]string, 0, len(entry.HostInfos))
+			for _, hostInfo := range entry.HostInfos {
+				addrs = append(addrs, hostAddr(hostInfo))
+			}
+			entries = append(entries, SVCInfoJSON{
+				Service:   entry.ServiceType.String(),
+				TTL:       entry.Ttl,
+				Addresses: addrs,
+			})
+		}
+		return entries
+	case proto.SCIONDMsg_Which_revReply:
+		return RevocationJSON{Result: pld.RevReply.Result.String()}
+	}
+	return nil
+}
+
+func pathsJSON(reply *sciond.PathReply) PathsJSON {
+	paths := PathsJSON{
+		ErrorCode: reply.ErrorCode.String(),
+		Paths:     make([]PathJSON, 0, len(reply.Entries)),
+	}
+	for _, entry := range reply.Entries {
+		if entry.Path == nil {
+			continue
+		}
+		hops := make([]HopJSON, 0, len(entry.Path.Interfaces))
+		for _, intf := range entry.Path.Interfaces {
+			hops = append(hops, HopJSON{IA: intf.ISD_AS(), IfID: intf.IfID})
+		}
+		paths.Paths = append(paths.Paths, PathJSON{
+			Hops:    hops,
+			MTU:     entry.Path.Mtu,
+			Expiry:  entry.Path.Expiry(),
+			NextHop: hostAddr(entry.HostInfo),
+			FwdPath: entry.Path.FwdPath,
+		})
+	}
+	return paths
+}
+
+// hostAddr formats the address in hostInfo as host:port. It returns the empty
+// string if hostInfo contains no address.
+func hostAddr(hostInfo hostinfo.HostInfo) string {
+	host := hostInfo.Host()
+	if host == nil {
+		return ""
+	}
+	return net.JoinHostPort(host.IP().String(), strconv.Itoa(int(hostInfo.Port)))
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(v); err != nil {
+		log.Error("[HTTPHandler] Unable to write response", "err", err)
+	}
+}
+
+// ListenAndServeHTTP serves handler on address. If address is an absolute
+// path, handler is served on a unix socket at that path. Otherwise, address
+// is a TCP host:port.
+func ListenAndServeHTTP(address string, handler http.Handler) error {
+	network := "tcp"
+	if strings.HasPrefix(address, "/") {
+		network = "unix"
+	}
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return common.NewBasicError("unable to listen on socket", err, "address", address)
+	}
+	return http.Serve(listener, handler)
+}
+
+var _ net.PacketConn = (*replyRecorder)(nil)
+
+// replyRecorder is a net.PacketConn that records the reply a handler writes.
+type replyRecorder struct {
+	reply common.RawBytes
+}
+
+func (c *replyRecorder) ReadFrom(b []byte) (int, net.Addr, error) {
+	return 0, nil, common.NewBasicError("read not supported", nil)
+}
+
+func (c *replyRecorder) WriteTo(b []byte, _ net.Addr) (int, error) {
+	c.reply = append(common.RawBytes(nil), b...)
+	return len(b), nil
+}
+
+func (c *replyRecorder) Close() error {
+	return nil
+}
+
+func (c *replyRecorder) LocalAddr() net.Addr {
+	return nil
+}
+
+func (c *replyRecorder) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *replyRecorder) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *replyRecorder) SetWriteDeadline(t time.Time) error {
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/hostinfo"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/proto"
+)
+
+// pathHandler replies to path requests with a single path.
+type pathHandler struct {
+	req *sciond.PathReq
+}
+
+func (h *pathHandler) Handle(_ context.Context, conn net.PacketConn, src net.Addr,
+	pld *sciond.Pld) {
+
+	h.req = pld.PathReq
+	reply := &sciond.PathReply{
+		ErrorCode: sciond.ErrorOk,
+		Entries: []sciond.PathReplyEntry{
+			{
+				Path: &sciond.FwdPathMeta{
+					FwdPath: []byte{1, 2, 3},
+					Mtu:     1472,
+					Interfaces: []sciond.PathInterface{
+						{RawIsdas: xtest.MustParseIA("1-ff00:0:111").IAInt(), IfID: 1},
+						{RawIsdas: xtest.MustParseIA("1-ff00:0:110").IAInt(), IfID: 2},
+					},
+					ExpTime: 1000,
+				},
+				HostInfo: *hostinfo.FromHostAddr(addr.HostFromIPStr("10.0.0.1"), 30041),
+			},
+		},
+	}
+	b, err := proto.PackRoot(&sciond.Pld{
+		Id:        pld.Id,
+		Which:     proto.SCIONDMsg_Which_pathReply,
+		PathReply: reply,
+	})
+	if err != nil {
+		panic(err)
+	}
+	conn.WriteTo(b, src)
+}
+
+func TestHTTPHandler(t *testing.T) {
+	Convey("Given an HTTP handler", t, func() {
+		paths := &pathHandler{}
+		handler := &HTTPHandler{
+			Handlers: HandlerMap{proto.SCIONDMsg_Which_pathReq: paths},
+			Logger:   log.Root(),
+		}
+		serve := func(method, target string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+			return w
+		}
+		Convey("a path request is answered with JSON", func() {
+			w := serve(http.MethodGet, "/paths?dst=1-ff00:0:110&max=5&refresh=true")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			SoMsg("dst", paths.req.Dst.IA(), ShouldResemble, xtest.MustParseIA("1-ff00:0:110"))
+			SoMsg("max", paths.req.MaxPaths, ShouldEqual, 5)
+			SoMsg("refresh", paths.req.Flags.Refresh, ShouldBeTrue)
+			var reply PathsJSON
+			So(json.Unmarshal(w.Body.Bytes(), &reply), ShouldBeNil)
+			SoMsg("code", reply.ErrorCode, ShouldEqual, sciond.ErrorOk.String())
+			So(len(reply.Paths), ShouldEqual, 1)
+			path := reply.Paths[0]
+			SoMsg("hops", path.Hops, ShouldResemble, []HopJSON{
+				{IA: xtest.MustParseIA("1-ff00:0:111"), IfID: 1},
+				{IA: xtest.MustParseIA("1-ff00:0:110"), IfID: 2},
+			})
+			SoMsg("mtu", path.MTU, ShouldEqual, 1472)
+			SoMsg("expiry", path.Expiry.Equal(time.Unix(1000, 0)), ShouldBeTrue)
+			SoMsg("next hop", path.NextHop, ShouldEqual, "10.0.0.1:30041")
+			SoMsg("fwd path", path.FwdPath, ShouldResemble, []byte{1, 2, 3})
+		})
+		Convey("a path request without destination is rejected", func() {
+			w := serve(http.MethodGet, "/paths")
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+		})
+		Convey("a request with the wrong method is rejected", func() {
+			w := serve(http.MethodPost, "/paths?dst=1-ff00:0:110")
+			So(w.Code, ShouldEqual, http.StatusMethodNotAllowed)
+		})
+		Convey("a request without handler fails", func() {
+			w := serve(http.MethodGet, "/asinfo")
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+		Convey("an unknown endpoint is not found", func() {
+			w := serve(http.MethodGet, "/unknown")
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+		})
+	})
+}
@@ -0,0 +1,279 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servers
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/proto"
+	"github.com/scionproto/scion/go/sciond/internal/fetcher"
+)
+
+const (
+	// DefaultSubscriptionRefresh is the maximum interval between two
+	// recomputations of the subscribed paths. It bounds the time it takes for
+	// new segments to reach subscribed clients.
+	DefaultSubscriptionRefresh = 30 * time.Second
+	// minSubscriptionRefresh bounds the refresh rate if paths are about to
+	// expire.
+	minSubscriptionRefresh = time.Second
+)
+
+// Subscriptions keeps track of the path subscriptions of all clients. The
+// paths of each subscription are recomputed whenever Update is called, when
+// the earliest path expires, and at least every DefaultSubscriptionRefresh.
+// A client is only sent a new path set if it differs from the last one it
+// received.
+type Subscriptions struct {
+	fetcher *fetcher.Fetcher
+	updateC chan struct{}
+
+	mtx  sync.Mutex
+	subs map[subscriptionKey]*subscription
+}
+
+// NewSubscriptions creates an empty subscription registry that uses f to
+// compute paths. Call Run to start pushing path updates.
+func NewSubscriptions(f *fetcher.Fetcher) *Subscriptions {
+	return &Subscriptions{
+		fetcher: f,
+		updateC: make(chan struct{}, 1),
+		subs:    make(map[subscriptionKey]*subscription),
+	}
+}
+
+// Update triggers a recomputation of the paths of all subscriptions, e.g.,
+// because a revocation was received or new segments were inserted into the
+// path database. It does not block.
+func (s *Subscriptions) Update() {
+	select {
+	case s.updateC <- struct{}{}:
+	default:
+	}
+}
+
+// Run recomputes the paths of all subscriptions and pushes changed paths to
+// the clients, until ctx is canceled.
+func (s *Subscriptions) Run(ctx context.Context) {
+	timer := time.NewTimer(DefaultSubscriptionRefresh)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.updateC:
+			if !timer.Stop() {
+				<-timer.C
+			}
+		case <-timer.C:
+		}
+		timer.Reset(s.refresh(ctx))
+	}
+}
+
+// refresh recomputes the paths of all subscriptions, and returns the time
+// until the next refresh is due.
+func (s *Subscriptions) refresh(ctx context.Context) time.Duration {
+	next := DefaultSubscriptionRefresh
+	for key, sub := range s.list() {
+		reply := s.fetch(ctx, sub.req)
+		if expiry := earliestExpiry(reply); !expiry.IsZero() {
+			if d := time.Until(expiry); d < next {
+				next = d
+			}
+		}
+		if samePaths(sub.last, reply) {
+			continue
+		}
+		if err := sub.send(reply); err != nil {
+			log.FromCtx(ctx).Warn("Unable to push paths to client, dropping subscription",
+				"client", sub.src, "err", err)
+			s.remove(key)
+			continue
+		}
+		sub.last = reply
+	}
+	if next < minSubscriptionRefresh {
+		next = minSubscriptionRefresh
+	}
+	return next
+}
+
+func (s *Subscriptions) fetch(ctx context.Context, req *sciond.PathReq) *sciond.PathReply {
+	logger := log.FromCtx(ctx)
+	workCtx, workCancelF := context.WithTimeout(ctx, DefaultWorkTimeout)
+	defer workCancelF()
+	reply, err := s.fetcher.GetPaths(workCtx, req, DefaultEarlyReply, logger)
+	if err != nil {
+		logger.Error("Unable to get paths", "err", err)
+	}
+	if reply == nil {
+		reply = &sciond.PathReply{ErrorCode: sciond.ErrorInternal}
+	}
+	return reply
+}
+
+func (s *Subscriptions) list() map[subscriptionKey]*subscription {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	subs := make(map[subscriptionKey]*subscription, len(s.subs))
+	for key, sub := range s.subs {
+		subs[key] = sub
+	}
+	return subs
+}
+
+func (s *Subscriptions) add(key subscriptionKey, sub *subscription) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.subs[key] = sub
+}
+
+func (s *Subscriptions) remove(key subscriptionKey) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.subs, key)
+}
+
+// removeConn removes all subscriptions of the client connected via conn.
+func (s *Subscriptions) removeConn(conn net.PacketConn) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for key := range s.subs {
+		if key.conn == conn {
+			delete(s.subs, key)
+		}
+	}
+}
+
+// subscriptionKey identifies a subscription by the client connection and the
+// message ID of the subscription request.
+type subscriptionKey struct {
+	conn net.PacketConn
+	id   uint64
+}
+
+type subscription struct {
+	conn net.PacketConn
+	src  net.Addr
+	id   uint64
+	req  *sciond.PathReq
+	// last is the path set most recently sent to the client.
+	last *sciond.PathReply
+}
+
+func (sub *subscription) send(reply *sciond.PathReply) error {
+	b, err := proto.PackRoot(&sciond.Pld{
+		Id:         sub.id,
+		Which:      proto.SCIONDMsg_Which_pathUpdate,
+		PathUpdate: &sciond.PathUpdate{Reply: reply},
+	})
+	if err != nil {
+		// This is constructed locally, so it should always succeed. Otherwise,
+		// it is a bug.
+		panic(err)
+	}
+	sub.conn.SetWriteDeadline(time.Now().Add(DefaultReplyTimeout))
+	_, err = sub.conn.WriteTo(b, sub.src)
+	return err
+}
+
+// PathSubscriptionHandler handles path subscription requests. On
+// subscription, the client is immediately sent the current paths.
+type PathSubscriptionHandler struct {
+	Subscriptions *Subscriptions
+}
+
+func (h *PathSubscriptionHandler) Handle(ctx context.Context, conn net.PacketConn,
+	src net.Addr, pld *sciond.Pld) {
+
+	logger := log.FromCtx(ctx)
+	logger.Debug("[PathSubscriptionHandler] Received request", "req", pld.PathSubscribeReq)
+	key := subscriptionKey{conn: conn, id: pld.Id}
+	if pld.PathSubscribeReq.Unsubscribe {
+		h.Subscriptions.remove(key)
+		return
+	}
+	sub := &subscription{
+		conn: conn,
+		src:  src,
+		id:   pld.Id,
+		req: &sciond.PathReq{
+			Dst:      pld.PathSubscribeReq.Dst,
+			Src:      pld.PathSubscribeReq.Src,
+			MaxPaths: pld.PathSubscribeReq.MaxPaths,
+		},
+	}
+	reply := h.Subscriptions.fetch(ctx, sub.req)
+	if err := sub.send(reply); err != nil {
+		logger.Warn("Unable to reply to client", "client", src, "err", err)
+		return
+	}
+	sub.last = reply
+	h.Subscriptions.add(key, sub)
+	logger.Debug("Subscribed client to paths", "num_paths", len(reply.Entries))
+}
+
+// ConnClosed drops the subscriptions of the client connected via conn.
+func (h *PathSubscriptionHandler) ConnClosed(conn net.PacketConn) {
+	h.Subscriptions.removeConn(conn)
+}
+
+// earliestExpiry returns the expiration time of the path in reply that
+// expires first, or the zero time if reply contains no paths.
+func earliestExpiry(reply *sciond.PathReply) time.Time {
+	var earliest time.Time
+	for _, entry := range reply.Entries {
+		if entry.Path == nil {
+			continue
+		}
+		if expiry := entry.Path.Expiry(); earliest.IsZero() || expiry.Before(earliest) {
+			earliest = expiry
+		}
+	}
+	return earliest
+}
+
+// samePaths returns true if a and b contain the same paths with the same
+// expiration times, irrespective of their order.
+func samePaths(a, b *sciond.PathReply) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.ErrorCode != b.ErrorCode || len(a.Entries) != len(b.Entries) {
+		return false
+	}
+	expTimes := make(map[string]uint32, len(a.Entries))
+	for _, entry := range a.Entries {
+		if entry.Path != nil {
+			expTimes[string(entry.Path.FwdPath)] = entry.Path.ExpTime
+		}
+	}
+	for _, entry := range b.Entries {
+		if entry.Path == nil {
+			continue
+		}
+		expTime, ok := expTimes[string(entry.Path.FwdPath)]
+		if !ok || expTime != entry.Path.ExpTime {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servers
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/sciond"
+)
+
+func TestSamePaths(t *testing.T) {
+	entry := func(fwdPath string, expTime uint32) sciond.PathReplyEntry {
+		return sciond.PathReplyEntry{
+			Path: &sciond.FwdPathMeta{FwdPath: []byte(fwdPath), ExpTime: expTime},
+		}
+	}
+	reply := func(code sciond.PathErrorCode, entries ...sciond.PathReplyEntry) *sciond.PathReply {
+		return &sciond.PathReply{ErrorCode: code, Entries: entries}
+	}
+	testCases := []struct {
+		Name string
+		A    *sciond.PathReply
+		B    *sciond.PathReply
+		Same bool
+	}{
+		{
+			Name: "nothing sent yet",
+			B:    reply(sciond.ErrorNoPaths),
+		},
+		{
+			Name: "different error codes",
+			A:    reply(sciond.ErrorNoPaths),
+			B:    reply(sciond.ErrorInternal),
+		},
+		{
+			Name: "same paths in different order",
+			A:    reply(sciond.ErrorOk, entry("a", 1), entry("b", 2)),
+			B:    reply(sciond.ErrorOk, entry("b", 2), entry("a", 1)),
+			Same: true,
+		},
+		{
+			Name: "path replaced",
+			A:    reply(sciond.ErrorOk, entry("a", 1), entry("b", 2)),
+			B:    reply(sciond.ErrorOk, entry("a", 1), entry("c", 2)),
+		},
+		{
+			Name: "path removed",
+			A:    reply(sciond.ErrorOk, entry("a", 1), entry("b", 2)),
+			B:    reply(sciond.ErrorOk, entry("a", 1)),
+		},
+		{
+			Name: "path refreshed",
+			A:    reply(sciond.ErrorOk, entry("a", 1)),
+			B:    reply(sciond.ErrorOk, entry("a", 3)),
+		},
+	}
+	Convey("samePaths detects changed path sets", t, func() {
+		for _, tc := range testCases {
+			Convey(tc.Name, func() {
+				So(samePaths(tc.A, tc.B), ShouldEqual, tc.Same)
+			})
+		}
+	})
+}
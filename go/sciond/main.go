@@ -18,6 +18,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"path/filepath"
@@ -41,6 +42,7 @@ import (
 	"github.com/scionproto/scion/go/lib/periodic"
 	"github.com/scionproto/scion/go/lib/revcache"
 	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/tracing"
 	"github.com/scionproto/scion/go/proto"
 	"github.com/scionproto/scion/go/sciond/internal/config"
 	"github.com/scionproto/scion/go/sciond/internal/fetcher"
@@ -84,6 +86,11 @@ func realMain() int {
 		log.Crit("Setup failed", "err", err)
 		return 1
 	}
+	if err := cfg.Tracing.InitGlobalTracer(cfg.General.ID); err != nil {
+		log.Crit("Unable to initialize tracing", "err", err)
+		return 1
+	}
+	defer tracing.Close()
 	if err := startDiscovery(); err != nil {
 		log.Crit("Unable to start topology fetcher", "err", err)
 		return 1
@@ -131,17 +138,29 @@ func realMain() int {
 		log.Crit(infraenv.ErrAppUnableToInitMessenger, "err", err)
 		return 1
 	}
+	pathFetcher := fetcher.NewFetcher(
+		msger,
+		pathDB,
+		trustStore,
+		revCache,
+		cfg.SD,
+		log.Root(),
+	)
+	subscriptions := servers.NewSubscriptions(pathFetcher)
+	pathFetcher.OnSegmentsInserted(subscriptions.Update)
+	subCtx, subCancelF := context.WithCancel(context.Background())
+	defer subCancelF()
+	go func() {
+		defer log.LogPanicAndExit()
+		subscriptions.Run(subCtx)
+	}()
 	// Route messages to their correct handlers
 	handlers := servers.HandlerMap{
 		proto.SCIONDMsg_Which_pathReq: &servers.PathRequestHandler{
-			Fetcher: fetcher.NewFetcher(
-				msger,
-				pathDB,
-				trustStore,
-				revCache,
-				cfg.SD,
-				log.Root(),
-			),
+			Fetcher: pathFetcher,
+		},
+		proto.SCIONDMsg_Which_pathSubscribeReq: &servers.PathSubscriptionHandler{
+			Subscriptions: subscriptions,
 		},
 		proto.SCIONDMsg_Which_asInfoReq: &servers.ASInfoRequestHandler{
 			TrustStore: trustStore,
@@ -149,8 +168,9 @@ func realMain() int {
 		proto.SCIONDMsg_Which_ifInfoRequest:      &servers.IFInfoRequestHandler{},
 		proto.SCIONDMsg_Which_serviceInfoRequest: &servers.SVCInfoRequestHandler{},
 		proto.SCIONDMsg_Which_revNotification: &servers.RevNotificationHandler{
-			RevCache:   revCache,
-			TrustStore: trustStore,
+			RevCache:      revCache,
+			TrustStore:    trustStore,
+			Subscriptions: subscriptions,
 		},
 	}
 	cleaner := periodic.StartPeriodicTask(pathdb.NewCleaner(pathDB),
@@ -159,6 +179,19 @@ func realMain() int {
 	rcCleaner := periodic.StartPeriodicTask(revcache.NewCleaner(revCache),
 		periodic.NewTicker(10*time.Second), 10*time.Second)
 	defer rcCleaner.Stop()
+	for _, r := range pathstorage.StartRevCacheSharing(cfg.SD.RevCache, revCache) {
+		defer r.Stop()
+	}
+	if len(cfg.SD.Prefetch) > 0 {
+		// The timeout is effectively forever, each destination is fetched with
+		// its own timeout.
+		prefetcher := periodic.StartPeriodicTask(
+			fetcher.NewPrefetcher(pathFetcher, cfg.SD.Prefetch),
+			periodic.NewTicker(fetcher.DefaultPrefetchInterval), time.Hour)
+		defer prefetcher.Stop()
+		// Warm up the cache right away instead of waiting for the first tick.
+		prefetcher.TriggerRun()
+	}
 	// Start servers
 	rsockServer, shutdownF := NewServer("rsock", cfg.SD.Reliable, handlers, log.Root())
 	defer shutdownF()
@@ -166,6 +199,12 @@ func realMain() int {
 	unixpacketServer, shutdownF := NewServer("unixpacket", cfg.SD.Unix, handlers, log.Root())
 	defer shutdownF()
 	StartServer("UnixServer", cfg.SD.Unix, unixpacketServer)
+	if cfg.SD.HTTP != "" {
+		StartHTTPServer(cfg.SD.HTTP, &servers.HTTPHandler{
+			Handlers: handlers,
+			Logger:   log.Root(),
+		})
+	}
 	cfg.Metrics.StartPrometheus()
 	select {
 	case <-environment.AppShutdownSignal:
@@ -224,6 +263,20 @@ func NewServer(network string, rsockPath string, handlers servers.HandlerMap,
 	return server, shutdownF
 }
 
+func StartHTTPServer(address string, handler http.Handler) {
+	go func() {
+		defer log.LogPanicAndExit()
+		if cfg.SD.DeleteSocket && filepath.IsAbs(address) {
+			if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+				fatal.Fatal(common.NewBasicError("HTTPServer SocketRemoval error", err))
+			}
+		}
+		if err := servers.ListenAndServeHTTP(address, handler); err != nil {
+			fatal.Fatal(common.NewBasicError("HTTPServer ListenAndServe error", err))
+		}
+	}()
+}
+
 func StartServer(name, sockPath string, server *servers.Server) {
 	go func() {
 		defer log.LogPanicAndExit()
@@ -0,0 +1,147 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/log/logparse"
+)
+
+// Filter decides which log entries are printed. The zero value accepts all
+// entries.
+type Filter struct {
+	// MaxLevel is the least severe level that is still accepted. Since log
+	// levels are ordered from most severe (crit) to least severe (debug), an
+	// entry passes if its level is numerically smaller or equal. Ignored if
+	// HasLevel is false.
+	MaxLevel log.Lvl
+	HasLevel bool
+	// Elements is a list of glob patterns (see path.Match) of which at least
+	// one has to match the element name. Ignored if empty.
+	Elements []string
+	// Since and Until delimit the accepted time window. Zero values are
+	// ignored.
+	Since time.Time
+	Until time.Time
+	// KeyValues maps keys to the value that the entry must contain, e.g.
+	// debug_id -> 0a1b2c3d. All pairs have to be present.
+	KeyValues map[string]string
+}
+
+// Match returns whether the entry passes all conditions of the filter.
+func (f *Filter) Match(e logparse.LogEntry) bool {
+	if f.HasLevel && e.Level > f.MaxLevel {
+		return false
+	}
+	if !f.Since.IsZero() && e.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Timestamp.After(f.Until) {
+		return false
+	}
+	if len(f.Elements) > 0 && !f.matchElement(e.Element) {
+		return false
+	}
+	for k, v := range f.KeyValues {
+		if !containsKV(e.Lines, k, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *Filter) matchElement(element string) bool {
+	for _, pattern := range f.Elements {
+		// Patterns are validated when parsing the flags, ignore the error.
+		if ok, _ := path.Match(pattern, element); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// containsKV returns whether any of the lines contains key=value as a
+// separate token. Quoted values (key="value") are accepted as well.
+func containsKV(lines []string, key, value string) bool {
+	prefix := key + "="
+	for _, line := range lines {
+		for _, field := range strings.Fields(line) {
+			if !strings.HasPrefix(field, prefix) {
+				continue
+			}
+			if strings.Trim(field[len(prefix):], `"`) == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseTime parses a point in time given either as an absolute timestamp
+// (common.TimeFmt or RFC3339) or as a duration relative to now, e.g. 15m
+// means 15 minutes ago.
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(common.TimeFmt, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, common.NewBasicError("Unable to parse time", nil, "input", s)
+	}
+	return t, nil
+}
+
+// parseElements splits a comma separated list of glob patterns and validates
+// each of them.
+func parseElements(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	patterns := strings.Split(s, ",")
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, common.NewBasicError("Invalid element pattern", err, "pattern", pattern)
+		}
+	}
+	return patterns, nil
+}
+
+// kvFlag collects repeated key=value command line arguments.
+type kvFlag map[string]string
+
+func (f kvFlag) String() string {
+	var pairs []string
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f kvFlag) Set(s string) error {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return common.NewBasicError("Expected key=value", nil, "input", s)
+	}
+	f[parts[0]] = parts[1]
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/log/logparse"
+)
+
+func TestFilterMatch(t *testing.T) {
+	ts := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	entry := logparse.LogEntry{
+		Timestamp: ts,
+		Element:   "ps1-ff00_0_110-1",
+		Level:     log.LvlInfo,
+		Lines:     []string{"Handling request debug_id=0a1b2c3d", `> ia="1-ff00:0:110"`},
+	}
+	tests := []struct {
+		Name   string
+		Filter Filter
+		Match  bool
+	}{
+		{
+			Name:   "empty filter",
+			Filter: Filter{},
+			Match:  true,
+		},
+		{
+			Name:   "level less severe",
+			Filter: Filter{HasLevel: true, MaxLevel: log.LvlDebug},
+			Match:  true,
+		},
+		{
+			Name:   "level more severe",
+			Filter: Filter{HasLevel: true, MaxLevel: log.LvlWarn},
+			Match:  false,
+		},
+		{
+			Name:   "element glob matches",
+			Filter: Filter{Elements: []string{"bs*", "ps*"}},
+			Match:  true,
+		},
+		{
+			Name:   "element glob does not match",
+			Filter: Filter{Elements: []string{"bs*"}},
+			Match:  false,
+		},
+		{
+			Name:   "in time window",
+			Filter: Filter{Since: ts.Add(-time.Second), Until: ts.Add(time.Second)},
+			Match:  true,
+		},
+		{
+			Name:   "before time window",
+			Filter: Filter{Since: ts.Add(time.Second)},
+			Match:  false,
+		},
+		{
+			Name:   "after time window",
+			Filter: Filter{Until: ts.Add(-time.Second)},
+			Match:  false,
+		},
+		{
+			Name: "key values present",
+			Filter: Filter{
+				KeyValues: map[string]string{"debug_id": "0a1b2c3d", "ia": "1-ff00:0:110"},
+			},
+			Match: true,
+		},
+		{
+			Name:   "key value with other value",
+			Filter: Filter{KeyValues: map[string]string{"debug_id": "0a1b"}},
+			Match:  false,
+		},
+		{
+			Name:   "key value missing",
+			Filter: Filter{KeyValues: map[string]string{"req": "1"}},
+			Match:  false,
+		},
+	}
+	Convey("Filter.Match", t, func() {
+		for _, test := range tests {
+			Convey(test.Name, func() {
+				SoMsg("match", test.Filter.Match(entry), ShouldEqual, test.Match)
+			})
+		}
+	})
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	Convey("parseTime", t, func() {
+		Convey("Duration is relative to now", func() {
+			ts, err := parseTime("15m", now)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("ts", ts, ShouldResemble, now.Add(-15*time.Minute))
+		})
+		Convey("Log timestamp format is accepted", func() {
+			ts, err := parseTime("2019-03-01 11:00:00.000000+0000", now)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("ts", ts.Equal(now.Add(-time.Hour)), ShouldBeTrue)
+		})
+		Convey("RFC3339 is accepted", func() {
+			ts, err := parseTime("2019-03-01T11:00:00Z", now)
+			SoMsg("err", err, ShouldBeNil)
+			SoMsg("ts", ts.Equal(now.Add(-time.Hour)), ShouldBeTrue)
+		})
+		Convey("Garbage is rejected", func() {
+			_, err := parseTime("yesterday", now)
+			SoMsg("err", err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestLastEntryStart(t *testing.T) {
+	Convey("lastEntryStart", t, func() {
+		Convey("Returns start of last entry", func() {
+			data := []byte("a\nb\n> c\n  d\n")
+			SoMsg("offset", lastEntryStart(data), ShouldEqual, 2)
+		})
+		Convey("Returns 0 for single entry", func() {
+			data := []byte("a\n> b\n")
+			SoMsg("offset", lastEntryStart(data), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/log/logparse"
+)
+
+// tailedFile is a log file that is followed for new entries.
+type tailedFile struct {
+	name string
+	file *os.File
+	// pending contains data that has been read but not yet parsed, i.e. an
+	// incomplete line or an entry that might still get continuation lines.
+	pending []byte
+}
+
+func openTailed(fn string) (*tailedFile, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	// Only entries written after startup are of interest, the existing
+	// content was already processed.
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &tailedFile{name: fn, file: f}, nil
+}
+
+// poll reads newly appended data and returns the entries that are complete.
+// The last entry is only returned once no more data arrived since the last
+// poll, since it might still get continuation lines.
+func (t *tailedFile) poll() LogEntries {
+	data, err := ioutil.ReadAll(t.file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read file %s: %s\n", t.name, err)
+		return nil
+	}
+	t.pending = append(t.pending, data...)
+	end := bytes.LastIndexByte(t.pending, '\n') + 1
+	if end == 0 {
+		return nil
+	}
+	if len(data) > 0 {
+		end = lastEntryStart(t.pending[:end])
+	}
+	if end == 0 {
+		return nil
+	}
+	var entries LogEntries
+	logparse.ParseFrom(bytes.NewReader(t.pending[:end]), t.name, fnToEName(t.name),
+		func(e logparse.LogEntry) {
+			entries = append(entries, e)
+		},
+	)
+	t.pending = append([]byte(nil), t.pending[end:]...)
+	return entries
+}
+
+// lastEntryStart returns the offset of the last line in data that is not a
+// continuation line. data must end with a newline.
+func lastEntryStart(data []byte) int {
+	start := len(data)
+	for start > 0 {
+		lineStart := bytes.LastIndexByte(data[:start-1], '\n') + 1
+		if !isContinuation(data[lineStart:]) {
+			return lineStart
+		}
+		start = lineStart
+	}
+	return 0
+}
+
+func isContinuation(line []byte) bool {
+	return bytes.HasPrefix(line, []byte("> ")) || bytes.HasPrefix(line, []byte(" "))
+}
+
+// follow polls the files for new entries until the process is terminated.
+// Entries read in the same polling round are sorted by timestamp before they
+// are passed to the output function.
+func follow(fns []string, interval time.Duration, output func(logparse.LogEntry)) {
+	var files []*tailedFile
+	for _, fn := range fns {
+		t, err := openTailed(fn)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not follow file %s: %s\n", fn, err)
+			continue
+		}
+		defer t.file.Close()
+		files = append(files, t)
+	}
+	if len(files) == 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		var entries LogEntries
+		for _, t := range files {
+			entries = append(entries, t.poll()...)
+		}
+		sort.Stable(entries)
+		for _, entry := range entries {
+			output(entry)
+		}
+	}
+}
@@ -24,16 +24,25 @@
 // timestamp format of the output is the same as the input format, i.e. ISO8601
 // with a space instead of "T".
 //
+// Entries can be filtered by level (-level), element name (-element, a comma
+// separated list of glob patterns), time window (-since, -until) and key/value
+// pairs contained in the entry (-kv, e.g. -kv debug_id=0a1b2c3d, can be
+// repeated). With -json each entry is emitted as a single JSON object per
+// line. With -follow the files are watched for new entries after the existing
+// content has been printed.
+//
 // Limitations:
 // - All the logs are kept in memory prior to output. Processing terabytes of
 //   logs is thus not recommended.
 // - The tool does not care about stdin
 // - The tool tries to keep going in the face of errors, but will emit messages
 //   to stderr when doing so.
+// - In follow mode, entries are only sorted within one polling interval.
 
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -41,16 +50,35 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/env"
+	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/log/logparse"
 )
 
 var (
 	version = flag.Bool("version", false, "Output version information and exit.")
+	level   = flag.String("level", "",
+		"Only output entries with this level or more severe (debug|info|warn|error|crit).")
+	element = flag.String("element", "",
+		"Comma separated glob patterns, only output entries of matching elements.")
+	since = flag.String("since", "",
+		"Only output entries at or after this time (timestamp or duration before now).")
+	until = flag.String("until", "",
+		"Only output entries at or before this time (timestamp or duration before now).")
+	jsonOut  = flag.Bool("json", false, "Output entries as JSON lines.")
+	followF  = flag.Bool("follow", false, "Keep watching the files for new entries.")
+	interval = flag.Duration("interval", time.Second, "Polling interval in follow mode.")
+	kvs      = kvFlag{}
 )
 
+func init() {
+	flag.Var(kvs, "kv",
+		"Only output entries containing key=value (e.g. debug_id=0a1b2c3d), can be repeated.")
+}
+
 func main() {
 	flag.Usage = printUsage
 	flag.Parse()
@@ -58,6 +86,11 @@ func main() {
 		fmt.Print(env.VersionInfo())
 		os.Exit(0)
 	}
+	filter, err := filterFromFlags()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid filter: %s\n", err)
+		os.Exit(1)
+	}
 	maxENameLen := 0
 	// Read in all files
 	for _, fn := range flag.Args() {
@@ -67,19 +100,102 @@ func main() {
 			maxENameLen = eNameLen
 		}
 	}
-	indent := strings.Repeat(" ", maxENameLen+3)
-	fmtL := "[%-" + strconv.Itoa(maxENameLen) + "s] %s"
+	p := newPrinter(maxENameLen, *jsonOut)
+	output := func(entry logparse.LogEntry) {
+		if filter.Match(entry) {
+			p.print(entry)
+		}
+	}
 	// Sort by timestamp and output
 	sort.Sort(entries)
-	lastelement := ""
 	for _, entry := range entries {
-		if entry.Element == lastelement {
-			fmt.Printf("%s%s", indent, fmtEntry(entry, indent))
-		} else {
-			lastelement = entry.Element
-			fmt.Printf(fmtL, entry.Element, fmtEntry(entry, indent))
+		output(entry)
+	}
+	if *followF {
+		follow(flag.Args(), *interval, output)
+	}
+}
+
+func filterFromFlags() (*Filter, error) {
+	var err error
+	filter := &Filter{KeyValues: kvs}
+	if *level != "" {
+		if filter.MaxLevel, err = log.LvlFromString(*level); err != nil {
+			return nil, err
+		}
+		filter.HasLevel = true
+	}
+	if filter.Elements, err = parseElements(*element); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if *since != "" {
+		if filter.Since, err = parseTime(*since, now); err != nil {
+			return nil, err
+		}
+	}
+	if *until != "" {
+		if filter.Until, err = parseTime(*until, now); err != nil {
+			return nil, err
 		}
 	}
+	return filter, nil
+}
+
+// printer writes entries to stdout, either human readable with the element
+// name as prefix or as JSON lines.
+type printer struct {
+	json        bool
+	indent      string
+	fmtL        string
+	lastElement string
+	enc         *json.Encoder
+}
+
+func newPrinter(maxENameLen int, jsonOut bool) *printer {
+	return &printer{
+		json:   jsonOut,
+		indent: strings.Repeat(" ", maxENameLen+3),
+		fmtL:   "[%-" + strconv.Itoa(maxENameLen) + "s] %s",
+		enc:    json.NewEncoder(os.Stdout),
+	}
+}
+
+func (p *printer) print(entry logparse.LogEntry) {
+	if p.json {
+		if err := p.enc.Encode(newJSONEntry(entry)); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not encode entry: %s\n", err)
+		}
+		return
+	}
+	if entry.Element == p.lastElement {
+		fmt.Printf("%s%s", p.indent, fmtEntry(entry, p.indent))
+	} else {
+		p.lastElement = entry.Element
+		fmt.Printf(p.fmtL, entry.Element, fmtEntry(entry, p.indent))
+	}
+}
+
+// jsonEntry is the JSON representation of a log entry.
+type jsonEntry struct {
+	Timestamp string   `json:"ts"`
+	Element   string   `json:"element"`
+	Level     string   `json:"level"`
+	Msg       string   `json:"msg"`
+	Lines     []string `json:"lines,omitempty"`
+}
+
+func newJSONEntry(l logparse.LogEntry) jsonEntry {
+	e := jsonEntry{
+		Timestamp: l.Timestamp.Format(time.RFC3339Nano),
+		Element:   l.Element,
+		Level:     l.Level.String(),
+	}
+	if len(l.Lines) > 0 {
+		e.Msg = l.Lines[0]
+		e.Lines = l.Lines[1:]
+	}
+	return e
 }
 
 func fmtEntry(l logparse.LogEntry, indent string) string {
@@ -112,7 +228,7 @@ func fnToEName(s string) string {
 }
 
 func printUsage() {
-	fmt.Printf("Usage: %s <logfile> [logfile ...]\n", os.Args[0])
+	fmt.Printf("Usage: %s [flags] <logfile> [logfile ...]\n", os.Args[0])
 	flag.PrintDefaults()
 }
 
@@ -77,12 +77,13 @@ The AS Certificate and Issuer Certificate sections can contain the following val
 		with the subject’s public/private key
 	SignAlgorithm (ed25519) [optional]
 		cryptographic algorithm that must be used to sign/verify a message with
-		the subject’s private/public key.
+		the subject’s private/public key. One of ed25519, ecdsa-p256, ecdsa-p384.
 The Key Algorithms section that can contain following values
 	Online (ed25519) [optional]
 		cryptographic algorithm that must be used as signing algorithm by online key
 	Offline (ed25519) [optional]
 		cryptographic algorithm that must be used as signing algorithm by offline key
+The signing keys generated by 'keys gen' use the algorithms configured in as.ini.
 `,
 }
 
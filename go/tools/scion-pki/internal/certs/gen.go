@@ -22,11 +22,11 @@ import (
 	"time"
 
 	"golang.org/x/crypto/curve25519"
-	"golang.org/x/crypto/ed25519"
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/scrypto"
 	"github.com/scionproto/scion/go/lib/scrypto/cert"
 	"github.com/scionproto/scion/go/lib/scrypto/trc"
 	"github.com/scionproto/scion/go/lib/util"
@@ -217,7 +217,10 @@ func genCertCommon(bc *conf.BaseCert, s addr.IA, signKeyFname string) (*cert.Cer
 	if err != nil {
 		return nil, err
 	}
-	signPub := common.RawBytes(ed25519.PrivateKey(signKey).Public().(ed25519.PublicKey))
+	signPub, err := scrypto.PublicKey(signKey, bc.SignAlgorithm)
+	if err != nil {
+		return nil, err
+	}
 	decKey, err := keyconf.LoadKey(filepath.Join(keyDir, keyconf.DecKeyFile), bc.EncAlgorithm)
 	if err != nil {
 		return nil, err
@@ -23,6 +23,7 @@ import (
 	"github.com/scionproto/scion/go/tools/scion-pki/internal/certs"
 	"github.com/scionproto/scion/go/tools/scion-pki/internal/keys"
 	"github.com/scionproto/scion/go/tools/scion-pki/internal/pkicmn"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/show"
 	"github.com/scionproto/scion/go/tools/scion-pki/internal/tmpl"
 	"github.com/scionproto/scion/go/tools/scion-pki/internal/trc"
 	"github.com/scionproto/scion/go/tools/scion-pki/internal/version"
@@ -106,5 +107,6 @@ func init() {
 	RootCmd.AddCommand(version.Cmd)
 	RootCmd.AddCommand(trc.Cmd)
 	RootCmd.AddCommand(tmpl.Cmd)
+	RootCmd.AddCommand(show.Cmd)
 	RootCmd.AddCommand(autoCompleteCmd)
 }
@@ -131,6 +131,25 @@ func TestValidatingAsConf(t *testing.T) {
 				},
 				err: "",
 			},
+			{
+				scenario: "With ECDSA algorithms",
+				as: &As{
+					AsCert: &AsCert{
+						Issuer: "1-ff00:0:10",
+						BaseCert: &BaseCert{
+							SignAlgorithm: "ecdsa-p256",
+							TRCVersion:    1,
+							Version:       1,
+							RawValidity:   "180s",
+						},
+					},
+					KeyAlgorithms: &KeyAlgorithms{
+						Online:  "ecdsa-p384",
+						Offline: "ecdsa-p384",
+					},
+				},
+				err: "",
+			},
 			{
 				scenario: "With invalid sign algorithm",
 				as: &As{
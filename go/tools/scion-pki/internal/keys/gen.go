@@ -24,6 +24,7 @@ import (
 
 	"golang.org/x/crypto/ed25519"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/keyconf"
 	"github.com/scionproto/scion/go/lib/scrypto"
@@ -44,8 +45,12 @@ func runGenKey(args []string) {
 		for _, ia := range ases {
 			dir := pkicmn.GetAsPath(pkicmn.OutDir, ia)
 			core := pkicmn.Contains(iconf.Trc.CoreIAs, ia)
+			algos, err := loadKeyAlgos(ia)
+			if err != nil {
+				pkicmn.ErrorAndExit("Error reading as.ini: %s\n", err)
+			}
 			pkicmn.QuietPrint("Generating keys for %s\n", ia)
-			if err = genAll(filepath.Join(dir, pkicmn.KeysDir), core); err != nil {
+			if err = genAll(filepath.Join(dir, pkicmn.KeysDir), core, algos); err != nil {
 				pkicmn.ErrorAndExit("Error generating keys: %s\n", err)
 			}
 		}
@@ -53,9 +58,45 @@ func runGenKey(args []string) {
 	os.Exit(0)
 }
 
-func genAll(outDir string, core bool) error {
+// keyAlgos holds the signing algorithms of the different keys of an AS.
+type keyAlgos struct {
+	sign    string
+	issSig  string
+	online  string
+	offline string
+}
+
+// loadKeyAlgos reads the signing algorithms from as.ini. If the AS has no
+// as.ini, or a section is missing, ed25519 is used.
+func loadKeyAlgos(ia addr.IA) (keyAlgos, error) {
+	algos := keyAlgos{
+		sign:    scrypto.Ed25519,
+		issSig:  scrypto.Ed25519,
+		online:  scrypto.Ed25519,
+		offline: scrypto.Ed25519,
+	}
+	dir := pkicmn.GetAsPath(pkicmn.RootDir, ia)
+	if _, err := os.Stat(filepath.Join(dir, conf.AsConfFileName)); os.IsNotExist(err) {
+		return algos, nil
+	}
+	a, err := conf.LoadAsConf(dir)
+	if err != nil {
+		return algos, err
+	}
+	algos.sign = a.AsCert.SignAlgorithm
+	if a.IssuerCert != nil && a.IssuerCert.BaseCert != nil {
+		algos.issSig = a.IssuerCert.SignAlgorithm
+	}
+	if a.KeyAlgorithms != nil {
+		algos.online = a.KeyAlgorithms.Online
+		algos.offline = a.KeyAlgorithms.Offline
+	}
+	return algos, nil
+}
+
+func genAll(outDir string, core bool, algos keyAlgos) error {
 	// Generate AS sigining and decryption keys.
-	if err := genKey(keyconf.SigKeyFile, outDir, genSignKey); err != nil {
+	if err := genKey(keyconf.SigKeyFile, outDir, genSignKey(algos.sign)); err != nil {
 		return err
 	}
 	if err := genKey(keyconf.DecKeyFile, outDir, genEncKey); err != nil {
@@ -72,14 +113,14 @@ func genAll(outDir string, core bool) error {
 		return nil
 	}
 	// Generate core signing key.
-	if err := genKey(keyconf.IssSigKeyFile, outDir, genSignKey); err != nil {
+	if err := genKey(keyconf.IssSigKeyFile, outDir, genSignKey(algos.issSig)); err != nil {
 		return err
 	}
 	// Generate offline and online root keys if core was specified.
-	if err := genKey(keyconf.OffKeyFile, outDir, genSignKey); err != nil {
+	if err := genKey(keyconf.OffKeyFile, outDir, genSignKey(algos.offline)); err != nil {
 		return err
 	}
-	return genKey(keyconf.OnKeyFile, outDir, genSignKey)
+	return genKey(keyconf.OnKeyFile, outDir, genSignKey(algos.online))
 }
 
 type keyGenFunc func(io.Reader) ([]byte, error)
@@ -114,12 +155,19 @@ func genKey(fname, outDir string, keyGenF keyGenFunc) error {
 	return nil
 }
 
-func genSignKey(rand io.Reader) ([]byte, error) {
-	_, private, err := scrypto.GenKeyPair(scrypto.Ed25519)
-	if err != nil {
-		return nil, err
+// genSignKey returns a function that generates signing keys for algo. For
+// ed25519, the seed is stored. For ECDSA, the private scalar is stored.
+func genSignKey(algo string) keyGenFunc {
+	return func(rand io.Reader) ([]byte, error) {
+		_, private, err := scrypto.GenKeyPair(algo)
+		if err != nil {
+			return nil, err
+		}
+		if algo == scrypto.Ed25519 {
+			return ed25519.PrivateKey(private).Seed(), nil
+		}
+		return private, nil
 	}
-	return ed25519.PrivateKey(private).Seed(), nil
 }
 
 func genEncKey(rand io.Reader) ([]byte, error) {
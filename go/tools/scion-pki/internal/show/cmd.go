@@ -0,0 +1,58 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package show
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	jsonOutput bool
+	warnExpiry string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "show <file>...",
+	Short: "Display the content of certificates, certificate chains and TRCs",
+	Args:  cobra.MinimumNArgs(1),
+	Long: `
+'show' displays the content of certificate (.crt) and TRC (.trc) files in human-readable form.
+The file type is detected from the file content.
+
+For every file, the signature status and validity warnings are displayed:
+	Certificate chains
+		The leaf certificate is verified against the issuer certificate. The issuer
+		certificate is verified against the TRC it references, which is looked up in
+		the trcs directory of the ISD under the output directory (-o flag).
+	Issuer certificates
+		The certificate is verified against the TRC it references.
+	TRCs
+		The signatures are verified against the previous TRC, which is looked up in the
+		directory of the TRC file. A base TRC is verified against itself. If a newer TRC
+		is present in the same directory, the remaining grace period is displayed.
+
+Validity warnings are emitted for files that are not yet valid, expired, or expiring
+within the duration given by --warn-expiry.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runShow(args)
+	},
+}
+
+func init() {
+	Cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	Cmd.Flags().StringVar(&warnExpiry, "warn-expiry", "7d",
+		"Warn if a certificate or TRC expires within this duration")
+}
@@ -0,0 +1,326 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package show
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/scrypto/cert"
+	"github.com/scionproto/scion/go/lib/scrypto/trc"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/pkicmn"
+)
+
+const (
+	typeTRC   = "TRC"
+	typeChain = "Chain"
+	typeCert  = "Certificate"
+
+	sigValid = "valid"
+)
+
+// report is the inspection result of a single file.
+type report struct {
+	File       string
+	Type       string
+	Content    interface{}
+	Signatures []sigStatus
+	Warnings   []string
+}
+
+// sigStatus is the verification status of a single signature.
+type sigStatus struct {
+	Signer string
+	Status string
+}
+
+func runShow(args []string) {
+	within, err := util.ParseDuration(warnExpiry)
+	if err != nil {
+		pkicmn.ErrorAndExit("Error parsing --warn-expiry: %s\n", err)
+	}
+	now := util.TimeToSecs(time.Now())
+	exitStatus := 0
+	for _, path := range args {
+		r, err := inspect(path, now, within)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error inspecting %s: %s\n", path, err)
+			exitStatus = 2
+			continue
+		}
+		if jsonOutput {
+			raw, err := json.MarshalIndent(r, "", "    ")
+			if err != nil {
+				pkicmn.ErrorAndExit("Error json-encoding report: %s\n", err)
+			}
+			fmt.Printf("%s\n", raw)
+			continue
+		}
+		r.writeText(os.Stdout)
+	}
+	os.Exit(exitStatus)
+}
+
+// inspect parses the file at path as TRC, certificate chain or certificate
+// and creates the corresponding report.
+func inspect(path string, now uint32, within time.Duration) (*report, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if t, err := trc.TRCFromRaw(raw, false); err == nil {
+		return inspectTrc(path, t, now, within), nil
+	}
+	if c, err := cert.ChainFromRaw(raw, false); err == nil {
+		return inspectChain(path, c, now, within), nil
+	}
+	if c, err := cert.CertificateFromRaw(raw); err == nil {
+		return inspectCert(path, c, now, within), nil
+	}
+	return nil, common.NewBasicError("Unknown file type, expected TRC or certificate", nil)
+}
+
+func inspectTrc(path string, t *trc.TRC, now uint32, within time.Duration) *report {
+	r := &report{File: path, Type: typeTRC, Content: t}
+	r.Warnings = expiryWarnings(t.VerifyTime(now), t.ExpirationTime, now, within)
+	if t.Quarantine {
+		r.Warnings = append(r.Warnings, trc.EarlyAnnouncement)
+	}
+	dir := filepath.Dir(path)
+	if next, err := loadTrc(dir, t.ISD, t.Version+1); err == nil {
+		graceEnd := next.CreationTime + next.GracePeriod
+		if now > graceEnd {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s: superseded by version %d at %s",
+				trc.GracePeriodPassed, next.Version, fmtTime(graceEnd)))
+		} else {
+			r.Warnings = append(r.Warnings, fmt.Sprintf(
+				"Superseded by version %d, grace period ends at %s", next.Version,
+				fmtTime(graceEnd)))
+		}
+	}
+	voters := t
+	if t.Version > 1 {
+		prev, err := loadTrc(dir, t.ISD, t.Version-1)
+		if err != nil {
+			for _, signer := range sortedSigners(t) {
+				r.Signatures = append(r.Signatures, sigStatus{Signer: signer,
+					Status: "unverified (previous TRC not found)"})
+			}
+			return r
+		}
+		voters = prev
+	}
+	var tvr *trc.TRCVerResult
+	var err error
+	if voters == t {
+		tvr, err = t.VerifyBase()
+	} else {
+		tvr, err = t.Verify(voters)
+	}
+	if err != nil {
+		r.Warnings = append(r.Warnings, err.Error())
+	}
+	if tvr == nil {
+		return r
+	}
+	for _, signer := range sortedSigners(t) {
+		r.Signatures = append(r.Signatures, sigStatus{Signer: signer,
+			Status: trcSigStatus(signer, tvr)})
+	}
+	return r
+}
+
+func trcSigStatus(signer string, tvr *trc.TRCVerResult) string {
+	for _, ia := range tvr.Verified {
+		if ia.String() == signer {
+			return sigValid
+		}
+	}
+	for ia, err := range tvr.Failed {
+		if ia.String() == signer {
+			return err.Error()
+		}
+	}
+	return "not a voting core AS"
+}
+
+func inspectChain(path string, c *cert.Chain, now uint32, within time.Duration) *report {
+	r := &report{File: path, Type: typeChain, Content: c}
+	for _, w := range expiryWarnings(c.Leaf.VerifyTime(now), c.Leaf.ExpirationTime, now,
+		within) {
+		r.Warnings = append(r.Warnings, "Leaf: "+w)
+	}
+	for _, w := range expiryWarnings(c.Issuer.VerifyTime(now), c.Issuer.ExpirationTime, now,
+		within) {
+		r.Warnings = append(r.Warnings, "Issuer: "+w)
+	}
+	status := sigValid
+	if err := c.Leaf.VerifySignature(c.Issuer.SubjectSignKey,
+		c.Issuer.SignAlgorithm); err != nil {
+		status = err.Error()
+	}
+	r.Signatures = append(r.Signatures, sigStatus{
+		Signer: fmt.Sprintf("%s (leaf)", c.Leaf.Issuer),
+		Status: status,
+	})
+	r.Signatures = append(r.Signatures, issuerSigStatus(c.Issuer))
+	return r
+}
+
+func inspectCert(path string, c *cert.Certificate, now uint32, within time.Duration) *report {
+	r := &report{File: path, Type: typeCert, Content: c}
+	r.Warnings = expiryWarnings(c.VerifyTime(now), c.ExpirationTime, now, within)
+	if !c.CanIssue {
+		r.Signatures = append(r.Signatures, sigStatus{
+			Signer: c.Issuer.String(),
+			Status: "unverified (issuer certificate required)",
+		})
+		return r
+	}
+	r.Signatures = append(r.Signatures, issuerSigStatus(c))
+	return r
+}
+
+// issuerSigStatus verifies the issuer certificate c against the online key of
+// its issuer in the TRC it references.
+func issuerSigStatus(c *cert.Certificate) sigStatus {
+	s := sigStatus{Signer: fmt.Sprintf("%s (issuer)", c.Issuer)}
+	dir := filepath.Join(pkicmn.GetIsdPath(pkicmn.OutDir, c.Issuer.I), pkicmn.TRCsDir)
+	t, err := loadTrc(dir, c.Issuer.I, c.TRCVersion)
+	if err != nil {
+		s.Status = fmt.Sprintf("unverified (TRC version %d not found)", c.TRCVersion)
+		return s
+	}
+	coreAS, ok := t.CoreASes[c.Issuer]
+	if !ok {
+		s.Status = fmt.Sprintf("%s: %s", cert.IssASNotFound, c.Issuer)
+		return s
+	}
+	s.Status = sigValid
+	if err := c.VerifySignature(coreAS.OnlineKey, coreAS.OnlineKeyAlg); err != nil {
+		s.Status = err.Error()
+	}
+	return s
+}
+
+// expiryWarnings returns the validity error, if any. Otherwise, a warning is
+// returned if the expiration time is within the given duration from now.
+func expiryWarnings(validity error, expiration, now uint32,
+	within time.Duration) []string {
+
+	if validity != nil {
+		return []string{validity.Error()}
+	}
+	left := time.Duration(expiration-now) * time.Second
+	if left <= within {
+		return []string{fmt.Sprintf("Expires in %s at %s", left, fmtTime(expiration))}
+	}
+	return nil
+}
+
+func loadTrc(dir string, isd addr.ISD, version uint64) (*trc.TRC, error) {
+	return trc.TRCFromFile(filepath.Join(dir, fmt.Sprintf(pkicmn.TrcNameFmt, isd, version)),
+		false)
+}
+
+func sortedSigners(t *trc.TRC) []string {
+	var signers []string
+	for signer := range t.Signatures {
+		signers = append(signers, signer)
+	}
+	sort.Strings(signers)
+	return signers
+}
+
+func (r *report) writeText(w io.Writer) {
+	fmt.Fprintf(w, "File: %s\nType: %s\n", r.File, r.Type)
+	switch c := r.Content.(type) {
+	case *trc.TRC:
+		writeTrc(w, c)
+	case *cert.Chain:
+		fmt.Fprintf(w, "Leaf certificate:\n")
+		writeCert(w, c.Leaf)
+		fmt.Fprintf(w, "Issuer certificate:\n")
+		writeCert(w, c.Issuer)
+	case *cert.Certificate:
+		writeCert(w, c)
+	}
+	fmt.Fprintf(w, "Signatures:\n")
+	for _, s := range r.Signatures {
+		fmt.Fprintf(w, "    %s: %s\n", s.Signer, s.Status)
+	}
+	if len(r.Warnings) > 0 {
+		fmt.Fprintf(w, "Warnings:\n")
+		for _, warning := range r.Warnings {
+			fmt.Fprintf(w, "    %s\n", warning)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+func writeTrc(w io.Writer, t *trc.TRC) {
+	fmt.Fprintf(w, "    ISD:            %d\n", t.ISD)
+	fmt.Fprintf(w, "    Version:        %d\n", t.Version)
+	fmt.Fprintf(w, "    Description:    %s\n", t.Description)
+	fmt.Fprintf(w, "    CreationTime:   %s\n", fmtTime(t.CreationTime))
+	fmt.Fprintf(w, "    ExpirationTime: %s\n", fmtTime(t.ExpirationTime))
+	fmt.Fprintf(w, "    GracePeriod:    %s\n", time.Duration(t.GracePeriod)*time.Second)
+	fmt.Fprintf(w, "    QuorumTRC:      %d\n", t.QuorumTRC)
+	fmt.Fprintf(w, "    Quarantine:     %t\n", t.Quarantine)
+	fmt.Fprintf(w, "    CoreASes:\n")
+	ases := t.CoreASes.ASList()
+	sort.Slice(ases, func(i, j int) bool { return ases[i].IAInt() < ases[j].IAInt() })
+	for _, ia := range ases {
+		coreAS := t.CoreASes[ia]
+		fmt.Fprintf(w, "        %s\n", ia)
+		fmt.Fprintf(w, "            OnlineKey:  %s %s\n", coreAS.OnlineKeyAlg,
+			fmtKey(coreAS.OnlineKey))
+		fmt.Fprintf(w, "            OfflineKey: %s %s\n", coreAS.OfflineKeyAlg,
+			fmtKey(coreAS.OfflineKey))
+	}
+}
+
+func writeCert(w io.Writer, c *cert.Certificate) {
+	fmt.Fprintf(w, "    Subject:        %s\n", c.Subject)
+	fmt.Fprintf(w, "    Issuer:         %s\n", c.Issuer)
+	fmt.Fprintf(w, "    Version:        %d\n", c.Version)
+	fmt.Fprintf(w, "    TRCVersion:     %d\n", c.TRCVersion)
+	fmt.Fprintf(w, "    CanIssue:       %t\n", c.CanIssue)
+	fmt.Fprintf(w, "    IssuingTime:    %s\n", fmtTime(c.IssuingTime))
+	fmt.Fprintf(w, "    ExpirationTime: %s\n", fmtTime(c.ExpirationTime))
+	fmt.Fprintf(w, "    SubjectSignKey: %s %s\n", c.SignAlgorithm, fmtKey(c.SubjectSignKey))
+	fmt.Fprintf(w, "    SubjectEncKey:  %s %s\n", c.EncAlgorithm, fmtKey(c.SubjectEncKey))
+	if c.Comment != "" {
+		fmt.Fprintf(w, "    Comment:        %s\n", c.Comment)
+	}
+}
+
+func fmtTime(t uint32) string {
+	return util.TimeToString(util.SecsToTime(t))
+}
+
+func fmtKey(key common.RawBytes) string {
+	return base64.StdEncoding.EncodeToString(key)
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package show
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/scrypto/trc"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestExpiryWarnings(t *testing.T) {
+	Convey("expiryWarnings", t, func() {
+		now := uint32(1000000)
+		Convey("Validity error is reported", func() {
+			w := expiryWarnings(errors.New("expired"), now-1, now, time.Hour)
+			SoMsg("warnings", w, ShouldResemble, []string{"expired"})
+		})
+		Convey("Expiring soon is reported", func() {
+			w := expiryWarnings(nil, now+60, now, time.Hour)
+			SoMsg("warnings", len(w), ShouldEqual, 1)
+		})
+		Convey("Far expiration is not reported", func() {
+			w := expiryWarnings(nil, now+7200, now, time.Hour)
+			SoMsg("warnings", w, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestTrcSigStatus(t *testing.T) {
+	Convey("trcSigStatus", t, func() {
+		ia110 := xtest.MustParseIA("1-ff00:0:110")
+		ia120 := xtest.MustParseIA("1-ff00:0:120")
+		tvr := &trc.TRCVerResult{
+			Verified: []addr.IA{ia110},
+			Failed:   map[addr.IA]error{ia120: errors.New("invalid")},
+		}
+		SoMsg("valid", trcSigStatus(ia110.String(), tvr), ShouldEqual, sigValid)
+		SoMsg("failed", trcSigStatus(ia120.String(), tvr), ShouldEqual, "invalid")
+		SoMsg("non-voter", trcSigStatus("1-ff00:0:130", tvr), ShouldEqual,
+			"not a voting core AS")
+	})
+}
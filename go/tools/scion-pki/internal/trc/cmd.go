@@ -20,7 +20,7 @@ import (
 
 var Cmd = &cobra.Command{
 	Use:   "trc",
-	Short: "Generate TRCs for the SCION control plane PKI",
+	Short: "Generate, update and sign TRCs for the SCION control plane PKI",
 	Long: `
 'trc' can be used to generate Trust Root Configuration (TRC) files used in the SCION control
 plane PKI.
@@ -62,6 +62,22 @@ and a section 'TRC' with the following values:
 		integer reprensenting the time the previous TRC is still valid in seconds
 	QuorumTRC [required]
 		integer reprensenting the number of core ASes needed to sign a new TRC.
+
+'gen' creates a base TRC and signs it with the online keys of all core ASes, which
+must be available locally. Subsequent TRC versions are created and signed by the
+voting core ASes, i.e., the core ASes of the previous TRC, as follows:
+	1. 'trc update <selector>' creates ISD<X>-V<N>.trc.unsigned from isd.ini
+	   and ISD<X>-V<N-1>.trc. Core ASes without local root keys keep the keys
+	   of the previous TRC.
+	2. Every voting core AS runs 'trc sign <unsigned TRC> <ISD-AS>' on its own
+	   machine. This writes ISD<X>-V<N>.trc.<AS>.partial, which only contains
+	   the signature of that AS. The signature must be created with the online
+	   key of the previous TRC. If the update rolls the online key of the AS,
+	   the previous key is passed with --key.
+	3. 'trc combine <unsigned TRC> <partial TRC>...' merges the signatures and
+	   writes ISD<X>-V<N>.trc once the quorum of the previous TRC is reached.
+	4. 'trc verify <TRC>...' checks the signatures against the previous TRC.
+The previous TRC is always looked up in the directory of the TRC file.
 `,
 }
 
@@ -74,6 +90,50 @@ var gen = &cobra.Command{
 	},
 }
 
+var update = &cobra.Command{
+	Use:   "update",
+	Short: "Create unsigned TRC updates",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runUpdateTrc(args)
+	},
+}
+
+var signKeyPath string
+
+var sign = &cobra.Command{
+	Use:   "sign <trc> <ISD-AS>",
+	Short: "Sign a TRC with the online key of a voting core AS",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runSignTrc(args)
+	},
+}
+
+var combine = &cobra.Command{
+	Use:   "combine <trc> <partial>...",
+	Short: "Combine partially signed TRCs",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runCombineTrc(args)
+	},
+}
+
+var verify = &cobra.Command{
+	Use:   "verify <trc>...",
+	Short: "Verify TRCs against their predecessor",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runVerifyTrc(args)
+	},
+}
+
 func init() {
 	Cmd.AddCommand(gen)
+	Cmd.AddCommand(update)
+	Cmd.AddCommand(sign)
+	sign.Flags().StringVar(&signKeyPath, "key", "",
+		"Online key file to sign with (default: the online key of the AS)")
+	Cmd.AddCommand(combine)
+	Cmd.AddCommand(verify)
 }
@@ -0,0 +1,62 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/scrypto/trc"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/pkicmn"
+)
+
+func runCombineTrc(args []string) {
+	if err := combineTrc(args[0], args[1:]); err != nil {
+		pkicmn.ErrorAndExit("Error combining TRC: %s\n", err)
+	}
+	os.Exit(0)
+}
+
+// combineTrc merges the signatures of the partial TRCs into the unsigned TRC
+// at path. The combined TRC is only written if it is signed by a quorum of
+// the voting core ASes.
+func combineTrc(path string, partials []string) error {
+	t, err := trc.TRCFromFile(path, false)
+	if err != nil {
+		return common.NewBasicError("Error loading TRC", err, "path", path)
+	}
+	t.Signatures = make(map[string]common.RawBytes)
+	for _, p := range partials {
+		partial, err := trc.TRCFromFile(p, false)
+		if err != nil {
+			return common.NewBasicError("Error loading partial TRC", err, "path", p)
+		}
+		if err := t.MergeSignatures(partial); err != nil {
+			return common.NewBasicError("Error merging signatures", err, "path", p)
+		}
+	}
+	dir := filepath.Dir(path)
+	if _, err := verifyTrc(t, dir); err != nil {
+		return err
+	}
+	raw, err := t.JSON(true)
+	if err != nil {
+		return common.NewBasicError("Error json-encoding TRC", err)
+	}
+	fname := fmt.Sprintf(pkicmn.TrcNameFmt, t.ISD, t.Version)
+	return pkicmn.WriteToFile(raw, filepath.Join(dir, fname), 0644)
+}
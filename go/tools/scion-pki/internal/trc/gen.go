@@ -20,8 +20,6 @@ import (
 	"path/filepath"
 	"time"
 
-	"golang.org/x/crypto/ed25519"
-
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/keyconf"
@@ -78,12 +76,40 @@ func genTrc(isd addr.ISD) error {
 }
 
 func newTrc(isd addr.ISD, iconf *conf.Isd, path string) (*trc.TRC, error) {
+	t := baseTrc(isd, iconf)
+	// Load the online/offline root keys.
+	var ases []*coreAS
+	for _, cia := range iconf.Trc.CoreIAs {
+		as, err := loadCoreAS(cia)
+		if err != nil {
+			return nil, err
+		}
+		ases = append(ases, as)
+	}
+	for _, as := range ases {
+		entry, err := as.entry()
+		if err != nil {
+			return nil, err
+		}
+		t.CoreASes[as.IA] = entry
+	}
+	// Sign the TRC.
+	for _, as := range ases {
+		if err := t.Sign(as.IA.String(), as.OnlineKey, as.OnlineKeyAlg); err != nil {
+			return nil, common.NewBasicError("Error signing TRC", err, "signer", as.IA)
+		}
+	}
+	return t, nil
+}
+
+// baseTrc creates an unsigned TRC without core ASes from the isd.ini values.
+func baseTrc(isd addr.ISD, iconf *conf.Isd) *trc.TRC {
 	issuingTime := iconf.Trc.IssuingTime
 	if issuingTime == 0 {
 		issuingTime = util.TimeToSecs(time.Now())
 	}
-	t := &trc.TRC{
-		CreationTime:   iconf.Trc.IssuingTime,
+	return &trc.TRC{
+		CreationTime:   issuingTime,
 		Description:    iconf.Desc,
 		ExpirationTime: issuingTime + uint32(iconf.Trc.Validity.Seconds()),
 		GracePeriod:    uint32(iconf.Trc.GracePeriod.Seconds()),
@@ -96,73 +122,45 @@ func newTrc(isd addr.ISD, iconf *conf.Isd, path string) (*trc.TRC, error) {
 		RootCAs:        make(map[string]*trc.RootCA),
 		CertLogs:       make(map[string]*trc.CertLog),
 	}
-	// Load the online/offline root keys.
-	var ases []coreAS
-	for _, cia := range iconf.Trc.CoreIAs {
-		var as coreAS
-		var err error
-		as.IA = cia
-		cpath := filepath.Join(pkicmn.GetAsPath(pkicmn.RootDir, cia), conf.AsConfFileName)
-		a, err := conf.LoadAsConf(filepath.Dir(cpath))
-		if err != nil {
-			return nil, common.NewBasicError("Error loading as.ini", err, "path", cpath)
-		}
-		if a.KeyAlgorithms == nil {
-			return nil, common.NewBasicError("Section missing from as.ini",
-				nil, "path", cpath, "section", conf.KeyAlgSectionName)
-		}
-		as.OnlineKeyAlg = scrypto.Ed25519
-		if a.KeyAlgorithms.Online != "" {
-			as.OnlineKeyAlg = a.KeyAlgorithms.Online
-		}
-		as.OfflineKeyAlg = scrypto.Ed25519
-		if a.KeyAlgorithms.Offline != "" {
-			as.OfflineKeyAlg = a.KeyAlgorithms.Offline
-		}
-		keysPath := filepath.Join(pkicmn.GetAsPath(pkicmn.OutDir, cia), pkicmn.KeysDir)
-		as.OnlineKey, err = keyconf.LoadKey(filepath.Join(keysPath, keyconf.OnKeyFile),
-			as.OnlineKeyAlg)
-		if err != nil {
-			return nil, common.NewBasicError("Error loading online key", err)
-		}
-		as.OfflineKey, err = keyconf.LoadKey(
-			filepath.Join(keysPath, keyconf.OffKeyFile), as.OfflineKeyAlg)
-		if err != nil {
-			return nil, common.NewBasicError("Error loading offline key", err)
-		}
-		ases = append(ases, as)
+}
+
+// loadCoreAS loads the key algorithms from as.ini and the online/offline root
+// keys of the core AS cia.
+func loadCoreAS(cia addr.IA) (*coreAS, error) {
+	as := &coreAS{IA: cia}
+	cpath := filepath.Join(pkicmn.GetAsPath(pkicmn.RootDir, cia), conf.AsConfFileName)
+	a, err := conf.LoadAsConf(filepath.Dir(cpath))
+	if err != nil {
+		return nil, common.NewBasicError("Error loading as.ini", err, "path", cpath)
 	}
-	for _, as := range ases {
-		pubKeyOnline, err := getPubKey(as.OnlineKey, as.OnlineKeyAlg)
-		if err != nil {
-			return nil, err
-		}
-		pubKeyOffline, err := getPubKey(as.OfflineKey, as.OfflineKeyAlg)
-		if err != nil {
-			return nil, err
-		}
-		t.CoreASes[as.IA] = &trc.CoreAS{
-			OnlineKey:     pubKeyOnline,
-			OnlineKeyAlg:  as.OnlineKeyAlg,
-			OfflineKey:    pubKeyOffline,
-			OfflineKeyAlg: as.OfflineKeyAlg,
-		}
+	if a.KeyAlgorithms == nil {
+		return nil, common.NewBasicError("Section missing from as.ini",
+			nil, "path", cpath, "section", conf.KeyAlgSectionName)
 	}
-	// Sign the TRC.
-	for _, as := range ases {
-		if err := t.Sign(as.IA.String(), as.OnlineKey, as.OnlineKeyAlg); err != nil {
-			return nil, common.NewBasicError("Error signing TRC", err, "signer", as.IA)
-		}
+	as.OnlineKeyAlg = scrypto.Ed25519
+	if a.KeyAlgorithms.Online != "" {
+		as.OnlineKeyAlg = a.KeyAlgorithms.Online
 	}
-	return t, nil
+	as.OfflineKeyAlg = scrypto.Ed25519
+	if a.KeyAlgorithms.Offline != "" {
+		as.OfflineKeyAlg = a.KeyAlgorithms.Offline
+	}
+	keysPath := filepath.Join(pkicmn.GetAsPath(pkicmn.OutDir, cia), pkicmn.KeysDir)
+	as.OnlineKey, err = keyconf.LoadKey(filepath.Join(keysPath, keyconf.OnKeyFile),
+		as.OnlineKeyAlg)
+	if err != nil {
+		return nil, common.NewBasicError("Error loading online key", err)
+	}
+	as.OfflineKey, err = keyconf.LoadKey(
+		filepath.Join(keysPath, keyconf.OffKeyFile), as.OfflineKeyAlg)
+	if err != nil {
+		return nil, common.NewBasicError("Error loading offline key", err)
+	}
+	return as, nil
 }
 
 func getPubKey(privKey common.RawBytes, keyType string) (common.RawBytes, error) {
-	switch keyType {
-	case scrypto.Ed25519:
-		return common.RawBytes(ed25519.PrivateKey(privKey).Public().(ed25519.PublicKey)), nil
-	}
-	return nil, common.NewBasicError("Unsupported key type", nil, "type", keyType)
+	return scrypto.PublicKey(privKey, keyType)
 }
 
 type coreAS struct {
@@ -172,3 +170,21 @@ type coreAS struct {
 	OnlineKeyAlg  string
 	OfflineKeyAlg string
 }
+
+// entry returns the TRC entry containing the public keys of the core AS.
+func (as *coreAS) entry() (*trc.CoreAS, error) {
+	pubKeyOnline, err := getPubKey(as.OnlineKey, as.OnlineKeyAlg)
+	if err != nil {
+		return nil, err
+	}
+	pubKeyOffline, err := getPubKey(as.OfflineKey, as.OfflineKeyAlg)
+	if err != nil {
+		return nil, err
+	}
+	return &trc.CoreAS{
+		OnlineKey:     pubKeyOnline,
+		OnlineKeyAlg:  as.OnlineKeyAlg,
+		OfflineKey:    pubKeyOffline,
+		OfflineKeyAlg: as.OfflineKeyAlg,
+	}, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/scrypto/trc"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/pkicmn"
+)
+
+func runSignTrc(args []string) {
+	ia, err := addr.IAFromString(args[1])
+	if err != nil {
+		pkicmn.ErrorAndExit("Error parsing ISD-AS: %s\n", err)
+	}
+	if err = signTrc(args[0], ia, signKeyPath); err != nil {
+		pkicmn.ErrorAndExit("Error signing TRC: %s\n", err)
+	}
+	os.Exit(0)
+}
+
+// signTrc signs the unsigned TRC at path with the online key of the voting
+// core AS ia. The key must match the online key of ia in the previous TRC. If
+// keyPath is empty, the online key in the keys directory of ia is used. This
+// is not possible if the update rolls the online key, in which case keyPath
+// must point to the previous online key. The result is written next to the
+// TRC as a partial TRC that only contains the signature of ia.
+func signTrc(path string, ia addr.IA, keyPath string) error {
+	t, err := trc.TRCFromFile(path, false)
+	if err != nil {
+		return common.NewBasicError("Error loading TRC", err, "path", path)
+	}
+	if ia.I != t.ISD {
+		return common.NewBasicError("Signer not part of ISD", nil, "ia", ia, "isd", t.ISD)
+	}
+	// The voting core ASes are defined by the previous TRC. A base TRC is
+	// signed by its own core ASes.
+	voters := t
+	if t.Version > 1 {
+		if voters, err = loadPrevTrc(filepath.Dir(path), t.ISD, t.Version); err != nil {
+			return err
+		}
+	}
+	voter, ok := voters.CoreASes[ia]
+	if !ok {
+		return common.NewBasicError("Signer is not a voting core AS", nil,
+			"ia", ia, "version", voters.Version)
+	}
+	key, keyAlg, err := loadSignKey(ia, keyPath, voter.OnlineKeyAlg)
+	if err != nil {
+		return err
+	}
+	pub, err := getPubKey(key, keyAlg)
+	if err != nil {
+		return err
+	}
+	if keyAlg != voter.OnlineKeyAlg || !bytes.Equal(pub, voter.OnlineKey) {
+		return common.NewBasicError("Online key does not match voting TRC, "+
+			"use --key to sign with the previous online key", nil,
+			"ia", ia, "version", voters.Version)
+	}
+	t.Signatures = make(map[string]common.RawBytes)
+	if err := t.Sign(ia.String(), key, keyAlg); err != nil {
+		return common.NewBasicError("Error signing TRC", err, "signer", ia)
+	}
+	raw, err := t.JSON(true)
+	if err != nil {
+		return common.NewBasicError("Error json-encoding TRC", err)
+	}
+	fname := fmt.Sprintf(pkicmn.TrcNameFmt, t.ISD, t.Version) +
+		fmt.Sprintf(partialSuffixFmt, ia.FileFmt(false))
+	return pkicmn.WriteToFile(raw, filepath.Join(filepath.Dir(path), fname), 0644)
+}
+
+// loadSignKey loads the online key of ia and its algorithm. If keyPath is set,
+// the key is loaded from it with the algorithm alg of the voting TRC.
+// Otherwise, the key in the keys directory of ia is loaded.
+func loadSignKey(ia addr.IA, keyPath, alg string) (common.RawBytes, string, error) {
+	if keyPath != "" {
+		key, err := keyconf.LoadKey(keyPath, alg)
+		if err != nil {
+			return nil, "", common.NewBasicError("Error loading online key", err,
+				"path", keyPath)
+		}
+		return key, alg, nil
+	}
+	as, err := loadCoreAS(ia)
+	if err != nil {
+		return nil, "", err
+	}
+	return as.OnlineKey, as.OnlineKeyAlg, nil
+}
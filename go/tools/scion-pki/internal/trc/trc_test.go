@@ -0,0 +1,153 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/scrypto/trc"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/conf"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/pkicmn"
+)
+
+var (
+	ia110 = xtest.MustParseIA("1-ff00:0:110")
+	ia120 = xtest.MustParseIA("1-ff00:0:120")
+)
+
+func TestUpdateSignCombineVerify(t *testing.T) {
+	Convey("Given a base TRC of two core ASes", t, func() {
+		dir, cleanF := setupPkiDir(t)
+		defer cleanF()
+		now := util.TimeToSecs(time.Now())
+		writeIsdConf(t, 1, now-10)
+		xtest.FailOnErr(t, genTrc(1))
+		trcDir := filepath.Join(pkicmn.GetIsdPath(dir, 1), pkicmn.TRCsDir)
+		unsigned := filepath.Join(trcDir, "ISD1-V2.trc"+unsignedSuffix)
+		partial := func(ia addr.IA) string {
+			return filepath.Join(trcDir, "ISD1-V2.trc"+
+				fmt.Sprintf(partialSuffixFmt, ia.FileFmt(false)))
+		}
+		Convey("An update with the same keys is signed, combined and verified", func() {
+			writeIsdConf(t, 2, now)
+			xtest.FailOnErr(t, updateTrc(1))
+			SoMsg("sign 110", signTrc(unsigned, ia110, ""), ShouldBeNil)
+			SoMsg("sign 120", signTrc(unsigned, ia120, ""), ShouldBeNil)
+			Convey("Combining requires the quorum", func() {
+				err := combineTrc(unsigned, []string{partial(ia110)})
+				SoMsg("err", err, ShouldNotBeNil)
+				_, err = os.Stat(filepath.Join(trcDir, "ISD1-V2.trc"))
+				SoMsg("not written", os.IsNotExist(err), ShouldBeTrue)
+			})
+			Convey("The combined TRC is verified", func() {
+				err := combineTrc(unsigned, []string{partial(ia110), partial(ia120)})
+				SoMsg("err", err, ShouldBeNil)
+				tvr := verifyFile(t, filepath.Join(trcDir, "ISD1-V2.trc"))
+				SoMsg("verified", len(tvr.Verified), ShouldEqual, 2)
+			})
+		})
+		Convey("An update that rolls the online key is signed with the previous key", func() {
+			oldKey := filepath.Join(dir, "old-online-root.seed")
+			keysDir := filepath.Join(pkicmn.GetAsPath(dir, ia110), pkicmn.KeysDir)
+			xtest.FailOnErr(t, os.Rename(filepath.Join(keysDir, keyconf.OnKeyFile), oldKey))
+			writeKey(t, filepath.Join(keysDir, keyconf.OnKeyFile), 3)
+			writeIsdConf(t, 2, now)
+			xtest.FailOnErr(t, updateTrc(1))
+			SoMsg("new key", signTrc(unsigned, ia110, ""), ShouldNotBeNil)
+			SoMsg("sign 110", signTrc(unsigned, ia110, oldKey), ShouldBeNil)
+			SoMsg("sign 120", signTrc(unsigned, ia120, ""), ShouldBeNil)
+			err := combineTrc(unsigned, []string{partial(ia110), partial(ia120)})
+			SoMsg("err", err, ShouldBeNil)
+			path := filepath.Join(trcDir, "ISD1-V2.trc")
+			tvr := verifyFile(t, path)
+			SoMsg("verified", len(tvr.Verified), ShouldEqual, 2)
+			t2, err := trc.TRCFromFile(path, false)
+			xtest.FailOnErr(t, err)
+			newKey, err := keyconf.LoadKey(filepath.Join(keysDir, keyconf.OnKeyFile),
+				scrypto.Ed25519)
+			xtest.FailOnErr(t, err)
+			pub, err := getPubKey(newKey, scrypto.Ed25519)
+			xtest.FailOnErr(t, err)
+			SoMsg("rolled key", bytes.Equal(t2.CoreASes[ia110].OnlineKey, pub), ShouldBeTrue)
+		})
+	})
+}
+
+// setupPkiDir creates the root directory with the configuration and keys of
+// the core ASes 1-ff00:0:110 and 1-ff00:0:120.
+func setupPkiDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "scion-pki-trc")
+	xtest.FailOnErr(t, err)
+	rootDir, outDir, force, quiet := pkicmn.RootDir, pkicmn.OutDir, pkicmn.Force, pkicmn.Quiet
+	pkicmn.RootDir, pkicmn.OutDir, pkicmn.Force, pkicmn.Quiet = dir, dir, true, true
+	for i, ia := range []addr.IA{ia110, ia120} {
+		asDir := pkicmn.GetAsPath(dir, ia)
+		keysDir := filepath.Join(asDir, pkicmn.KeysDir)
+		xtest.FailOnErr(t, os.MkdirAll(keysDir, 0755))
+		asConf := conf.NewTemplateAsConf(ia, 1, true)
+		asConf.AsCert.RawValidity = "3d"
+		asConf.IssuerCert.RawValidity = "7d"
+		err := asConf.Write(filepath.Join(asDir, conf.AsConfFileName), true)
+		xtest.FailOnErr(t, err)
+		writeKey(t, filepath.Join(keysDir, keyconf.OnKeyFile), byte(2*i+1))
+		writeKey(t, filepath.Join(keysDir, keyconf.OffKeyFile), byte(2*i+2))
+	}
+	return dir, func() {
+		pkicmn.RootDir, pkicmn.OutDir, pkicmn.Force, pkicmn.Quiet = rootDir, outDir, force, quiet
+		os.RemoveAll(dir)
+	}
+}
+
+func writeIsdConf(t *testing.T, version uint64, issuingTime uint32) {
+	isd := &conf.Isd{
+		Trc: &conf.Trc{
+			Version:     version,
+			IssuingTime: issuingTime,
+			Validity:    24 * time.Hour,
+			CoreIAs:     []addr.IA{ia110, ia120},
+			QuorumTRC:   2,
+		},
+	}
+	path := filepath.Join(pkicmn.GetIsdPath(pkicmn.RootDir, 1), conf.IsdConfFileName)
+	xtest.FailOnErr(t, isd.Write(path, true))
+}
+
+// writeKey writes an Ed25519 seed filled with b.
+func writeKey(t *testing.T, path string, b byte) {
+	seed := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+	xtest.FailOnErr(t, ioutil.WriteFile(path, []byte(seed), 0600))
+}
+
+func verifyFile(t *testing.T, path string) *trc.TRCVerResult {
+	t2, err := trc.TRCFromFile(path, false)
+	xtest.FailOnErr(t, err)
+	tvr, err := verifyTrc(t2, filepath.Dir(path))
+	xtest.FailOnErr(t, err)
+	return tvr
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/keyconf"
+	"github.com/scionproto/scion/go/lib/scrypto/trc"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/conf"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/pkicmn"
+)
+
+const (
+	// unsignedSuffix is appended to the file name of a TRC that has not been
+	// signed yet.
+	unsignedSuffix = ".unsigned"
+	// partialSuffixFmt is appended to the file name of a TRC that only
+	// contains the signature of a single core AS.
+	partialSuffixFmt = ".%s.partial"
+)
+
+func runUpdateTrc(args []string) {
+	asMap, err := pkicmn.ProcessSelector(args[0])
+	if err != nil {
+		pkicmn.ErrorAndExit("Error: %s\n", err)
+	}
+	for isd := range asMap {
+		if err = updateTrc(isd); err != nil {
+			pkicmn.ErrorAndExit("Error updating TRC: %s\n", err)
+		}
+	}
+	os.Exit(0)
+}
+
+// updateTrc creates the unsigned successor of the previous TRC according to
+// isd.ini.
+func updateTrc(isd addr.ISD) error {
+	confDir := pkicmn.GetIsdPath(pkicmn.RootDir, isd)
+	// Check that isd.ini exists, otherwise skip directory.
+	cpath := filepath.Join(confDir, conf.IsdConfFileName)
+	if _, err := os.Stat(cpath); os.IsNotExist(err) {
+		return nil
+	}
+	iconf, err := conf.LoadIsdConf(confDir)
+	if err != nil {
+		return common.NewBasicError("Error loading TRC conf", err)
+	}
+	if iconf.Trc.Version <= 1 {
+		return common.NewBasicError("Base TRC cannot be updated, use 'trc gen'", nil,
+			"version", iconf.Trc.Version)
+	}
+	pkicmn.QuietPrint("Updating TRC for ISD %d to version %d\n", isd, iconf.Trc.Version)
+	outDir := filepath.Join(pkicmn.GetIsdPath(pkicmn.OutDir, isd), pkicmn.TRCsDir)
+	prev, err := loadPrevTrc(outDir, isd, iconf.Trc.Version)
+	if err != nil {
+		return err
+	}
+	t := baseTrc(isd, iconf)
+	if t.CreationTime < prev.CreationTime+prev.GracePeriod {
+		return common.NewBasicError("Issuing time within grace period of previous TRC", nil,
+			"issuingTime", t.CreationTime, "earliest", prev.CreationTime+prev.GracePeriod)
+	}
+	for _, cia := range iconf.Trc.CoreIAs {
+		entry, err := coreASEntry(cia, prev)
+		if err != nil {
+			return err
+		}
+		t.CoreASes[cia] = entry
+	}
+	raw, err := t.JSON(true)
+	if err != nil {
+		return common.NewBasicError("Error json-encoding TRC", err)
+	}
+	fname := fmt.Sprintf(pkicmn.TrcNameFmt, isd, iconf.Trc.Version) + unsignedSuffix
+	return pkicmn.WriteToFile(raw, filepath.Join(outDir, fname), 0644)
+}
+
+// coreASEntry returns the TRC entry for the core AS cia. If the root keys of
+// cia are available locally, they are used. Otherwise, the entry of the
+// previous TRC is carried over.
+func coreASEntry(cia addr.IA, prev *trc.TRC) (*trc.CoreAS, error) {
+	keyPath := filepath.Join(pkicmn.GetAsPath(pkicmn.OutDir, cia), pkicmn.KeysDir,
+		keyconf.OnKeyFile)
+	if _, err := os.Stat(keyPath); err == nil {
+		as, err := loadCoreAS(cia)
+		if err != nil {
+			return nil, err
+		}
+		return as.entry()
+	}
+	entry, ok := prev.CoreASes[cia]
+	if !ok {
+		return nil, common.NewBasicError("No root keys for new core AS", nil,
+			"ia", cia, "path", keyPath)
+	}
+	pkicmn.QuietPrint("Keeping keys of %s from previous TRC\n", cia)
+	return entry, nil
+}
+
+// loadPrevTrc loads the predecessor of the TRC with the given version from dir.
+func loadPrevTrc(dir string, isd addr.ISD, version uint64) (*trc.TRC, error) {
+	fname := fmt.Sprintf(pkicmn.TrcNameFmt, isd, version-1)
+	t, err := trc.TRCFromFile(filepath.Join(dir, fname), false)
+	if err != nil {
+		return nil, common.NewBasicError("Error loading previous TRC", err, "file", fname)
+	}
+	return t, nil
+}
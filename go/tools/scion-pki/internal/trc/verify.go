@@ -0,0 +1,63 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/scionproto/scion/go/lib/scrypto/trc"
+	"github.com/scionproto/scion/go/tools/scion-pki/internal/pkicmn"
+)
+
+func runVerifyTrc(args []string) {
+	exitStatus := 0
+	for _, trcPath := range args {
+		t, err := trc.TRCFromFile(trcPath, false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading %s: %s\n", trcPath, err)
+			exitStatus = 2
+			continue
+		}
+		tvr, err := verifyTrc(t, filepath.Dir(trcPath))
+		if err != nil {
+			pkicmn.QuietPrint("Verification of %s FAILED. Reason: %s\n", trcPath, err)
+			if tvr != nil {
+				for ia, err := range tvr.Failed {
+					pkicmn.QuietPrint("  %s: %s\n", ia, err)
+				}
+			}
+			exitStatus = 2
+			continue
+		}
+		pkicmn.QuietPrint("Verification of %s SUCCEEDED. Signed by %v (quorum %d)\n",
+			trcPath, tvr.Verified, tvr.Quorum)
+	}
+	os.Exit(exitStatus)
+}
+
+// verifyTrc verifies t against its predecessor in dir. A base TRC is verified
+// against itself.
+func verifyTrc(t *trc.TRC, dir string) (*trc.TRCVerResult, error) {
+	if t.Version <= 1 {
+		return t.VerifyBase()
+	}
+	prev, err := loadPrevTrc(dir, t.ISD, t.Version)
+	if err != nil {
+		return nil, err
+	}
+	return t.Verify(prev)
+}
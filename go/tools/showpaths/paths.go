@@ -27,6 +27,7 @@ import (
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/env"
 	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/naming"
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/scmp"
 	"github.com/scionproto/scion/go/lib/snet"
@@ -35,7 +36,7 @@ import (
 )
 
 var (
-	dstIAStr     = flag.String("dstIA", "", "Destination IA address: ISD-AS")
+	dstIAStr     = flag.String("dstIA", "", "Destination IA address: ISD-AS, or a host name")
 	srcIAStr     = flag.String("srcIA", "", "Source IA address: ISD-AS")
 	sciondPath   = flag.String("sciond", "", "SCIOND socket path")
 	timeout      = flag.Duration("timeout", 5*time.Second, "Timeout in seconds")
@@ -101,6 +102,25 @@ func main() {
 	}
 }
 
+// resolveIA parses s as an ISD-AS. If that fails, s is resolved as a host name
+// and the ISD-AS of its first address is returned.
+func resolveIA(s string) (addr.IA, error) {
+	ia, err := addr.IAFromString(s)
+	if err == nil || !naming.ValidName(s) {
+		return ia, err
+	}
+	ctx, cancelF := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelF()
+	addrs, err := naming.Resolve(ctx, s)
+	if err != nil {
+		return addr.IA{}, err
+	}
+	if len(addrs) == 0 {
+		return addr.IA{}, common.NewBasicError(naming.ErrNotFound, nil, "name", s)
+	}
+	return addrs[0].IA, nil
+}
+
 func validateFlags() {
 	flag.Parse()
 	var err error
@@ -111,7 +131,7 @@ func validateFlags() {
 	if *dstIAStr == "" {
 		LogFatal("Missing destination IA")
 	} else {
-		dstIA, err = addr.IAFromString(*dstIAStr)
+		dstIA, err = resolveIA(*dstIAStr)
 		if err != nil {
 			LogFatal("Unable to parse destination IA", "err", err)
 		}
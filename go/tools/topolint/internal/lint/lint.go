@@ -0,0 +1,438 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint checks a set of AS topologies for consistency.
+//
+// Each AS topology is only validated locally when it is loaded. Package lint
+// builds the inter-AS graph from the topologies of all ASes in a deployment
+// and checks that both ends of every link agree with each other. The remote
+// end of an interface is the interface of the neighboring AS that points back
+// to the local AS and whose overlay addresses mirror the local ones.
+//
+// The following problems are reported:
+//  - topologies that can not be loaded,
+//  - differing copies of the same AS topology,
+//  - interface IDs used by multiple border routers of an AS,
+//  - interfaces pointing to ASes that are not part of the set,
+//  - interfaces without (or with more than one) matching remote interface,
+//  - links whose overlay addresses, link types or MTUs do not match,
+//  - ASes that can not be reached from the core.
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/overlay"
+	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/proto"
+)
+
+// Kinds of findings.
+const (
+	KindInvalidTopology    = "invalid_topology"
+	KindInconsistentCopies = "inconsistent_copies"
+	KindDuplicateIFID      = "duplicate_ifid"
+	KindUnknownAS          = "unknown_as"
+	KindDanglingInterface  = "dangling_interface"
+	KindAmbiguousLink      = "ambiguous_link"
+	KindOverlayMismatch    = "overlay_mismatch"
+	KindLinkTypeMismatch   = "link_type_mismatch"
+	KindMTUMismatch        = "mtu_mismatch"
+	KindUnreachableAS      = "unreachable_as"
+)
+
+// Source is the content of a topology file.
+type Source struct {
+	File string
+	Raw  common.RawBytes
+}
+
+// LoadDir reads all files called name in the directory tree rooted at dir.
+func LoadDir(dir, name string) ([]Source, error) {
+	var srcs []Source
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() != name {
+			return nil
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		srcs = append(srcs, Source{File: path, Raw: b})
+		return nil
+	})
+	if err != nil {
+		return nil, common.NewBasicError("Unable to read topologies", err, "dir", dir)
+	}
+	return srcs, nil
+}
+
+// Interface identifies an interface of an AS.
+type Interface struct {
+	IA   addr.IA
+	IFID common.IFIDType
+}
+
+func (i Interface) String() string {
+	return fmt.Sprintf("%s#%d", i.IA, i.IFID)
+}
+
+func (i Interface) less(o Interface) bool {
+	if i.IA != o.IA {
+		return i.IA.IAInt() < o.IA.IAInt()
+	}
+	return i.IFID < o.IFID
+}
+
+// Link is an inter-AS link whose ends were matched.
+type Link struct {
+	A Interface
+	B Interface
+}
+
+// Finding describes a single problem.
+type Finding struct {
+	Kind string
+	// IA is the AS the problem was found in. It is the zero value if the
+	// topology could not be loaded.
+	IA   addr.IA
+	IFID common.IFIDType `json:",omitempty"`
+	File string
+	Msg  string
+}
+
+func (f Finding) String() string {
+	var where string
+	switch {
+	case f.IA.IsZero():
+		where = f.File
+	case f.IFID != 0:
+		where = Interface{IA: f.IA, IFID: f.IFID}.String()
+	default:
+		where = f.IA.String()
+	}
+	return fmt.Sprintf("%s: %s: %s", where, f.Kind, f.Msg)
+}
+
+// Report is the result of checking a set of topologies.
+type Report struct {
+	ASes     []addr.IA
+	Links    []Link
+	Findings []Finding
+}
+
+// Check checks the topologies in srcs for consistency. Copies of the same AS
+// topology (e.g., one for each service of the AS) are allowed, as long as
+// they are identical.
+func Check(srcs []Source) *Report {
+	c := &checker{
+		ases:    make(map[addr.IA]*as),
+		pairs:   make(map[Interface]Interface),
+		parents: make(map[addr.IA][]addr.IA),
+		cores:   make(map[addr.IA][]addr.IA),
+		report:  &Report{},
+	}
+	c.load(srcs)
+	c.checkLinks()
+	c.checkReachability()
+	return c.report
+}
+
+type as struct {
+	file string
+	raw  common.RawBytes
+	topo *topology.Topo
+}
+
+type checker struct {
+	ases map[addr.IA]*as
+	ias  []addr.IA
+	// pairs maps each interface to the remote interface it was matched with.
+	pairs map[Interface]Interface
+	// parents contains the parents of each AS, cores the core neighbors of
+	// each core AS. Only links with matching link types are included.
+	parents map[addr.IA][]addr.IA
+	cores   map[addr.IA][]addr.IA
+	report  *Report
+}
+
+func (c *checker) add(kind string, file string, intf Interface, format string,
+	args ...interface{}) {
+
+	c.report.Findings = append(c.report.Findings, Finding{
+		Kind: kind,
+		IA:   intf.IA,
+		IFID: intf.IFID,
+		File: file,
+		Msg:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) load(srcs []Source) {
+	for _, src := range srcs {
+		topo, err := topology.Load(src.Raw)
+		if err != nil {
+			c.add(KindInvalidTopology, src.File, Interface{}, "%s", err)
+			continue
+		}
+		ia := topo.ISD_AS
+		if prev, ok := c.ases[ia]; ok {
+			if !bytes.Equal(prev.raw, src.Raw) {
+				c.add(KindInconsistentCopies, src.File, Interface{IA: ia},
+					"Topology differs from %s", prev.file)
+			}
+			continue
+		}
+		c.ases[ia] = &as{file: src.File, raw: src.Raw, topo: topo}
+		c.ias = append(c.ias, ia)
+		// Load succeeded, so the raw topology is valid as well.
+		raw, _ := topology.LoadRaw(src.Raw)
+		c.checkDuplicateIFIDs(src.File, ia, raw)
+	}
+	sort.Slice(c.ias, func(i, j int) bool { return c.ias[i].IAInt() < c.ias[j].IAInt() })
+	c.report.ASes = c.ias
+}
+
+// checkDuplicateIFIDs reports interface IDs that are used by multiple border
+// routers. Only one of them ends up in the interface map of the topology.
+func (c *checker) checkDuplicateIFIDs(file string, ia addr.IA, raw *topology.RawTopo) {
+	owners := make(map[common.IFIDType][]string)
+	var ifids []common.IFIDType
+	for name, br := range raw.BorderRouters {
+		for ifid := range br.Interfaces {
+			if len(owners[ifid]) == 0 {
+				ifids = append(ifids, ifid)
+			}
+			owners[ifid] = append(owners[ifid], name)
+		}
+	}
+	sortIFIDs(ifids)
+	for _, ifid := range ifids {
+		if brs := owners[ifid]; len(brs) > 1 {
+			sort.Strings(brs)
+			c.add(KindDuplicateIFID, file, Interface{IA: ia, IFID: ifid},
+				"Interface used by multiple border routers: %v", brs)
+		}
+	}
+}
+
+func (c *checker) checkLinks() {
+	var intfs []Interface
+	for _, ia := range c.ias {
+		ifInfos := c.ases[ia].topo.IFInfoMap
+		for _, ifid := range sortedIFIDs(ifInfos) {
+			local := Interface{IA: ia, IFID: ifid}
+			if remote, ok := c.match(local); ok {
+				c.pairs[local] = remote
+				intfs = append(intfs, local)
+			}
+		}
+	}
+	for _, local := range intfs {
+		remote := c.pairs[local]
+		// If the remote interface has no match, it was reported already.
+		back, ok := c.pairs[remote]
+		if !ok {
+			continue
+		}
+		if back != local {
+			c.add(KindAmbiguousLink, c.ases[local.IA].file, local,
+				"Remote interface %s is matched with %s", remote, back)
+			continue
+		}
+		if local.less(remote) {
+			c.checkLink(local, remote)
+		}
+	}
+}
+
+// match returns the remote interface of local. If no unique remote interface
+// exists, a finding is added and false is returned.
+func (c *checker) match(local Interface) (Interface, bool) {
+	file := c.ases[local.IA].file
+	info := c.ifInfo(local)
+	remoteAS, ok := c.ases[info.ISD_AS]
+	if !ok {
+		c.add(KindUnknownAS, file, local, "Remote AS %s not found", info.ISD_AS)
+		return Interface{}, false
+	}
+	var candidates, matches []common.IFIDType
+	for ifid, rinfo := range remoteAS.topo.IFInfoMap {
+		if rinfo.ISD_AS != local.IA {
+			continue
+		}
+		candidates = append(candidates, ifid)
+		if overlaysMatch(info, rinfo) {
+			matches = append(matches, ifid)
+		}
+	}
+	switch {
+	case len(matches) == 1:
+		return Interface{IA: info.ISD_AS, IFID: matches[0]}, true
+	case len(matches) == 0 && len(candidates) == 1:
+		// The overlay mismatch is reported when checking the link.
+		return Interface{IA: info.ISD_AS, IFID: candidates[0]}, true
+	case len(candidates) == 0:
+		c.add(KindDanglingInterface, file, local,
+			"No interface of %s points back to %s", info.ISD_AS, local.IA)
+	default:
+		sortIFIDs(candidates)
+		c.add(KindAmbiguousLink, file, local,
+			"No unique remote interface, candidates in %s: %v", info.ISD_AS, candidates)
+	}
+	return Interface{}, false
+}
+
+func (c *checker) checkLink(a, b Interface) {
+	c.report.Links = append(c.report.Links, Link{A: a, B: b})
+	file := c.ases[a.IA].file
+	aInfo, bInfo := c.ifInfo(a), c.ifInfo(b)
+	// Overlay information is optional, e.g., in stripped endhost topologies.
+	if (aInfo.Remote != nil || bInfo.Remote != nil) && !overlaysMatch(aInfo, bInfo) {
+		c.add(KindOverlayMismatch, file, a,
+			"Overlay %s (local %s, remote %s) does not match %s: %s (local %s, remote %s)",
+			aInfo.Overlay, publicOverlay(aInfo), aInfo.Remote,
+			b, bInfo.Overlay, publicOverlay(bInfo), bInfo.Remote)
+	}
+	if reverseLinkType(aInfo.LinkType) != bInfo.LinkType {
+		c.add(KindLinkTypeMismatch, file, a, "Link type %s does not match %s: %s",
+			aInfo.LinkType, b, bInfo.LinkType)
+	} else {
+		switch aInfo.LinkType {
+		case proto.LinkType_parent:
+			c.parents[a.IA] = append(c.parents[a.IA], b.IA)
+		case proto.LinkType_child:
+			c.parents[b.IA] = append(c.parents[b.IA], a.IA)
+		case proto.LinkType_core:
+			c.cores[a.IA] = append(c.cores[a.IA], b.IA)
+			c.cores[b.IA] = append(c.cores[b.IA], a.IA)
+		}
+	}
+	if aInfo.MTU != bInfo.MTU {
+		c.add(KindMTUMismatch, file, a, "MTU %d does not match %s: %d",
+			aInfo.MTU, b, bInfo.MTU)
+	}
+}
+
+// checkReachability reports ASes that can not be reached from the core. The
+// core is the set of core ASes connected to the first core AS via core links.
+// A non-core AS is reachable if one of its parents in the same ISD is.
+func (c *checker) checkReachability() {
+	reachable := make(map[addr.IA]bool)
+	var root addr.IA
+	for _, ia := range c.ias {
+		if c.ases[ia].topo.Core {
+			root = ia
+			break
+		}
+	}
+	if !root.IsZero() {
+		queue := []addr.IA{root}
+		reachable[root] = true
+		for len(queue) > 0 {
+			ia := queue[0]
+			queue = queue[1:]
+			for _, n := range c.cores[ia] {
+				if !reachable[n] {
+					reachable[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, ia := range c.ias {
+			if reachable[ia] || c.ases[ia].topo.Core {
+				continue
+			}
+			for _, p := range c.parents[ia] {
+				if p.I == ia.I && reachable[p] {
+					reachable[ia] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	for _, ia := range c.ias {
+		if reachable[ia] {
+			continue
+		}
+		switch {
+		case root.IsZero():
+			c.add(KindUnreachableAS, c.ases[ia].file, Interface{IA: ia}, "No core AS found")
+		case c.ases[ia].topo.Core:
+			c.add(KindUnreachableAS, c.ases[ia].file, Interface{IA: ia},
+				"Core AS not connected to core AS %s", root)
+		default:
+			c.add(KindUnreachableAS, c.ases[ia].file, Interface{IA: ia},
+				"No path to a core AS of ISD %d via parent links", ia.I)
+		}
+	}
+}
+
+func (c *checker) ifInfo(intf Interface) topology.IFInfo {
+	return c.ases[intf.IA].topo.IFInfoMap[intf.IFID]
+}
+
+// overlaysMatch returns whether the public overlay address of each interface
+// is the remote overlay address of the other one.
+func overlaysMatch(a, b topology.IFInfo) bool {
+	if a.Local == nil || b.Local == nil || a.Remote == nil || b.Remote == nil {
+		return false
+	}
+	return a.Overlay == b.Overlay && publicOverlay(a).Equal(b.Remote) &&
+		publicOverlay(b).Equal(a.Remote)
+}
+
+func publicOverlay(info topology.IFInfo) *overlay.OverlayAddr {
+	if info.Local == nil {
+		return nil
+	}
+	return info.Local.PublicOverlay(info.Overlay)
+}
+
+func reverseLinkType(t proto.LinkType) proto.LinkType {
+	switch t {
+	case proto.LinkType_parent:
+		return proto.LinkType_child
+	case proto.LinkType_child:
+		return proto.LinkType_parent
+	}
+	return t
+}
+
+func sortedIFIDs(m topology.IfInfoMap) []common.IFIDType {
+	var ifids []common.IFIDType
+	for ifid := range m {
+		ifids = append(ifids, ifid)
+	}
+	sortIFIDs(ifids)
+	return ifids
+}
+
+func sortIFIDs(ifids []common.IFIDType) {
+	sort.Slice(ifids, func(i, j int) bool { return ifids[i] < ifids[j] })
+}
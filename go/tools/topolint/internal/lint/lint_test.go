@@ -0,0 +1,198 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/topology"
+)
+
+type testIntf struct {
+	br     string
+	ifid   common.IFIDType
+	ia     string
+	linkTo string
+	local  int
+	remote int
+	mtu    int
+}
+
+// testTopo returns the topology of ia. The public and remote overlay
+// addresses of the interfaces are 127.0.0.1 with the given ports.
+func testTopo(ia string, core bool, intfs ...testIntf) *topology.RawTopo {
+	rt := &topology.RawTopo{
+		ISD_AS:        ia,
+		Overlay:       "UDP/IPv4",
+		MTU:           1472,
+		Core:          core,
+		BorderRouters: make(map[string]*topology.RawBRInfo),
+	}
+	for _, intf := range intfs {
+		br, ok := rt.BorderRouters[intf.br]
+		if !ok {
+			br = &topology.RawBRInfo{
+				InternalAddrs: topology.RawBRAddrMap{"IPv4": &topology.RawOverlayBind{
+					PublicOverlay: topology.RawAddrOverlay{Addr: "127.0.0.1"},
+				}},
+				CtrlAddr: topology.RawAddrMap{"IPv4": &topology.RawPubBindOverlay{
+					Public: topology.RawAddrPortOverlay{
+						RawAddrPort: topology.RawAddrPort{Addr: "127.0.0.1", L4Port: 30042},
+					},
+				}},
+				Interfaces: make(map[common.IFIDType]*topology.RawBRIntf),
+			}
+			rt.BorderRouters[intf.br] = br
+		}
+		br.Interfaces[intf.ifid] = &topology.RawBRIntf{
+			Overlay: "UDP/IPv4",
+			PublicOverlay: &topology.RawAddrOverlay{
+				Addr: "127.0.0.1", OverlayPort: intf.local},
+			RemoteOverlay: &topology.RawAddrOverlay{
+				Addr: "127.0.0.1", OverlayPort: intf.remote},
+			ISD_AS: intf.ia,
+			LinkTo: intf.linkTo,
+			MTU:    intf.mtu,
+		}
+	}
+	return rt
+}
+
+// testTopos returns a core AS 110 with child 111, which has child 112.
+func testTopos() []*topology.RawTopo {
+	return []*topology.RawTopo{
+		testTopo("1-ff00:0:110", true,
+			testIntf{"br1", 1, "1-ff00:0:111", "CHILD", 50000, 50001, 1472}),
+		testTopo("1-ff00:0:111", false,
+			testIntf{"br1", 1, "1-ff00:0:110", "PARENT", 50001, 50000, 1472},
+			testIntf{"br1", 2, "1-ff00:0:112", "CHILD", 50002, 50003, 1472}),
+		testTopo("1-ff00:0:112", false,
+			testIntf{"br1", 7, "1-ff00:0:111", "PARENT", 50003, 50002, 1472}),
+	}
+}
+
+func sources(t *testing.T, rts ...*topology.RawTopo) []Source {
+	var srcs []Source
+	for i, rt := range rts {
+		b, err := json.Marshal(rt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		srcs = append(srcs, Source{File: fmt.Sprintf("topo%d.json", i), Raw: b})
+	}
+	return srcs
+}
+
+func kinds(r *Report) []string {
+	var ks []string
+	for _, f := range r.Findings {
+		ks = append(ks, f.Kind)
+	}
+	return ks
+}
+
+func TestCheck(t *testing.T) {
+	Convey("Check", t, func() {
+		rts := testTopos()
+		intf := func(as int, br string, ifid common.IFIDType) *topology.RawBRIntf {
+			return rts[as].BorderRouters[br].Interfaces[ifid]
+		}
+		Convey("Consistent topologies have no findings", func() {
+			r := Check(sources(t, rts...))
+			So(r.Findings, ShouldBeEmpty)
+			So(r.ASes, ShouldHaveLength, 3)
+			So(r.Links, ShouldHaveLength, 2)
+			So(r.Links[1].A.String(), ShouldEqual, "1-ff00:0:111#2")
+			So(r.Links[1].B.String(), ShouldEqual, "1-ff00:0:112#7")
+		})
+		Convey("Identical copies are ignored", func() {
+			r := Check(sources(t, append(rts, rts[0])...))
+			So(r.Findings, ShouldBeEmpty)
+		})
+		Convey("Differing copies are reported", func() {
+			srcs := sources(t, rts...)
+			rts[0].TTL = 10
+			r := Check(append(srcs, sources(t, rts[0])...))
+			So(kinds(r), ShouldResemble, []string{KindInconsistentCopies})
+		})
+		Convey("Invalid topologies are reported", func() {
+			srcs := append(sources(t, rts...), Source{File: "bad.json", Raw: []byte("{")})
+			r := Check(srcs)
+			So(kinds(r), ShouldResemble, []string{KindInvalidTopology})
+			So(r.Findings[0].File, ShouldEqual, "bad.json")
+		})
+		Convey("MTU mismatches are reported", func() {
+			intf(1, "br1", 2).MTU = 1280
+			r := Check(sources(t, rts...))
+			So(kinds(r), ShouldResemble, []string{KindMTUMismatch})
+			So(r.Findings[0].IFID, ShouldEqual, 2)
+		})
+		Convey("Overlay mismatches are reported", func() {
+			intf(2, "br1", 7).RemoteOverlay.OverlayPort = 40000
+			r := Check(sources(t, rts...))
+			So(kinds(r), ShouldResemble, []string{KindOverlayMismatch})
+		})
+		Convey("Link type mismatches are reported", func() {
+			intf(2, "br1", 7).LinkTo = "CHILD"
+			r := Check(sources(t, rts...))
+			So(kinds(r), ShouldResemble,
+				[]string{KindLinkTypeMismatch, KindUnreachableAS})
+			So(r.Findings[1].IA.String(), ShouldEqual, "1-ff00:0:112")
+		})
+		Convey("Dangling interfaces are reported", func() {
+			delete(rts[2].BorderRouters["br1"].Interfaces, 7)
+			r := Check(sources(t, rts...))
+			So(kinds(r), ShouldResemble,
+				[]string{KindDanglingInterface, KindUnreachableAS})
+		})
+		Convey("Interfaces to unknown ASes are reported", func() {
+			r := Check(sources(t, rts[:2]...))
+			So(kinds(r), ShouldResemble, []string{KindUnknownAS})
+		})
+		Convey("Ambiguous links are reported", func() {
+			rts[1].BorderRouters["br1"].Interfaces[3] = &topology.RawBRIntf{
+				Overlay:       "UDP/IPv4",
+				PublicOverlay: &topology.RawAddrOverlay{Addr: "127.0.0.1", OverlayPort: 50004},
+				RemoteOverlay: &topology.RawAddrOverlay{Addr: "127.0.0.1", OverlayPort: 50005},
+				ISD_AS:        "1-ff00:0:112",
+				LinkTo:        "CHILD",
+				MTU:           1472,
+			}
+			r := Check(sources(t, rts...))
+			So(kinds(r), ShouldResemble, []string{KindAmbiguousLink})
+			So(r.Findings[0].IFID, ShouldEqual, 3)
+			intf(2, "br1", 7).RemoteOverlay.OverlayPort = 40000
+			r = Check(sources(t, rts...))
+			So(kinds(r), ShouldResemble, []string{KindAmbiguousLink, KindUnreachableAS})
+			So(r.Findings[0].IA.String(), ShouldEqual, "1-ff00:0:112")
+		})
+		Convey("Duplicate interface IDs are reported", func() {
+			rts[1].BorderRouters["br2"] = rts[1].BorderRouters["br1"]
+			r := Check(sources(t, rts...))
+			So(kinds(r), ShouldResemble, []string{KindDuplicateIFID, KindDuplicateIFID})
+		})
+		Convey("Core ASes not connected to the core are reported", func() {
+			rts = append(rts, testTopo("2-ff00:0:210", true))
+			r := Check(sources(t, rts...))
+			So(kinds(r), ShouldResemble, []string{KindUnreachableAS})
+			So(r.Findings[0].IA.String(), ShouldEqual, "2-ff00:0:210")
+		})
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// topolint checks the topologies of a multi-AS deployment for consistency.
+//
+// It reads all topology files in a directory tree, builds the inter-AS graph
+// and reports mismatching link ends, dangling interfaces, duplicate interface
+// IDs and unreachable ASes. It exits with status 1 if problems were found.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/scionproto/scion/go/lib/env"
+	"github.com/scionproto/scion/go/tools/topolint/internal/lint"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+var (
+	dir     = flag.String("dir", "", "Directory tree containing the topologies. Required.")
+	name    = flag.String("name", "topology.json", "File name of the topologies.")
+	format  = flag.String("format", formatText, "Output format: "+formatText+" or "+formatJSON)
+	version = flag.Bool("version", false, "Output version information and exit.")
+)
+
+func main() {
+	flag.Parse()
+	if *version {
+		fmt.Print(env.VersionInfo())
+		os.Exit(0)
+	}
+	if *dir == "" {
+		fmt.Fprintf(os.Stderr, "You must specify a topology directory.\n")
+		os.Exit(2)
+	}
+	if *format != formatText && *format != formatJSON {
+		fmt.Fprintf(os.Stderr, "Unknown output format: %s\n", *format)
+		os.Exit(2)
+	}
+	srcs, err := lint.LoadDir(*dir, *name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading topologies: %s\n", err)
+		os.Exit(2)
+	}
+	if len(srcs) == 0 {
+		fmt.Fprintf(os.Stderr, "No topologies found in %s\n", *dir)
+		os.Exit(2)
+	}
+	report := lint.Check(srcs)
+	if *format == formatJSON {
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not marshal report to JSON: %s\n", err)
+			os.Exit(2)
+		}
+		fmt.Println(string(b))
+	} else {
+		for _, f := range report.Findings {
+			fmt.Println(f)
+		}
+		fmt.Printf("Checked %d ASes and %d links: %d problems found.\n",
+			len(report.ASes), len(report.Links), len(report.Findings))
+	}
+	if len(report.Findings) > 0 {
+		os.Exit(1)
+	}
+}
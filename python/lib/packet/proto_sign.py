@@ -36,6 +36,8 @@ class ProtoSignError(SCIONBaseError):
 class ProtoSignType(object):
     NONE = "none"
     ED25519 = "ed25519"
+    ECDSA_P256 = "ecdsaP256"
+    ECDSA_P384 = "ecdsaP384"
 
 
 class ProtoSign(Cerealizable):
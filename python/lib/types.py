@@ -216,6 +216,8 @@ class SCIONDMsgType(TypeBase):
     DRKEY_REPLY = "drkeyReply"
     SEGTYPEHOP_REQUEST = "segTypeHopReq"
     SEGTYPEHOP_REPLY = "segTypeHopReply"
+    PATH_SUBSCRIBE_REQUEST = "pathSubscribeReq"
+    PATH_UPDATE = "pathUpdate"
 
 
 #######################